@@ -15,38 +15,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/bookings": {
+        "/admin/payouts": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Adds a new booking",
+                "description": "Pays every provider who is owed money their whole balance, as one batch. Each payout is posted\nto the ledger; the batch is then exported as CSV for the bank transfers. Providers whose\nbalance is negative, after refunds of bookings they were already paid for, are left out until\ntheir earnings make up for it.",
                 "tags": [
-                    "booking"
-                ],
-                "summary": "Creates booking",
-                "parameters": [
-                    {
-                        "description": "New booking",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.BookingCreate"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Creates payout batch",
                 "responses": {
                     "201": {
-                        "description": "Booking created",
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutBatch"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid data format",
+                    "409": {
+                        "description": "No provider is owed money",
                         "schema": {
                             "type": "string"
                         }
@@ -60,39 +55,87 @@ const docTemplate = `{
                 }
             }
         },
-        "/bookings/all": {
+        "/admin/payouts/{id}/export": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches bookings",
+                "description": "Exports the payouts of a batch as CSV, one row per provider with their company name. The\ntransaction ID is the payout's ledger transaction, for reconciliation.",
+                "produces": [
+                    "text/csv"
+                ],
                 "tags": [
-                    "booking"
+                    "admin"
                 ],
-                "summary": "Fetches bookings",
+                "summary": "Exports payout batch",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Payout batch ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payouts",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Payout batch not found",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/providers/export": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Streams every provider as CSV, in the column layout accepted by the import, or as NDJSON.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Exports providers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (default) or ndjson",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Providers",
                         "schema": {
-                            "$ref": "#/definitions/bookings.BookingsList"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -101,6 +144,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -110,32 +159,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/bookings/{id}": {
-            "get": {
+        "/admin/providers/import": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets booking",
+                "description": "Creates providers from a CSV file with a header row or from NDJSON, one provider per line.\nCSV columns: user_id, company_name, description, services, availability, average_rating,\naddress, city, country, latitude, longitude; list columns are separated by \"|\".\nEvery row is validated like a single provider registration and reported on its own,\nwith field errors keyed by the JSON path of the field.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
                 "tags": [
-                    "booking"
+                    "admin"
                 ],
-                "summary": "Gets booking",
+                "summary": "Imports providers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Booking ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "csv or ndjson, taken from Content-Type by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the rows",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/bookings.Booking"
+                            "$ref": "#/definitions/models.ImportReport"
                         }
                     },
                     "400": {
@@ -144,48 +202,50 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/providers/{id}/commission": {
             "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates booking",
+                "description": "Sets the share of a provider's payments the platform keeps, between 0 and 1. It applies to\npayments posted from now on; payments posted before, and their refunds, keep their rate.",
                 "tags": [
-                    "booking"
+                    "admin"
                 ],
-                "summary": "Updates booking",
+                "summary": "Sets provider commission",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Booking ID",
+                        "description": "Provider ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New booking data",
+                        "description": "Commission rate",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.BookingUpdate"
+                            "$ref": "#/definitions/models.CommissionUpdate"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Booking updated",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Commission"
                         }
                     },
                     "400": {
@@ -194,6 +254,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -203,30 +269,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/bookings/{id}/cancel": {
-            "put": {
+        "/admin/services/export": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Cancels booking",
+                "description": "Streams every service as CSV, in the column layout accepted by the import, or as NDJSON.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
                 "tags": [
-                    "booking"
+                    "admin"
                 ],
-                "summary": "Cancels booking",
+                "summary": "Exports services",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Booking ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "csv (default) or ndjson",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Booking canceled",
+                        "description": "Services",
                         "schema": {
                             "type": "string"
                         }
@@ -237,6 +306,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -246,34 +321,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications": {
+        "/admin/services/import": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Adds a new notification",
+                "description": "Creates services from a CSV file with a header row or from NDJSON, one service per line.\nCSV columns: name, description, price, duration.\nEvery row is validated like a single service creation and reported on its own.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
                 "tags": [
-                    "notification"
+                    "admin"
                 ],
-                "summary": "Creates notification",
+                "summary": "Imports services",
                 "parameters": [
                     {
-                        "description": "Receiver ID, Title and Message",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/notifications.NewNotification"
-                        }
+                        "type": "string",
+                        "description": "csv or ndjson, taken from Content-Type by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the rows",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Notification created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ImportReport"
                         }
                     },
                     "400": {
@@ -282,8 +364,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Server error while processing request",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "string"
                         }
@@ -291,32 +373,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/notifications/{id}": {
-            "get": {
+        "/batch": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets notification",
+                "description": "Runs each sub-request through the API with the caller's credentials, as if it\nhad been sent on its own, and returns the status and body of every one in order.\nPaths are relative to the API base path, e.g. /providers/register.\nWith stop_on_error, sub-requests that have not started when one fails are skipped.",
                 "tags": [
-                    "notification"
+                    "batch"
                 ],
-                "summary": "Gets notification",
+                "summary": "Runs several requests at once",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Notification ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Sub-requests",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/notifications.Notification"
+                            "$ref": "#/definitions/models.BatchResp"
                         }
                     },
                     "400": {
@@ -324,48 +408,48 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    "500": {
-                        "description": "Server error while processing request",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
             }
         },
-        "/payments": {
+        "/bookings": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Adds a new payment",
+                "description": "Adds a new booking",
                 "tags": [
-                    "payment"
+                    "booking"
                 ],
-                "summary": "Creates payment",
+                "summary": "Creates booking",
                 "parameters": [
                     {
-                        "description": "New payment",
+                        "description": "New booking",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payments.NewPayment"
+                            "$ref": "#/definitions/models.BookingCreate"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Payment created",
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.BookingResp"
                         }
                     },
                     "400": {
-                        "description": "Invalid data format",
+                        "description": "Invalid data format or mismatching total price",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Requested time is not available",
                         "schema": {
                             "type": "string"
                         }
@@ -379,39 +463,82 @@ const docTemplate = `{
                 }
             }
         },
-        "/payments/all": {
+        "/bookings/all": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches payments",
+                "description": "Fetches bookings",
                 "tags": [
-                    "payment"
+                    "booking"
                 ],
-                "summary": "Fetches payments",
+                "summary": "Fetches bookings",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Page number",
                         "name": "page",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "integer",
                         "description": "Number of items per page",
                         "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. status:eq:pending,scheduled_time:gte:2026-10-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: provider, service, payments",
+                        "name": "expand",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/payments.PaymentsList"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BookingExpanded"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -425,36 +552,48 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "List too long to filter in the gateway",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/payments/{id}": {
+        "/bookings/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets payment",
+                "description": "Gets booking",
                 "tags": [
-                    "payment"
+                    "booking"
                 ],
-                "summary": "Gets payment",
+                "summary": "Gets booking",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Payment ID",
+                        "description": "Booking ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: provider, service, payments",
+                        "name": "expand",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/payments.Payment"
+                            "$ref": "#/definitions/models.BookingExpanded"
                         }
                     },
                     "400": {
@@ -463,6 +602,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -470,41 +615,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/providers/all": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches providers",
+                "description": "Updates booking",
                 "tags": [
-                    "provider"
+                    "booking"
                 ],
-                "summary": "Fetches providers",
+                "summary": "Updates booking",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "description": "New booking data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BookingUpdate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/providers.ProvidersList"
+                            "$ref": "#/definitions/models.BookingResp"
                         }
                     },
                     "400": {
@@ -513,6 +658,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Booking can no longer be modified, requested time is not available or a payment is in progress",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -522,34 +679,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/providers/register": {
-            "post": {
+        "/bookings/{id}/cancel": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Adds a new provider",
+                "description": "Cancels booking",
                 "tags": [
-                    "provider"
+                    "booking"
                 ],
-                "summary": "Creates provider",
+                "summary": "Cancels booking",
                 "parameters": [
                     {
-                        "description": "New provider",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ProviderCreate"
-                        }
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Booking canceled",
                         "schema": {
-                            "$ref": "#/definitions/providers.CreateResp"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -558,6 +713,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status transition",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -567,37 +734,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/providers/search": {
-            "get": {
+        "/bookings/{id}/complete": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Searches providers",
+                "description": "Moves an in progress booking to completed",
                 "tags": [
-                    "provider"
+                    "booking"
                 ],
-                "summary": "Searches providers",
+                "summary": "Completes booking",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Company name",
-                        "name": "company_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Average rating",
-                        "name": "average_rating",
-                        "in": "query"
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Booking completed",
                         "schema": {
-                            "$ref": "#/definitions/providers.SearchResp"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -606,6 +768,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status transition",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -615,22 +789,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/providers/{id}": {
-            "get": {
+        "/bookings/{id}/confirm": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets provider",
+                "description": "Moves a pending booking to confirmed",
                 "tags": [
-                    "provider"
+                    "booking"
                 ],
-                "summary": "Gets provider",
+                "summary": "Confirms booking",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID",
+                        "description": "Booking ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -638,9 +812,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Booking confirmed",
                         "schema": {
-                            "$ref": "#/definitions/providers.Provider"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -649,6 +823,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status transition",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -656,41 +842,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/bookings/{id}/details": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates provider",
+                "description": "Gets a booking together with its service, provider, payments and review.\nParts that fail to load are left empty and listed in \"unavailable\".",
                 "tags": [
-                    "provider"
+                    "booking"
                 ],
-                "summary": "Updates provider",
+                "summary": "Gets booking details",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID",
+                        "description": "Booking ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated provider",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ProviderUpdate"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/providers.UpdateResp"
+                            "$ref": "#/definitions/models.BookingDetails"
                         }
                     },
                     "400": {
@@ -699,6 +878,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -706,32 +891,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/bookings/{id}/invoice": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes provider",
+                "description": "Gets the invoice of a completed booking as JSON, an HTML page or a PDF document. The invoice\nis issued the first time it is requested, with the provider's next sequential number, and keeps\nit after that. It lists the charges of the booking with their taxes, and its payments.",
+                "produces": [
+                    "application/json",
+                    "text/html",
+                    "application/pdf"
+                ],
                 "tags": [
-                    "provider"
+                    "booking"
                 ],
-                "summary": "Deletes provider",
+                "summary": "Gets booking invoice",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID",
+                        "description": "Booking ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Output format: json (default), html or pdf",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Provider deleted",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/invoice.Invoice"
                         }
                     },
                     "400": {
@@ -740,6 +938,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Booking is not completed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -749,38 +959,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/reviews": {
+        "/bookings/{id}/invoice/send": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Creates a new review",
+                "description": "Sends the customer of a completed booking a notification with its invoice, issuing the invoice\nif it was not yet.",
                 "tags": [
-                    "review"
+                    "booking"
                 ],
-                "summary": "Create review",
+                "summary": "Sends booking invoice",
                 "parameters": [
                     {
-                        "description": "Review",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ReviewCreate"
-                        }
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Review created",
+                    "200": {
+                        "description": "Invoice sent",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid data format",
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Booking is not completed",
                         "schema": {
                             "type": "string"
                         }
@@ -794,39 +1008,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/reviews/all": {
-            "get": {
+        "/bookings/{id}/start": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches reviews",
+                "description": "Moves a confirmed booking to in progress",
                 "tags": [
-                    "review"
+                    "booking"
                 ],
-                "summary": "Fetches reviews",
+                "summary": "Starts booking",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Booking started",
                         "schema": {
-                            "$ref": "#/definitions/reviews.ReviewsList"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -835,6 +1042,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid status transition",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -844,32 +1063,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/reviews/{id}": {
-            "get": {
+        "/graphql": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets review",
+                "description": "Runs a GraphQL query or mutation. Every field applies the Casbin\npolicy of the REST route that serves the same data.",
                 "tags": [
-                    "review"
+                    "graphql"
                 ],
-                "summary": "Gets review",
+                "summary": "Runs a GraphQL query",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Review ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Query, operation name and variables",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GraphQLRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/reviews.Review"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -877,49 +1099,38 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    "500": {
-                        "description": "Server error while processing request",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/notifications": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates review",
+                "description": "Adds a new notification",
                 "tags": [
-                    "review"
+                    "notification"
                 ],
-                "summary": "Update review",
+                "summary": "Creates notification",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Review ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Review",
+                        "description": "Receiver ID, Title and Message",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ReviewUpdate"
+                            "$ref": "#/definitions/models.NotificationCreate"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Notification created",
                         "schema": {
-                            "$ref": "#/definitions/reviews.UpdateResp"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -935,22 +1146,58 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/notifications/read-all": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes review",
+                "description": "Marks every notification of the current user as read",
                 "tags": [
-                    "review"
+                    "notification"
                 ],
-                "summary": "Delete review",
+                "summary": "Marks all notifications as read",
+                "responses": {
+                    "200": {
+                        "description": "Notifications marked as read",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets a notification of the current user",
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Gets notification",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Review ID",
+                        "description": "Notification ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -958,9 +1205,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Review deleted successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/notifications.Notification"
                         }
                     },
                     "400": {
@@ -969,6 +1216,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -976,36 +1229,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/services": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Adds a new service",
+                "description": "Deletes a notification of the current user",
                 "tags": [
-                    "service"
+                    "notification"
                 ],
-                "summary": "Creates service",
+                "summary": "Deletes notification",
                 "parameters": [
                     {
-                        "description": "New service",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/services.NewService"
-                        }
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Notification deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/services.CreateResp"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1014,6 +1263,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
@@ -1023,43 +1278,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/services/all": {
-            "get": {
+        "/notifications/{id}/read": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches services",
+                "description": "Marks a notification of the current user as read",
                 "tags": [
-                    "service"
+                    "notification"
                 ],
-                "summary": "Fetches services",
+                "summary": "Marks notification as read",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items per page",
-                        "name": "limit",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Notification marked as read",
                         "schema": {
-                            "$ref": "#/definitions/services.ServicesList"
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid pagination parameter",
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
                             "type": "string"
                         }
@@ -1073,23 +1327,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/services/popular": {
-            "get": {
+        "/payments": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets popular services",
+                "description": "Starts the payment of a booking of the current user: creates a payment intent with the\nprocessor for the booking's price. A booking has one open intent at a time; while it is\npending or processing it is returned again. Confirm it with a payment method to pay.",
                 "tags": [
-                    "service"
+                    "payment"
+                ],
+                "summary": "Creates payment",
+                "parameters": [
+                    {
+                        "description": "Booking to pay",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentCreate"
+                        }
+                    }
                 ],
-                "summary": "Gets popular services",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "The open intent of the booking",
                         "schema": {
-                            "$ref": "#/definitions/services.SearchResp"
+                            "$ref": "#/definitions/models.PaymentIntent"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentIntent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Booking cannot be paid",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -1097,39 +1386,63 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Payments are not configured",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/services/search": {
+        "/payments/all": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Searches services",
+                "description": "Fetches payments",
                 "tags": [
-                    "service"
+                    "payment"
                 ],
-                "summary": "Searches services",
+                "summary": "Fetches payments",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Name",
-                        "name": "name",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
-                        "type": "number",
-                        "description": "Price",
-                        "name": "price",
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Duration",
-                        "name": "duration",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. amount:gte:50,created_at:gte:2026-10-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
                         "in": "query"
                     }
                 ],
@@ -1137,7 +1450,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/services.SearchResp"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/payments.Payment"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -1151,26 +1479,32 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "List too long to filter in the gateway",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/services/{id}": {
+        "/payments/intents/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Gets service",
+                "description": "Gets a payment intent of the current user",
                 "tags": [
-                    "service"
+                    "payment"
                 ],
-                "summary": "Gets service",
+                "summary": "Gets payment intent",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Service ID",
+                        "description": "Payment intent ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1180,11 +1514,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/services.Service"
+                            "$ref": "#/definitions/models.PaymentIntent"
                         }
                     },
-                    "400": {
-                        "description": "Invalid data format",
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment intent not found",
                         "schema": {
                             "type": "string"
                         }
@@ -1196,33 +1536,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/payments/intents/{id}/confirm": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates service",
+                "description": "Pays a pending payment intent of the current user with a payment method tokenized by\nthe processor's client library; card details are never sent to the gateway. The intent\ncomes back succeeded, processing while the processor settles it, or still pending with\na failure message when the method was declined, in which case another one can be tried.\nThe payment is recorded once the intent succeeds or is processing.",
                 "tags": [
-                    "service"
+                    "payment"
                 ],
-                "summary": "Updates service",
+                "summary": "Confirms payment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Service ID",
+                        "description": "Payment intent ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New service data",
+                        "description": "Payment method",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ServiceUpdate"
+                            "$ref": "#/definitions/models.PaymentConfirm"
                         }
                     }
                 ],
@@ -1230,7 +1572,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/services.UpdateResp"
+                            "$ref": "#/definitions/models.PaymentIntent"
                         }
                     },
                     "400": {
@@ -1239,29 +1581,105 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "402": {
+                        "description": "Payment method declined",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentIntent"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment intent not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment intent is not pending",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "503": {
+                        "description": "Payments are not configured",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/payments/webhooks/{provider}": {
+            "post": {
+                "description": "Receives the webhook events of the payment processor, e.g. stripe. It needs no token:\neach event is checked against the processor's signature, and events it already received\nare acknowledged without being handled again. Events about payment intents move the\nintent on, and a payment that settles after processing is published as payment_updated.\nEvents about unknown intents and other events are acknowledged and ignored.",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Receives payment processor events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment processor",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event received",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown payment processor",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes service",
+                "description": "Gets payment",
                 "tags": [
-                    "service"
+                    "payment"
                 ],
-                "summary": "Deletes service",
+                "summary": "Gets payment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Service ID",
+                        "description": "Payment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1269,10 +1687,68 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Service deleted successfully",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payments.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/refund": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gives back part or all of a payment collected through the processor, with a reason; without an\namount, everything that can still be refunded is. The booking's provider and admins can refund\nthe whole payment. Customers can only get refunds of cancelled bookings, within the cancellation\npolicy: by default a full refund when cancelled more than 24 hours before the booking starts and\n50% after that, or a full one when the provider or an admin cancelled.\nCancelling a paid booking refunds it by the same rules, so this is mostly needed when that failed.\nA refund that would exceed what can be refunded fails with the amount that still can.",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Refunds payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefundCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Refund"
+                        }
                     },
                     "400": {
                         "description": "Invalid data format",
@@ -1280,36 +1756,101 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment cannot be refunded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Server error while processing request",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "502": {
+                        "description": "Processor failed to refund",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Payments are not configured",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/users/profile": {
+        "/providers/all": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves user profile",
+                "description": "Fetches providers",
                 "tags": [
-                    "user"
+                    "provider"
+                ],
+                "summary": "Fetches providers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Gets profile",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.Profile"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/providers.Provider"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "401": {
-                        "description": "Invalid user",
+                    "400": {
+                        "description": "Invalid data format",
                         "schema": {
                             "type": "string"
                         }
@@ -1321,34 +1862,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/providers/register": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates user profile",
+                "description": "Adds a new provider",
                 "tags": [
-                    "user"
+                    "provider"
                 ],
-                "summary": "Updates profile",
+                "summary": "Creates provider",
                 "parameters": [
                     {
-                        "description": "New user data",
+                        "description": "New provider",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserUpdate"
+                            "$ref": "#/definitions/models.ProviderCreate"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/user.UpdateResp"
+                            "$ref": "#/definitions/providers.CreateResp"
                         }
                     },
                     "400": {
@@ -1357,8 +1900,77 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Invalid user",
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Searches providers, optionally within a radius of a point",
+                "tags": [
+                    "provider"
+                ],
+                "summary": "Searches providers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Company name",
+                        "name": "company_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Average rating",
+                        "name": "average_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Customer latitude",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Customer longitude",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Search radius in kilometers, requires lat and lng",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "distance"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProviderSearchResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
                         "schema": {
                             "type": "string"
                         }
@@ -1371,37 +1983,2912 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "bookings.Booking": {
+        },
+        "/providers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets provider",
+                "tags": [
+                    "provider"
+                ],
+                "summary": "Gets provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/providers.Provider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates provider",
+                "tags": [
+                    "provider"
+                ],
+                "summary": "Updates provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated provider",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProviderUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/providers.UpdateResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes provider",
+                "tags": [
+                    "provider"
+                ],
+                "summary": "Deletes provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Provider deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}/bookings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches bookings made with a provider",
+                "tags": [
+                    "booking"
+                ],
+                "summary": "Fetches provider bookings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. status:eq:pending,scheduled_time:gte:2026-10-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: provider, service, payments",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BookingExpanded"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "List too long to filter in the gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}/earnings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sums up what a provider earned by day, week or month, from the ledger: the payments of their\nbookings, the refunds, the platform's commission, what is left to the provider and what was paid\nout to them. Dates are inclusive and in the schedule time zone; by default the range ends today\nand covers 30 days, 12 weeks or 12 months. The balance is what the provider is owed now.",
+                "tags": [
+                    "provider"
+                ],
+                "summary": "Gets provider earnings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default)",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Earnings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches reviews left for a provider",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Fetches provider reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. rating:gte:4,created_at:gte:2026-10-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: booking, provider",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ReviewExpanded"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "List too long to filter in the gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}/slots": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the provider's free time slots for a service on a date. A provider without\nworking hours is open all day, in the gateway's time zone.",
+                "tags": [
+                    "provider"
+                ],
+                "summary": "Gets free slots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service ID",
+                        "name": "service_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SlotsResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new review",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Create review",
+                "parameters": [
+                    {
+                        "description": "Review",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Review created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/all": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches reviews",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Fetches reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. rating:gte:4,created_at:gte:2026-10-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: booking, provider",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ReviewExpanded"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "List too long to filter in the gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets review",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Gets review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: booking, provider",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewExpanded"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates review",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Update review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reviews.UpdateResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes review",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Delete review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a new service",
+                "tags": [
+                    "service"
+                ],
+                "summary": "Creates service",
+                "parameters": [
+                    {
+                        "description": "New service",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/services.CreateResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/all": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches services",
+                "tags": [
+                    "service"
+                ],
+                "summary": "Fetches services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. price:lte:100,duration:lte:60",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/services.Service"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameter",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/popular": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets popular services",
+                "tags": [
+                    "service"
+                ],
+                "summary": "Gets popular services",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.SearchResp"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Searches services",
+                "tags": [
+                    "service"
+                ],
+                "summary": "Searches services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Price",
+                        "name": "price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Duration",
+                        "name": "duration",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.SearchResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets service",
+                "tags": [
+                    "service"
+                ],
+                "summary": "Gets service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates service",
+                "tags": [
+                    "service"
+                ],
+                "summary": "Updates service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New service data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ServiceUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.UpdateResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes service",
+                "tags": [
+                    "service"
+                ],
+                "summary": "Deletes service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pushes booking updates and cancellations of the caller's bookings, as customer or as\nprovider, and notifications of the caller as Server-Sent Events. The event name is\nbooking_updated, booking_cancelled or notification_created and the data is the message\nof the event. Comment lines are sent as heartbeats.\nTo resume after a disconnect, pass the ID of the last event received; the events since\nare sent first. When some of them are no longer kept, a reset event is sent instead and\nthe client should refetch its bookings. A client that falls too far behind gets an\noverflow event and is disconnected, and should reconnect to resume.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Streams the caller's events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Same as Last-Event-ID, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/bookings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches bookings made by the current user",
+                "tags": [
+                    "booking"
+                ],
+                "summary": "Fetches my bookings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. status:eq:pending,scheduled_time:gte:2026-10-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: provider, service, payments",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BookingExpanded"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "List too long to filter in the gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notification-deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches the attempts to deliver the current user's notifications, newest first",
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Fetches my notification deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DeliveryLog"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets the channels the current user's notifications are delivered through besides the inbox",
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Gets my notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the channels the current user's notifications are delivered through besides the inbox.\nChannels are listed by notification type, e.g. booking_confirmed, with \"default\" for the\ntypes not listed. Channels are email, sms, push and webhook; email and SMS go to the\naddress and phone number of the profile.",
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Updates my notification preferences",
+                "parameters": [
+                    {
+                        "description": "Preferences",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferences updated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches notifications sent to the current user, with the number of unread ones",
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Fetches my notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.NotificationListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/notifications.Notification"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/payments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches payments for bookings of the current user",
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Fetches my payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. amount:gte:50,created_at:gte:2026-10-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/payments.Payment"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "List too long to filter in the gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches reviews written by the current user",
+                "tags": [
+                    "review"
+                ],
+                "summary": "Fetches my reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. rating:gte:4,created_at:gte:2026-10-01",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related resources to inline: booking, provider",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ReviewExpanded"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "List too long to filter in the gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/profile": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves user profile",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Gets profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Profile"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates user profile",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Updates profile",
+                "parameters": [
+                    {
+                        "description": "New user data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateResp"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches the webhooks of the current user, newest first",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Fetches my webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Webhook"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Subscribes a URL to events of the current user's bookings, as customer or as provider,\nand of their payments and reviews; an admin's subscriptions get the events of all bookings.\nEvents are booking_created, booking_updated, booking_cancelled, payment_created,\npayment_updated, payment_refunded and review_created. Each delivery is a POST of {id, event, created_at, data}, where data is\nthe message of the event, with the headers X-Webhook-Id, X-Webhook-Event,\nX-Webhook-Timestamp (Unix seconds) and X-Webhook-Signature, which is \"v1=\" followed by the\nhex HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed with the secret. Receivers should reject\ndeliveries whose timestamp is more than a few minutes old. The secret is only returned here.\nDeliveries not answered with a 2xx are retried with exponential backoff, and end up dead\nwhen the attempts run out.",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Creates webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets a webhook of the current user",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Gets webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the URL or the events of a webhook of the current user, or pauses and resumes it.\nThe deliveries of a paused webhook wait until it is resumed.",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Updates webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a webhook of the current user along with its deliveries",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Deletes webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches the deliveries of a webhook of the current user, newest first. Filter by status\ndead to see the deliveries whose attempts ran out.",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Fetches webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status: pending, delivered or dead",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all items",
+                        "name": "include_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ListResp"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.WebhookDelivery"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid data format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets a delivery of a webhook of the current user with the log of its attempts",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Gets webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sends a delivery of a webhook of the current user again, as a new delivery with the same\nID in the body and a fresh set of attempts. Deliveries still pending cannot be redelivered.",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Redelivers webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is still pending",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error while processing request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "bookings.Booking": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/bookings.Location"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "service_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_price": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "bookings.Location": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "invoice.Invoice": {
+            "type": "object",
+            "properties": {
+                "amount_due": {
+                    "type": "number"
+                },
+                "amount_paid": {
+                    "type": "number"
+                },
+                "amount_refunded": {
+                    "type": "number"
+                },
+                "booking_id": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer": {
+                    "$ref": "#/definitions/invoice.Party"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invoice.Line"
+                    }
+                },
+                "location": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invoice.Payment"
+                    }
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "seller": {
+                    "$ref": "#/definitions/invoice.Party"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invoice.TaxLine"
+                    }
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "invoice.Line": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "invoice.Party": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "invoice.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "invoice.TaxLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BatchItem": {
+            "type": "object",
+            "required": [
+                "method",
+                "path"
+            ],
+            "properties": {
+                "body": {
+                    "type": "object"
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "GET",
+                        "POST",
+                        "PUT",
+                        "DELETE"
+                    ]
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchRequest": {
+            "type": "object",
+            "required": [
+                "requests"
+            ],
+            "properties": {
+                "concurrency": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "requests": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.BatchItem"
+                    }
+                },
+                "stop_on_error": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.BatchResp": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchResult"
+                    }
+                }
+            }
+        },
+        "models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "object"
+                },
+                "skipped": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BookingCreate": {
+            "type": "object",
+            "required": [
+                "location",
+                "provider_id",
+                "scheduled_time",
+                "service_id"
+            ],
+            "properties": {
+                "location": {
+                    "$ref": "#/definitions/models.Location"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "service_id": {
+                    "type": "string"
+                },
+                "total_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BookingDetails": {
+            "type": "object",
+            "properties": {
+                "booking": {
+                    "$ref": "#/definitions/bookings.Booking"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/payments.Payment"
+                    }
+                },
+                "provider": {
+                    "$ref": "#/definitions/providers.Provider"
+                },
+                "review": {
+                    "$ref": "#/definitions/reviews.Review"
+                },
+                "service": {
+                    "$ref": "#/definitions/services.Service"
+                },
+                "unavailable": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.BookingExpanded": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/bookings.Location"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/payments.Payment"
+                    }
+                },
+                "provider": {
+                    "$ref": "#/definitions/providers.Provider"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                },
+                "service": {
+                    "$ref": "#/definitions/services.Service"
+                },
+                "service_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_price": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BookingResp": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/pricing.Quote"
+                }
+            }
+        },
+        "models.BookingUpdate": {
+            "type": "object",
+            "properties": {
+                "location": {
+                    "$ref": "#/definitions/models.Location"
+                },
+                "scheduled_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Commission": {
+            "type": "object",
+            "properties": {
+                "provider_id": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CommissionUpdate": {
+            "type": "object",
+            "required": [
+                "rate"
+            ],
+            "properties": {
+                "rate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                }
+            }
+        },
+        "models.DeliveryLog": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Earnings": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "commission_rate": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EarningsPeriod"
+                    }
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/models.EarningsPeriod"
+                }
+            }
+        },
+        "models.EarningsPeriod": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "type": "number"
+                },
+                "gross": {
+                    "type": "number"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "paid_out": {
+                    "type": "number"
+                },
+                "refunded": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GraphQLRequest": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRow"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportRow": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "error": {
                     "type": "string"
                 },
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
-                "location": {
-                    "$ref": "#/definitions/bookings.Location"
+                "line": {
+                    "type": "integer"
                 },
-                "provider_id": {
+                "status": {
                     "type": "string"
-                },
-                "scheduled_time": {
+                }
+            }
+        },
+        "models.ListResp": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "page_info": {
+                    "$ref": "#/definitions/models.PageInfo"
+                }
+            }
+        },
+        "models.Location": {
+            "type": "object",
+            "required": [
+                "address",
+                "city",
+                "country"
+            ],
+            "properties": {
+                "address": {
                     "type": "string"
                 },
-                "service_id": {
+                "city": {
                     "type": "string"
                 },
-                "status": {
+                "country": {
                     "type": "string"
                 },
-                "total_price": {
+                "latitude": {
                     "type": "number"
                 },
-                "updated_at": {
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.NotificationCreate": {
+            "type": "object",
+            "required": [
+                "message",
+                "title",
+                "user_id"
+            ],
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 },
                 "user_id": {
@@ -1409,114 +4896,173 @@ const docTemplate = `{
                 }
             }
         },
-        "bookings.BookingsList": {
+        "models.NotificationListResp": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "page_info": {
+                    "$ref": "#/definitions/models.PageInfo"
+                },
+                "unread_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NotificationPreferences": {
             "type": "object",
+            "required": [
+                "channels",
+                "push_tokens"
+            ],
             "properties": {
-                "bookings": {
+                "channels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "locale": {
+                    "type": "string",
+                    "maxLength": 10,
+                    "minLength": 2
+                },
+                "push_tokens": {
                     "type": "array",
+                    "maxItems": 10,
                     "items": {
-                        "$ref": "#/definitions/bookings.Booking"
+                        "type": "string"
                     }
                 },
-                "limit": {
-                    "type": "integer"
+                "updated_at": {
+                    "type": "string"
                 },
-                "page": {
-                    "type": "integer"
+                "webhook_url": {
+                    "type": "string"
                 }
             }
         },
-        "bookings.Location": {
+        "models.PageInfo": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "has_more": {
+                    "type": "boolean"
                 },
-                "city": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "country": {
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PaymentConfirm": {
+            "type": "object",
+            "required": [
+                "payment_method"
+            ],
+            "properties": {
+                "payment_method": {
                     "type": "string"
-                },
-                "latitude": {
-                    "type": "number"
-                },
-                "longitude": {
-                    "type": "number"
                 }
             }
         },
-        "models.BookingCreate": {
+        "models.PaymentCreate": {
             "type": "object",
             "required": [
-                "location",
-                "provider_id",
-                "scheduled_time",
-                "service_id",
-                "status",
-                "total_price"
+                "booking_id"
             ],
             "properties": {
-                "location": {
-                    "$ref": "#/definitions/models.Location"
+                "booking_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentIntent": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
                 },
-                "provider_id": {
+                "amount_refunded": {
+                    "type": "number"
+                },
+                "booking_id": {
                     "type": "string"
                 },
-                "scheduled_time": {
+                "created_at": {
                     "type": "string"
                 },
-                "service_id": {
+                "currency": {
+                    "type": "string"
+                },
+                "failure_message": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "processor": {
+                    "type": "string"
+                },
+                "processor_intent_id": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
                 },
-                "total_price": {
-                    "type": "number"
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "models.BookingUpdate": {
+        "models.Payout": {
             "type": "object",
             "properties": {
-                "location": {
-                    "$ref": "#/definitions/models.Location"
+                "amount": {
+                    "type": "number"
                 },
-                "scheduled_time": {
+                "currency": {
                     "type": "string"
                 },
-                "status": {
+                "provider_id": {
                     "type": "string"
                 },
-                "total_price": {
-                    "type": "number"
+                "transaction_id": {
+                    "type": "string"
                 }
             }
         },
-        "models.Location": {
+        "models.PayoutBatch": {
             "type": "object",
-            "required": [
-                "address",
-                "city",
-                "country",
-                "latitude",
-                "longitude"
-            ],
             "properties": {
-                "address": {
+                "created_at": {
                     "type": "string"
                 },
-                "city": {
+                "created_by": {
                     "type": "string"
                 },
-                "country": {
+                "currency": {
                     "type": "string"
                 },
-                "latitude": {
-                    "type": "number"
+                "id": {
+                    "type": "string"
                 },
-                "longitude": {
+                "payouts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payout"
+                    }
+                },
+                "total": {
                     "type": "number"
                 }
             }
@@ -1525,7 +5071,6 @@ const docTemplate = `{
             "type": "object",
             "required": [
                 "availability",
-                "average_rating",
                 "company_name",
                 "description",
                 "location",
@@ -1539,7 +5084,9 @@ const docTemplate = `{
                     }
                 },
                 "average_rating": {
-                    "type": "number"
+                    "type": "number",
+                    "maximum": 5,
+                    "minimum": 0
                 },
                 "company_name": {
                     "type": "string"
@@ -1558,7 +5105,7 @@ const docTemplate = `{
                 }
             }
         },
-        "models.ProviderUpdate": {
+        "models.ProviderDistance": {
             "type": "object",
             "properties": {
                 "availability": {
@@ -1573,17 +5120,127 @@ const docTemplate = `{
                 "company_name": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distance_km": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/providers.Location"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProviderSearchResp": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProviderDistance"
+                    }
+                }
+            }
+        },
+        "models.ProviderUpdate": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "average_rating": {
+                    "type": "number",
+                    "maximum": 5,
+                    "minimum": 0
+                },
+                "company_name": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
                 "location": {
                     "$ref": "#/definitions/models.Location"
                 },
-                "services": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Refund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "booking_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment_intent_id": {
+                    "type": "string"
+                },
+                "processor_refund_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RefundCreate": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
                 }
             }
         },
@@ -1605,8 +5262,45 @@ const docTemplate = `{
                 "provider_id": {
                     "type": "string"
                 },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                }
+            }
+        },
+        "models.ReviewExpanded": {
+            "type": "object",
+            "properties": {
+                "booking": {
+                    "$ref": "#/definitions/bookings.Booking"
+                },
+                "booking_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "$ref": "#/definitions/providers.Provider"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
                 "rating": {
                     "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
@@ -1617,7 +5311,32 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                }
+            }
+        },
+        "models.ServiceCreate": {
+            "type": "object",
+            "required": [
+                "description",
+                "duration",
+                "name",
+                "price"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
                     "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
                 }
             }
         },
@@ -1638,6 +5357,37 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Slot": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SlotsResp": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "service_id": {
+                    "type": "string"
+                },
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Slot"
+                    }
+                }
+            }
+        },
         "models.UserUpdate": {
             "type": "object",
             "required": [
@@ -1661,13 +5411,31 @@ const docTemplate = `{
                 }
             }
         },
-        "notifications.NewNotification": {
+        "models.Webhook": {
             "type": "object",
             "properties": {
-                "message": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "title": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 },
                 "user_id": {
@@ -1675,42 +5443,129 @@ const docTemplate = `{
                 }
             }
         },
-        "notifications.Notification": {
+        "models.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "response_status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WebhookCreate": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
             "type": "object",
             "properties": {
+                "attempt_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookAttempt"
+                    }
+                },
+                "attempts": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "message": {
+                "last_error": {
                     "type": "string"
                 },
-                "title": {
+                "next_attempt_at": {
                     "type": "string"
                 },
-                "user_id": {
+                "payload": {
+                    "type": "object"
+                },
+                "redelivery_of": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
                     "type": "string"
                 }
             }
         },
-        "payments.NewPayment": {
+        "models.WebhookUpdate": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
+                "active": {
+                    "type": "boolean"
                 },
-                "booking_id": {
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "notifications.Notification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "payment_method": {
+                "id": {
                     "type": "string"
                 },
-                "status": {
+                "is_read": {
+                    "type": "boolean"
+                },
+                "message": {
                     "type": "string"
                 },
-                "transaction_id": {
+                "read_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
@@ -1741,20 +5596,26 @@ const docTemplate = `{
                 }
             }
         },
-        "payments.PaymentsList": {
+        "pricing.Quote": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "type": "integer"
+                "base_price": {
+                    "type": "number"
                 },
-                "page": {
-                    "type": "integer"
+                "distance_km": {
+                    "type": "number"
                 },
-                "payments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/payments.Payment"
-                    }
+                "distance_surcharge": {
+                    "type": "number"
+                },
+                "peak_surcharge": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "total_price": {
+                    "type": "number"
                 }
             }
         },
@@ -1830,34 +5691,6 @@ const docTemplate = `{
                 }
             }
         },
-        "providers.ProvidersList": {
-            "type": "object",
-            "properties": {
-                "limit": {
-                    "type": "integer"
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "providers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/providers.Provider"
-                    }
-                }
-            }
-        },
-        "providers.SearchResp": {
-            "type": "object",
-            "properties": {
-                "providers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/providers.Provider"
-                    }
-                }
-            }
-        },
         "providers.UpdateResp": {
             "type": "object",
             "properties": {
@@ -1895,23 +5728,6 @@ const docTemplate = `{
                 }
             }
         },
-        "reviews.ReviewsList": {
-            "type": "object",
-            "properties": {
-                "limit": {
-                    "type": "integer"
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "reviews": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/reviews.Review"
-                    }
-                }
-            }
-        },
         "reviews.UpdateResp": {
             "type": "object",
             "properties": {
@@ -1931,23 +5747,6 @@ const docTemplate = `{
                 }
             }
         },
-        "services.NewService": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "duration": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "number"
-                }
-            }
-        },
         "services.SearchResp": {
             "type": "object",
             "properties": {
@@ -1988,23 +5787,6 @@ const docTemplate = `{
                 }
             }
         },
-        "services.ServicesList": {
-            "type": "object",
-            "properties": {
-                "limit": {
-                    "type": "integer"
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "services": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/services.Service"
-                    }
-                }
-            }
-        },
         "services.UpdateResp": {
             "type": "object",
             "properties": {
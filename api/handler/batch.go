@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Batch godoc
+// @Summary Runs several requests at once
+// @Description Runs each sub-request through the API with the caller's credentials, as if it
+// @Description had been sent on its own, and returns the status and body of every one in order.
+// @Description Paths are relative to the API base path, e.g. /providers/register.
+// @Description With stop_on_error, sub-requests that have not started when one fails are skipped.
+// @Tags batch
+// @Security ApiKeyAuth
+// @Param data body models.BatchRequest true "Sub-requests"
+// @Success 200 {object} models.BatchResp
+// @Failure 400 {object} string "Invalid data format"
+// @Router /batch [post]
+func (h *Handler) Batch(c *gin.Context) {
+	h.Logger.Info("Batch handler is invoked")
+
+	var req models.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Requests) > h.BatchMaxRequests {
+		msg := fmt.Sprintf("a batch can hold at most %d requests", h.BatchMaxRequests)
+		handleError(c, h, nil, msg, http.StatusBadRequest)
+		return
+	}
+
+	for _, item := range req.Requests {
+		path := strings.TrimPrefix(item.Path, apiPrefix)
+		if strings.HasPrefix(path, "/batch") {
+			handleError(c, h, nil, "batches cannot be nested", http.StatusBadRequest)
+			return
+		}
+		if strings.HasPrefix(path, "/stream") {
+			handleError(c, h, nil, "streams cannot be batched", http.StatusBadRequest)
+			return
+		}
+	}
+
+	concurrency := h.BatchMaxConcurrency
+	if req.Concurrency > 0 {
+		concurrency = min(req.Concurrency, h.BatchMaxConcurrency)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+		failed  atomic.Bool
+		results = make([]models.BatchResult, len(req.Requests))
+	)
+
+	for i, item := range req.Requests {
+		sem <- struct{}{}
+
+		if req.StopOnError && failed.Load() {
+			<-sem
+			results[i] = models.BatchResult{Skipped: true}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = h.runBatchItem(c, item)
+			if results[i].Status >= http.StatusBadRequest {
+				failed.Store(true)
+			}
+		}()
+	}
+	wg.Wait()
+
+	h.Logger.Info("Batch handler is completed")
+	c.JSON(http.StatusOK, models.BatchResp{Results: results})
+}
+
+// runBatchItem sends one sub-request through the router, carrying over
+// the caller's credentials.
+func (h *Handler) runBatchItem(c *gin.Context, item models.BatchItem) models.BatchResult {
+	path := item.Path
+	if !strings.HasPrefix(path, apiPrefix+"/") {
+		path = apiPrefix + path
+	}
+
+	var body bytes.Buffer
+	if len(item.Body) > 0 {
+		body.Write(item.Body)
+	}
+
+	sub, err := http.NewRequestWithContext(c.Request.Context(), item.Method, path, &body)
+	if err != nil {
+		return batchError(http.StatusBadRequest, "invalid request: "+err.Error())
+	}
+	sub.Header.Set("Authorization", c.GetHeader("Authorization"))
+	if body.Len() > 0 {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+	sub.RemoteAddr = c.Request.RemoteAddr
+
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, sub)
+
+	result := models.BatchResult{Status: w.Code}
+	if out := w.Body.Bytes(); json.Valid(out) {
+		result.Body = out
+	} else if len(out) > 0 {
+		result.Body, _ = json.Marshal(string(out))
+	}
+
+	return result
+}
+
+func batchError(status int, msg string) models.BatchResult {
+	body, _ := json.Marshal(gin.H{"error": msg})
+	return models.BatchResult{Status: status, Body: body}
+}
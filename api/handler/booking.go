@@ -2,12 +2,23 @@ package handler
 
 import (
 	pb "api-gateway/genproto/bookings"
+	pbpa "api-gateway/genproto/payments"
+	pbp "api-gateway/genproto/providers"
+	pbs "api-gateway/genproto/services"
 	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"api-gateway/pkg/geo"
+	"api-gateway/pkg/pricing"
+	"api-gateway/pkg/schedule"
 	"context"
 	"encoding/json"
 	"net/http"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
 // CreateBooking godoc
@@ -16,8 +27,9 @@ import (
 // @Tags booking
 // @Security ApiKeyAuth
 // @Param data body models.BookingCreate true "New booking"
-// @Success 201 {object} string "Booking created"
-// @Failure 400 {object} string "Invalid data format"
+// @Success 201 {object} models.BookingResp
+// @Failure 400 {object} string "Invalid data format or mismatching total price"
+// @Failure 409 {object} string "Requested time is not available"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /bookings [post]
 func (h *Handler) CreateBooking(c *gin.Context) {
@@ -35,11 +47,52 @@ func (h *Handler) CreateBooking(c *gin.Context) {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	quote, err := h.createBooking(ctx, id, req)
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("CreateBooking handler is completed")
+	c.JSON(http.StatusOK, models.BookingResp{Message: "Booking created", Price: quote})
+}
+
+// createBooking prices the booking at the provider's location and time
+// and publishes it as pending.
+func (h *Handler) createBooking(ctx context.Context, userID string, req models.BookingCreate) (pricing.Quote, error) {
+	service, err := h.Service.GetService(ctx, &pbs.ID{Id: req.ServiceID})
+	if err != nil {
+		return pricing.Quote{}, newStatusError(err, "error finding service", http.StatusInternalServerError)
+	}
+
+	provider, err := h.Provider.GetProvider(ctx, &pbp.ID{Id: req.ProviderID})
+	if err != nil {
+		return pricing.Quote{}, newStatusError(err, "error finding provider", http.StatusInternalServerError)
+	}
+
+	scheduledAt, _ := time.Parse(time.RFC3339, req.ScheduledTime)
+
+	err = h.checkAvailability(provider, service, scheduledAt)
+	if err != nil {
+		return pricing.Quote{}, availabilityError(err)
+	}
+
+	quote := h.quoteBooking(service, provider, scheduledAt, req.Location.Latitude, req.Location.Longitude)
+
+	if req.TotalPrice != 0 && !quote.Matches(req.TotalPrice) {
+		err := newStatusError(nil, "total price does not match server calculation", http.StatusBadRequest)
+		err.extra = gin.H{"price": quote}
+		return pricing.Quote{}, err
+	}
+
 	message, err := json.Marshal(pb.NewBooking{
-		UserId:        id,
+		UserId:        userID,
 		ProviderId:    req.ProviderID,
 		ServiceId:     req.ServiceID,
-		Status:        req.Status,
+		Status:        booking.StatusPending,
 		ScheduledTime: req.ScheduledTime,
 		Location: &pb.Location{
 			Address:   req.Location.Address,
@@ -48,24 +101,19 @@ func (h *Handler) CreateBooking(c *gin.Context) {
 			Latitude:  req.Location.Latitude,
 			Longitude: req.Location.Longitude,
 		},
-		TotalPrice: req.TotalPrice,
+		TotalPrice: quote.TotalPrice,
 	})
 	if err != nil {
-		handleError(c, h, err, "error serializing booking", http.StatusInternalServerError)
-		return
+		return pricing.Quote{}, newStatusError(err, "error serializing booking", http.StatusInternalServerError)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
-	defer cancel()
-
-	err = h.KafkaProducer.Produce(ctx, h.TopicBookingCreated, []byte(message))
+	publish := h.bookingPublisher(ctx, h.TopicBookingCreated, message, "error creating booking")
+	err = h.holdBookingSlot(ctx, provider.Id, scheduledAt, time.Duration(service.Duration)*time.Minute, publish)
 	if err != nil {
-		handleError(c, h, err, "error creating booking", http.StatusInternalServerError)
-		return
+		return pricing.Quote{}, err
 	}
 
-	h.Logger.Info("CreateBooking handler is completed")
-	c.JSON(http.StatusOK, "Booking created")
+	return quote, nil
 }
 
 // GetBooking godoc
@@ -74,8 +122,10 @@ func (h *Handler) CreateBooking(c *gin.Context) {
 // @Tags booking
 // @Security ApiKeyAuth
 // @Param id path string true "Booking ID"
-// @Success 200 {object} bookings.Booking
+// @Param expand query string false "Related resources to inline: provider, service, payments"
+// @Success 200 {object} models.BookingExpanded
 // @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /bookings/{id} [get]
 func (h *Handler) GetBooking(c *gin.Context) {
@@ -87,15 +137,31 @@ func (h *Handler) GetBooking(c *gin.Context) {
 		return
 	}
 
+	expand, err := parseExpand(c, bookingRelations)
+	if err != nil {
+		handleError(c, h, err, "invalid expand parameter", http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
-	resp, err := h.Booking.GetBooking(ctx, &pb.ID{Id: id})
+	item, err := h.Booking.GetBooking(ctx, &pb.ID{Id: id})
 	if err != nil {
 		handleError(c, h, err, "error finding booking", http.StatusInternalServerError)
 		return
 	}
 
+	if err := h.checkBookingAccess(ctx, c, item); err != nil {
+		handleError(c, h, err, "access denied", http.StatusForbidden)
+		return
+	}
+
+	var resp interface{} = item
+	if len(expand) > 0 {
+		resp = h.expandBookings(ctx, c, []*pb.Booking{item}, expand)[0]
+	}
+
 	h.Logger.Info("GetBooking handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
@@ -107,8 +173,10 @@ func (h *Handler) GetBooking(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Param id path string true "Booking ID"
 // @Param data body models.BookingUpdate true "New booking data"
-// @Success 200 {object} string "Booking updated"
+// @Success 200 {object} models.BookingResp
 // @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Booking can no longer be modified, requested time is not available or a payment is in progress"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /bookings/{id} [put]
 func (h *Handler) UpdateBooking(c *gin.Context) {
@@ -126,35 +194,103 @@ func (h *Handler) UpdateBooking(c *gin.Context) {
 		return
 	}
 
-	message, err := json.Marshal(pb.NewData{
-		Id:            id,
-		Status:        req.Status,
-		ScheduledTime: req.ScheduledTime,
-		Location: &pb.Location{
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	quote, err := h.updateBooking(ctx, c, id, req)
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("UpdateBooking handler is completed")
+	c.JSON(http.StatusOK, models.BookingResp{Message: "Booking updated", Price: quote})
+}
+
+// updateBooking reschedules or relocates a booking on behalf of the
+// caller, prices it again and publishes the change.
+func (h *Handler) updateBooking(ctx context.Context, c *gin.Context, id string, req models.BookingUpdate) (pricing.Quote, error) {
+	current, err := h.Booking.GetBooking(ctx, &pb.ID{Id: id})
+	if err != nil {
+		return pricing.Quote{}, newStatusError(err, "error finding booking", http.StatusInternalServerError)
+	}
+
+	if err := h.checkBookingAccess(ctx, c, current); err != nil {
+		return pricing.Quote{}, newStatusError(err, "access denied", http.StatusForbidden)
+	}
+
+	if !booking.IsModifiable(current.Status) {
+		return pricing.Quote{}, newStatusError(nil, "booking can no longer be modified", http.StatusConflict)
+	}
+
+	scheduledTime := current.ScheduledTime
+	if req.ScheduledTime != "" {
+		scheduledTime = req.ScheduledTime
+	}
+
+	location := current.Location
+	if req.Location != nil {
+		location = &pb.Location{
 			Address:   req.Location.Address,
 			City:      req.Location.City,
 			Country:   req.Location.Country,
 			Latitude:  req.Location.Latitude,
 			Longitude: req.Location.Longitude,
-		},
-		TotalPrice: req.TotalPrice,
-	})
+		}
+	}
+
+	service, err := h.Service.GetService(ctx, &pbs.ID{Id: current.ServiceId})
 	if err != nil {
-		handleError(c, h, err, "error serializing booking", http.StatusInternalServerError)
-		return
+		return pricing.Quote{}, newStatusError(err, "error finding service", http.StatusInternalServerError)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
-	defer cancel()
+	provider, err := h.Provider.GetProvider(ctx, &pbp.ID{Id: current.ProviderId})
+	if err != nil {
+		return pricing.Quote{}, newStatusError(err, "error finding provider", http.StatusInternalServerError)
+	}
 
-	err = h.KafkaProducer.Produce(ctx, h.TopicBookingUpdated, []byte(message))
+	scheduledAt, err := time.Parse(time.RFC3339, scheduledTime)
 	if err != nil {
-		handleError(c, h, err, "error updating booking", http.StatusInternalServerError)
-		return
+		return pricing.Quote{}, newStatusError(err, "invalid scheduled time", http.StatusInternalServerError)
 	}
 
-	h.Logger.Info("UpdateBooking handler is completed")
-	c.JSON(http.StatusOK, "Booking updated")
+	if scheduledTime != current.ScheduledTime {
+		err = h.checkAvailability(provider, service, scheduledAt)
+		if err != nil {
+			return pricing.Quote{}, availabilityError(err)
+		}
+	}
+
+	quote := h.quoteBooking(service, provider, scheduledAt, location.GetLatitude(), location.GetLongitude())
+
+	if quote.TotalPrice != current.TotalPrice {
+		if err := h.checkNoOpenIntent(ctx, id); err != nil {
+			return pricing.Quote{}, err
+		}
+	}
+
+	message, err := json.Marshal(pb.NewData{
+		Id:            id,
+		Status:        current.Status,
+		ScheduledTime: scheduledTime,
+		Location:      location,
+		TotalPrice:    quote.TotalPrice,
+	})
+	if err != nil {
+		return pricing.Quote{}, newStatusError(err, "error serializing booking", http.StatusInternalServerError)
+	}
+
+	publish := h.bookingPublisher(ctx, h.TopicBookingUpdated, message, "error updating booking")
+	if scheduledTime != current.ScheduledTime {
+		err = h.moveBookingSlot(ctx, current, scheduledAt, time.Duration(service.Duration)*time.Minute, publish)
+	} else {
+		err = publish()
+	}
+	if err != nil {
+		return pricing.Quote{}, err
+	}
+
+	return quote, nil
 }
 
 // CancelBooking godoc
@@ -165,34 +301,88 @@ func (h *Handler) UpdateBooking(c *gin.Context) {
 // @Param id path string true "Booking ID"
 // @Success 200 {object} string "Booking canceled"
 // @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Invalid status transition"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /bookings/{id}/cancel [put]
 func (h *Handler) CancelBooking(c *gin.Context) {
 	h.Logger.Info("CancelBooking handler is invoked")
 
-	id := c.Param("id")
-	if id == "" {
-		handleError(c, h, nil, "invalid data format", http.StatusBadRequest)
+	if !h.changeBookingStatus(c, booking.StatusCancelled) {
 		return
 	}
 
-	message, err := json.Marshal(pb.ID{Id: id})
-	if err != nil {
-		handleError(c, h, err, "error serializing booking", http.StatusInternalServerError)
+	h.Logger.Info("CancelBooking handler is completed")
+	c.JSON(http.StatusOK, "Booking canceled")
+}
+
+// ConfirmBooking godoc
+// @Summary Confirms booking
+// @Description Moves a pending booking to confirmed
+// @Tags booking
+// @Security ApiKeyAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} string "Booking confirmed"
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Invalid status transition"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /bookings/{id}/confirm [put]
+func (h *Handler) ConfirmBooking(c *gin.Context) {
+	h.Logger.Info("ConfirmBooking handler is invoked")
+
+	if !h.changeBookingStatus(c, booking.StatusConfirmed) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
-	defer cancel()
+	h.Logger.Info("ConfirmBooking handler is completed")
+	c.JSON(http.StatusOK, "Booking confirmed")
+}
 
-	err = h.KafkaProducer.Produce(ctx, h.TopicBookingCancelled, message)
-	if err != nil {
-		handleError(c, h, err, "error canceling booking", http.StatusInternalServerError)
+// StartBooking godoc
+// @Summary Starts booking
+// @Description Moves a confirmed booking to in progress
+// @Tags booking
+// @Security ApiKeyAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} string "Booking started"
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Invalid status transition"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /bookings/{id}/start [put]
+func (h *Handler) StartBooking(c *gin.Context) {
+	h.Logger.Info("StartBooking handler is invoked")
+
+	if !h.changeBookingStatus(c, booking.StatusInProgress) {
 		return
 	}
 
-	h.Logger.Info("CancelBooking handler is completed")
-	c.JSON(http.StatusOK, "Booking canceled")
+	h.Logger.Info("StartBooking handler is completed")
+	c.JSON(http.StatusOK, "Booking started")
+}
+
+// CompleteBooking godoc
+// @Summary Completes booking
+// @Description Moves an in progress booking to completed
+// @Tags booking
+// @Security ApiKeyAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} string "Booking completed"
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Invalid status transition"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /bookings/{id}/complete [put]
+func (h *Handler) CompleteBooking(c *gin.Context) {
+	h.Logger.Info("CompleteBooking handler is invoked")
+
+	if !h.changeBookingStatus(c, booking.StatusCompleted) {
+		return
+	}
+
+	h.Logger.Info("CompleteBooking handler is completed")
+	c.JSON(http.StatusOK, "Booking completed")
 }
 
 // FetchBookings godoc
@@ -200,39 +390,455 @@ func (h *Handler) CancelBooking(c *gin.Context) {
 // @Description Fetches bookings
 // @Tags booking
 // @Security ApiKeyAuth
-// @Param page query int true "Page number"
-// @Param limit query int true "Number of items per page"
-// @Success 200 {object} bookings.BookingsList
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. status:eq:pending,scheduled_time:gte:2026-10-01"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Param expand query string false "Related resources to inline: provider, service, payments"
+// @Success 200 {object} models.ListResp{data=[]models.BookingExpanded}
 // @Failure 400 {object} string "Invalid data format"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "List too long to filter in the gateway"
 // @Router /bookings/all [get]
 func (h *Handler) FetchBookings(c *gin.Context) {
 	h.Logger.Info("FetchBookings handler is invoked")
 
-	pageStr := c.Query("page")
-	limitStr := c.Query("limit")
-
-	page, err := parseIntQueryParam(pageStr)
+	expand, err := parseExpand(c, bookingRelations)
 	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
+		handleError(c, h, err, "invalid expand parameter", http.StatusBadRequest)
 		return
 	}
 
-	limit, err := parseIntQueryParam(limitStr)
+	q, err := parseQuery(c, bookingFields)
 	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
 		return
 	}
 
+	resp, ok := paginate(c, h, applyQuery(h.bookingScan.source(q), q), "error fetching bookings")
+	if !ok {
+		return
+	}
+
+	h.expandBookingList(c, resp, expand)
+
+	h.Logger.Info("FetchBookings handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// checkBookingAccess verifies that the caller is the customer who made
+// the booking, the provider it was made with, or an admin.
+func (h *Handler) checkBookingAccess(ctx context.Context, c *gin.Context, b *pb.Booking) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return err
+	}
+
+	role, err := getUserRole(c)
+	if err != nil {
+		return err
+	}
+
+	switch role {
+	case booking.RoleAdmin:
+		return nil
+	case booking.RoleCustomer:
+		if b.UserId == userID {
+			return nil
+		}
+	case booking.RoleProvider:
+		return h.checkProviderAccess(ctx, c, b.ProviderId)
+	}
+
+	return errors.New("booking does not belong to user")
+}
+
+// changeBookingStatus moves the booking from the path through the
+// lifecycle. It writes the error response itself and reports whether
+// the transition succeeded.
+func (h *Handler) changeBookingStatus(c *gin.Context, status string) bool {
+	id := c.Param("id")
+	if id == "" {
+		handleError(c, h, nil, "invalid data format", http.StatusBadRequest)
+		return false
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
+	if err := h.setBookingStatus(ctx, c, id, status); err != nil {
+		respondError(c, h, err)
+		return false
+	}
+
+	return true
+}
+
+// setBookingStatus moves the booking through the lifecycle on behalf of
+// the caller and publishes the change. A cancelled booking that was paid
+// is refunded.
+func (h *Handler) setBookingStatus(ctx context.Context, c *gin.Context, id, status string) error {
+	role, err := getUserRole(c)
+	if err != nil {
+		return newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	current, err := h.Booking.GetBooking(ctx, &pb.ID{Id: id})
+	if err != nil {
+		return newStatusError(err, "error finding booking", http.StatusInternalServerError)
+	}
+
+	if err := h.checkBookingAccess(ctx, c, current); err != nil {
+		return newStatusError(err, "access denied", http.StatusForbidden)
+	}
+
+	err = booking.CheckTransition(current.Status, status, role)
+	switch errors.Cause(err) {
+	case nil:
+	case booking.ErrForbiddenTransition:
+		return newStatusError(err, "access denied", http.StatusForbidden)
+	default:
+		return newStatusError(err, "invalid status transition", http.StatusConflict)
+	}
+
+	topic := h.TopicBookingUpdated
+	var message []byte
+	if status == booking.StatusCancelled {
+		topic = h.TopicBookingCancelled
+		message, err = json.Marshal(pb.ID{Id: id})
+	} else {
+		message, err = json.Marshal(pb.NewData{
+			Id:            id,
+			Status:        status,
+			ScheduledTime: current.ScheduledTime,
+			Location:      current.Location,
+			TotalPrice:    current.TotalPrice,
+		})
+	}
+	if err != nil {
+		return newStatusError(err, "error serializing booking", http.StatusInternalServerError)
+	}
+
+	publish := h.bookingPublisher(ctx, topic, message, "error changing booking status")
+	if status == booking.StatusCancelled || status == booking.StatusCompleted {
+		err = h.freeBookingSlot(ctx, current, publish)
+	} else {
+		err = publish()
+	}
+	if err != nil {
+		return err
+	}
+
+	if status == booking.StatusCancelled {
+		// The refund policy counts the notice from now, also for refunds
+		// made later on.
+		userID, _ := getUserID(c)
+		if err := h.Storage.RecordCancellation(ctx, id, userID, role); err != nil {
+			h.Logger.Error(errors.Wrap(err, "error recording cancellation").Error(), "booking_id", id)
+		}
+		h.refundCancelledBooking(ctx, current, role, userID)
+	}
+
+	return nil
+}
+
+// bookingPublisher returns the publish of a booking change for the slot
+// changes to run.
+func (h *Handler) bookingPublisher(ctx context.Context, topic string, message []byte, errMsg string) func() error {
+	return func() error {
+		if err := h.KafkaProducer.Produce(ctx, topic, message); err != nil {
+			return newStatusError(err, errMsg, http.StatusInternalServerError)
+		}
+		return nil
+	}
+}
+
+// quoteBooking prices a booking from the service's listed price and the
+// distance between the provider and the booking location.
+func (h *Handler) quoteBooking(service *pbs.Service, provider *pbp.Provider, at time.Time,
+	lat, lng float32) pricing.Quote {
+	var distance float64
+	if loc := provider.GetLocation(); loc != nil {
+		distance = geo.Distance(float64(loc.Latitude), float64(loc.Longitude), float64(lat), float64(lng))
+	}
+
+	return h.Pricing.Quote(service.Price, distance, at)
+}
+
+// checkAvailability verifies that the service fits into the provider's
+// working hours at the given time, if the provider set any. Overlaps with
+// other bookings of the provider are caught when the time is taken up.
+func (h *Handler) checkAvailability(provider *pbp.Provider, service *pbs.Service, at time.Time) error {
+	slot := schedule.Interval{Start: at, End: at.Add(time.Duration(service.Duration) * time.Minute)}
+	if !h.providerSchedule(provider).Contains(slot) {
+		return schedule.ErrOutsideHours
+	}
+	return nil
+}
+
+// providerSchedule parses the provider's availability. A provider without
+// working hours can be booked at any time, and so can one whose
+// availability does not parse, such as entries saved before the format
+// was checked.
+func (h *Handler) providerSchedule(provider *pbp.Provider) *schedule.Schedule {
+	sched, err := schedule.Parse(provider.Availability, h.TimeZone)
+	if err != nil {
+		h.Logger.Warn("ignoring invalid provider availability", "provider_id", provider.Id, "error", err)
+		sched, _ = schedule.Parse(nil, h.TimeZone)
+	}
+	return sched
+}
+
+func availabilityError(err error) error {
+	switch errors.Cause(err) {
+	case schedule.ErrOutsideHours, schedule.ErrSlotTaken:
+		return newStatusError(err, "requested time is not available", http.StatusConflict)
+	default:
+		return newStatusError(err, "error checking availability", http.StatusInternalServerError)
+	}
+}
+
+// providerBusyIntervals returns the time taken by the active bookings of
+// the provider between from and to.
+func (h *Handler) providerBusyIntervals(ctx context.Context, providerID string,
+	from, to time.Time) ([]schedule.Interval, error) {
+	slots, err := h.Storage.ListBookingSlots(ctx, providerID, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching booking slots")
+	}
+
+	var busy []schedule.Interval
+	for _, s := range slots {
+		busy = append(busy, schedule.Interval{Start: s.Start, End: s.End})
+	}
+
+	return busy, nil
+}
+
+func (h *Handler) bookingsPage(ctx context.Context, page, limit int32) ([]*pb.Booking, error) {
 	resp, err := h.Booking.ListBookings(ctx, &pb.Pagination{Page: page, Limit: limit})
 	if err != nil {
-		handleError(c, h, err, "error fetching bookings", http.StatusInternalServerError)
+		return nil, err
+	}
+	return resp.Bookings, nil
+}
+
+// FetchMyBookings godoc
+// @Summary Fetches my bookings
+// @Description Fetches bookings made by the current user
+// @Tags booking
+// @Security ApiKeyAuth
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. status:eq:pending,scheduled_time:gte:2026-10-01"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Param expand query string false "Related resources to inline: provider, service, payments"
+// @Success 200 {object} models.ListResp{data=[]models.BookingExpanded}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "List too long to filter in the gateway"
+// @Router /users/me/bookings [get]
+func (h *Handler) FetchMyBookings(c *gin.Context) {
+	h.Logger.Info("FetchMyBookings handler is invoked")
+
+	id, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
 		return
 	}
 
-	h.Logger.Info("FetchBookings handler is completed")
+	expand, err := parseExpand(c, bookingRelations)
+	if err != nil {
+		handleError(c, h, err, "invalid expand parameter", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseQuery(c, bookingFields)
+	if err != nil {
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
+		return
+	}
+
+	scoped := filterAll(h.bookingScan.pages, func(b *pb.Booking) bool {
+		return b.UserId == id
+	})
+
+	resp, ok := paginate(c, h, applyQuery(scoped, q), "error fetching bookings")
+	if !ok {
+		return
+	}
+
+	h.expandBookingList(c, resp, expand)
+
+	h.Logger.Info("FetchMyBookings handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// FetchProviderBookings godoc
+// @Summary Fetches provider bookings
+// @Description Fetches bookings made with a provider
+// @Tags booking
+// @Security ApiKeyAuth
+// @Param id path string true "Provider ID"
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. status:eq:pending,scheduled_time:gte:2026-10-01"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Param expand query string false "Related resources to inline: provider, service, payments"
+// @Success 200 {object} models.ListResp{data=[]models.BookingExpanded}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "List too long to filter in the gateway"
+// @Router /providers/{id}/bookings [get]
+func (h *Handler) FetchProviderBookings(c *gin.Context) {
+	h.Logger.Info("FetchProviderBookings handler is invoked")
+
+	id := c.Param("id")
+	if id == "" {
+		handleError(c, h, nil, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	if err := h.checkProviderAccess(ctx, c, id); err != nil {
+		handleError(c, h, err, "access denied", http.StatusForbidden)
+		return
+	}
+
+	expand, err := parseExpand(c, bookingRelations)
+	if err != nil {
+		handleError(c, h, err, "invalid expand parameter", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseQuery(c, bookingFields)
+	if err != nil {
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
+		return
+	}
+
+	scoped := filterAll(h.bookingScan.pages, func(b *pb.Booking) bool {
+		return b.ProviderId == id
+	})
+
+	resp, ok := paginate(c, h, applyQuery(scoped, q), "error fetching bookings")
+	if !ok {
+		return
+	}
+
+	h.expandBookingList(c, resp, expand)
+
+	h.Logger.Info("FetchProviderBookings handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetBookingDetails godoc
+// @Summary Gets booking details
+// @Description Gets a booking together with its service, provider, payments and review.
+// @Description Parts that fail to load are left empty and listed in "unavailable".
+// @Tags booking
+// @Security ApiKeyAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} models.BookingDetails
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /bookings/{id}/details [get]
+func (h *Handler) GetBookingDetails(c *gin.Context) {
+	h.Logger.Info("GetBookingDetails handler is invoked")
+
+	id := c.Param("id")
+	if id == "" {
+		handleError(c, h, nil, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	b, err := h.Booking.GetBooking(ctx, &pb.ID{Id: id})
+	if err != nil {
+		handleError(c, h, err, "error finding booking", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.checkBookingAccess(ctx, c, b); err != nil {
+		handleError(c, h, err, "access denied", http.StatusForbidden)
+		return
+	}
+
+	resp := models.BookingDetails{Booking: b}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	// part loads one section of the document, marking it unavailable
+	// instead of failing the whole request.
+	part := func(name string, load func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := load(); err != nil {
+				h.Logger.Warn("error loading booking details", "part", name, "booking_id", id, "error", err)
+
+				mu.Lock()
+				resp.Unavailable = append(resp.Unavailable, name)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	part("service", func() (err error) {
+		resp.Service, err = h.getService(ctx, b.ServiceId)
+		return err
+	})
+	part("provider", func() (err error) {
+		resp.Provider, err = h.getProvider(ctx, b.ProviderId)
+		return err
+	})
+	part("payments", func() error {
+		grouped, err := h.paymentsByBooking(ctx, map[string]bool{id: true})
+		if err != nil {
+			return err
+		}
+
+		resp.Payments = grouped[id]
+		if resp.Payments == nil {
+			resp.Payments = []*pbpa.Payment{}
+		}
+		return nil
+	})
+	part("review", func() error {
+		reviews, err := h.reviewScan.all(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range reviews {
+			if r.BookingId == id {
+				resp.Review = r
+				break
+			}
+		}
+		return nil
+	})
+
+	wg.Wait()
+	sort.Strings(resp.Unavailable)
+
+	h.Logger.Info("GetBookingDetails handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
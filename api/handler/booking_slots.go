@@ -0,0 +1,65 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/bookings"
+	"api-gateway/pkg/schedule"
+	"api-gateway/storage"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// holdBookingSlot takes up the time of a new booking while publish
+// publishes it. The bookings service gives the booking its ID, so the
+// slot is held under one of its own until the booking next changes.
+func (h *Handler) holdBookingSlot(ctx context.Context, providerID string, start time.Time, d time.Duration,
+	publish func() error) error {
+	err := h.Storage.HoldBookingSlot(ctx, &storage.BookingSlot{
+		BookingID:  uuid.NewString(),
+		ProviderID: providerID,
+		Start:      start,
+		End:        start.Add(d),
+	}, publish)
+	return slotError(err)
+}
+
+// moveBookingSlot moves the time a booking takes up to start while
+// publish publishes the reschedule.
+func (h *Handler) moveBookingSlot(ctx context.Context, b *pb.Booking, start time.Time, d time.Duration,
+	publish func() error) error {
+	err := h.Storage.MoveBookingSlot(ctx, bookingSlot(b), &storage.BookingSlot{
+		BookingID:  b.Id,
+		ProviderID: b.ProviderId,
+		Start:      start,
+		End:        start.Add(d),
+	}, publish)
+	return slotError(err)
+}
+
+// freeBookingSlot frees the time of a booking that is no longer active
+// while publish publishes the change.
+func (h *Handler) freeBookingSlot(ctx context.Context, b *pb.Booking, publish func() error) error {
+	return slotError(h.Storage.FreeBookingSlot(ctx, bookingSlot(b), publish))
+}
+
+// bookingSlot is the slot a booking has, as far as it is needed to find
+// it: a held one is known by its provider and start.
+func bookingSlot(b *pb.Booking) *storage.BookingSlot {
+	start, _ := time.Parse(time.RFC3339, b.ScheduledTime)
+	return &storage.BookingSlot{BookingID: b.Id, ProviderID: b.ProviderId, Start: start}
+}
+
+// slotError reports a slot that overlaps another booking as a conflict.
+// The errors of publish pass through.
+func slotError(err error) error {
+	if _, ok := err.(*statusError); ok || err == nil {
+		return err
+	}
+
+	if err == storage.ErrConflict {
+		return newStatusError(schedule.ErrSlotTaken, "requested time is not available", http.StatusConflict)
+	}
+	return newStatusError(err, "error saving booking slot", http.StatusInternalServerError)
+}
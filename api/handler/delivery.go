@@ -0,0 +1,215 @@
+package handler
+
+import (
+	pbn "api-gateway/genproto/notifications"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/kafka/consumer"
+	"api-gateway/models"
+	"api-gateway/pkg/delivery"
+	"api-gateway/storage"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// Outcomes of a delivery attempt.
+const (
+	deliverySent    = "sent"
+	deliveryFailed  = "failed"
+	deliverySkipped = "skipped"
+)
+
+// defaultChannels is the preferences entry for the notification types
+// that have no entry of their own.
+const defaultChannels = "default"
+
+// GetNotificationPreferences godoc
+// @Summary Gets my notification preferences
+// @Description Gets the channels the current user's notifications are delivered through besides the inbox
+// @Tags notification
+// @Security ApiKeyAuth
+// @Success 200 {object} models.NotificationPreferences
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /users/me/notification-preferences [get]
+func (h *Handler) GetNotificationPreferences(c *gin.Context) {
+	h.Logger.Info("GetNotificationPreferences handler is invoked")
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	prefs, err := h.preferences(ctx, userID)
+	if err != nil {
+		handleError(c, h, err, "error fetching preferences", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("GetNotificationPreferences handler is completed")
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Updates my notification preferences
+// @Description Replaces the channels the current user's notifications are delivered through besides the inbox.
+// @Description Channels are listed by notification type, e.g. booking_confirmed, with "default" for the
+// @Description types not listed. Channels are email, sms, push and webhook; email and SMS go to the
+// @Description address and phone number of the profile.
+// @Tags notification
+// @Security ApiKeyAuth
+// @Param data body models.NotificationPreferences true "Preferences"
+// @Success 200 {object} string "Preferences updated"
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /users/me/notification-preferences [put]
+func (h *Handler) UpdateNotificationPreferences(c *gin.Context) {
+	h.Logger.Info("UpdateNotificationPreferences handler is invoked")
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.NotificationPreferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+	req.UpdatedAt = ""
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	if err := h.Storage.SetPreferences(ctx, userID, &req); err != nil {
+		handleError(c, h, err, "error updating preferences", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("UpdateNotificationPreferences handler is completed")
+	c.JSON(http.StatusOK, "Preferences updated")
+}
+
+// FetchMyDeliveries godoc
+// @Summary Fetches my notification deliveries
+// @Description Fetches the attempts to deliver the current user's notifications, newest first
+// @Tags notification
+// @Security ApiKeyAuth
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Success 200 {object} models.ListResp{data=[]models.DeliveryLog}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /users/me/notification-deliveries [get]
+func (h *Handler) FetchMyDeliveries(c *gin.Context) {
+	h.Logger.Info("FetchMyDeliveries handler is invoked")
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	resp, ok := paginate(c, h, func(ctx context.Context, page, limit int32) ([]*models.DeliveryLog, error) {
+		return h.Storage.ListDeliveries(ctx, userID, page, limit)
+	}, "error fetching deliveries")
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("FetchMyDeliveries handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// deliverEvents sends every new notification through the channels its
+// receiver chose. The instances share one consumer group, so every
+// notification is delivered once.
+func (h *Handler) deliverEvents(events consumer.IKafkaConsumer) {
+	h.consumeEvents(events, "delivery", h.deliverEvent)
+}
+
+func (h *Handler) deliverEvent(topic string, msg []byte) {
+	if topic != h.TopicNotificationCreated {
+		return
+	}
+
+	var n pbn.NewNotification
+	if err := json.Unmarshal(msg, &n); err != nil {
+		h.Logger.Error(errors.Wrap(err, "error reading notification for delivery").Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	prefs, err := h.preferences(ctx, n.UserId)
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error fetching preferences for delivery").Error())
+		return
+	}
+
+	channels, ok := prefs.Channels[n.Type]
+	if !ok {
+		channels = prefs.Channels[defaultChannels]
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	var to delivery.Recipient
+	profile, profileErr := h.User.GetProfile(ctx, &pbu.ID{Id: n.UserId})
+	if profileErr == nil {
+		to = delivery.Recipient{
+			UserID:     n.UserId,
+			Email:      profile.Email,
+			Phone:      profile.PhoneNumber,
+			PushTokens: prefs.PushTokens,
+			WebhookURL: prefs.WebhookURL,
+		}
+	}
+
+	for _, name := range channels {
+		attempt := &models.DeliveryLog{UserID: n.UserId, Type: n.Type, Channel: name, Status: deliverySent}
+
+		err := profileErr
+		if err == nil {
+			err = h.Channels[name].Send(ctx, to, delivery.Message{Type: n.Type, Title: n.Title, Message: n.Message})
+		}
+
+		switch {
+		case errors.Is(err, delivery.ErrNoAddress):
+			attempt.Status = deliverySkipped
+			attempt.Error = err.Error()
+		case err != nil:
+			h.Logger.Error(errors.Wrapf(err, "error delivering notification by %s", name).Error())
+			attempt.Status = deliveryFailed
+			attempt.Error = err.Error()
+		}
+
+		if err := h.Storage.LogDelivery(ctx, attempt); err != nil {
+			h.Logger.Error(errors.Wrap(err, "error logging delivery").Error())
+		}
+	}
+}
+
+// preferences returns the delivery preferences of the user, which are
+// empty until they set some.
+func (h *Handler) preferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	prefs, err := h.Storage.GetPreferences(ctx, userID)
+	if err == storage.ErrNotFound {
+		return &models.NotificationPreferences{Channels: map[string][]string{}, PushTokens: []string{}}, nil
+	}
+	return prefs, err
+}
@@ -0,0 +1,306 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/bookings"
+	pbpa "api-gateway/genproto/payments"
+	pbp "api-gateway/genproto/providers"
+	pbr "api-gateway/genproto/reviews"
+	pbs "api-gateway/genproto/services"
+	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	expandBooking  = "booking"
+	expandPayments = "payments"
+	expandProvider = "provider"
+	expandService  = "service"
+)
+
+// expandConcurrency bounds the number of lookups in flight for a single
+// expansion.
+const expandConcurrency = 8
+
+var (
+	bookingRelations = []string{expandProvider, expandService, expandPayments}
+	reviewRelations  = []string{expandBooking, expandProvider}
+)
+
+// parseExpand reads the expand query parameter, rejecting relations the
+// resource does not have.
+func parseExpand(c *gin.Context, allowed []string) (map[string]bool, error) {
+	expand := make(map[string]bool)
+
+	for _, rel := range strings.Split(c.Query("expand"), ",") {
+		rel = strings.TrimSpace(rel)
+		if rel == "" {
+			continue
+		}
+
+		known := false
+		for _, a := range allowed {
+			if rel == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, errors.Errorf("cannot expand %q, expected one of: %s", rel, strings.Join(allowed, ", "))
+		}
+
+		expand[rel] = true
+	}
+
+	return expand, nil
+}
+
+// expandBookingList replaces the bookings of a list page with their
+// expanded form when any relation was requested.
+func (h *Handler) expandBookingList(c *gin.Context, resp *models.ListResp, expand map[string]bool) {
+	if len(expand) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	resp.Data = h.expandBookings(ctx, c, resp.Data.([]*pb.Booking), expand)
+}
+
+// expandReviewList replaces the reviews of a list page with their
+// expanded form when any relation was requested.
+func (h *Handler) expandReviewList(c *gin.Context, resp *models.ListResp, expand map[string]bool) {
+	if len(expand) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	resp.Data = h.expandReviews(ctx, c, resp.Data.([]*pbr.Review), expand)
+}
+
+// expandBookings inlines the requested relations of the bookings. Each
+// related resource is fetched once however many bookings share it, and
+// payments are only shown for bookings the caller may access. Lookups
+// that fail are left out of the response.
+func (h *Handler) expandBookings(ctx context.Context, c *gin.Context, items []*pb.Booking,
+	expand map[string]bool) []*models.BookingExpanded {
+	providerIDs := make(map[string]bool)
+	serviceIDs := make(map[string]bool)
+	visible := make(map[string]bool)
+
+	canAccess := h.bookingAccess(c)
+	for _, b := range items {
+		if expand[expandProvider] {
+			providerIDs[b.ProviderId] = true
+		}
+		if expand[expandService] {
+			serviceIDs[b.ServiceId] = true
+		}
+		if expand[expandPayments] && canAccess(ctx, b) {
+			visible[b.Id] = true
+		}
+	}
+
+	var (
+		providers map[string]*pbp.Provider
+		services  map[string]*pbs.Service
+		payments  map[string][]*pbpa.Payment
+		wg        sync.WaitGroup
+	)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		providers = fetchEach(ctx, h, providerIDs, h.getProvider)
+	}()
+	go func() {
+		defer wg.Done()
+		services = fetchEach(ctx, h, serviceIDs, h.getService)
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		if payments, err = h.paymentsByBooking(ctx, visible); err != nil {
+			h.Logger.Warn("error expanding payments", "error", err)
+		}
+	}()
+	wg.Wait()
+
+	expanded := make([]*models.BookingExpanded, 0, len(items))
+	for _, b := range items {
+		e := &models.BookingExpanded{Booking: b}
+		if expand[expandProvider] {
+			e.Provider = providers[b.ProviderId]
+		}
+		if expand[expandService] {
+			e.Service = services[b.ServiceId]
+		}
+		if visible[b.Id] {
+			e.Payments = payments[b.Id]
+			if e.Payments == nil {
+				e.Payments = []*pbpa.Payment{}
+			}
+		}
+		expanded = append(expanded, e)
+	}
+
+	return expanded
+}
+
+// expandReviews inlines the requested relations of the reviews. Bookings
+// the caller may not access are left out.
+func (h *Handler) expandReviews(ctx context.Context, c *gin.Context, items []*pbr.Review,
+	expand map[string]bool) []*models.ReviewExpanded {
+	providerIDs := make(map[string]bool)
+	bookingIDs := make(map[string]bool)
+
+	for _, r := range items {
+		if expand[expandProvider] {
+			providerIDs[r.ProviderId] = true
+		}
+		if expand[expandBooking] {
+			bookingIDs[r.BookingId] = true
+		}
+	}
+
+	var (
+		providers map[string]*pbp.Provider
+		bookings  map[string]*pb.Booking
+		wg        sync.WaitGroup
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		providers = fetchEach(ctx, h, providerIDs, h.getProvider)
+	}()
+	go func() {
+		defer wg.Done()
+		bookings = fetchEach(ctx, h, bookingIDs, h.getBooking)
+	}()
+	wg.Wait()
+
+	canAccess := h.bookingAccess(c)
+	for id, b := range bookings {
+		if !canAccess(ctx, b) {
+			delete(bookings, id)
+		}
+	}
+
+	expanded := make([]*models.ReviewExpanded, 0, len(items))
+	for _, r := range items {
+		e := &models.ReviewExpanded{Review: r}
+		if expand[expandProvider] {
+			e.Provider = providers[r.ProviderId]
+		}
+		if expand[expandBooking] {
+			e.Booking = bookings[r.BookingId]
+		}
+		expanded = append(expanded, e)
+	}
+
+	return expanded
+}
+
+// bookingAccess returns a checker of the caller's access to bookings
+// that remembers provider ownership, so that a page of bookings from the
+// same provider costs a single lookup. It is safe for concurrent use.
+func (h *Handler) bookingAccess(c *gin.Context) func(context.Context, *pb.Booking) bool {
+	var mu sync.Mutex
+	owned := make(map[string]bool)
+
+	return func(ctx context.Context, b *pb.Booking) bool {
+		if role, _ := getUserRole(c); role != booking.RoleProvider {
+			return h.checkBookingAccess(ctx, c, b) == nil
+		}
+
+		mu.Lock()
+		ok, seen := owned[b.ProviderId]
+		mu.Unlock()
+
+		if !seen {
+			ok = h.checkProviderAccess(ctx, c, b.ProviderId) == nil
+
+			mu.Lock()
+			owned[b.ProviderId] = ok
+			mu.Unlock()
+		}
+		return ok
+	}
+}
+
+// paymentsByBooking groups the payments of the given bookings.
+func (h *Handler) paymentsByBooking(ctx context.Context, bookingIDs map[string]bool) (map[string][]*pbpa.Payment, error) {
+	grouped := make(map[string][]*pbpa.Payment)
+	if len(bookingIDs) == 0 {
+		return grouped, nil
+	}
+
+	payments, err := h.paymentScan.all(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching payments")
+	}
+
+	for _, p := range payments {
+		if bookingIDs[p.BookingId] {
+			grouped[p.BookingId] = append(grouped[p.BookingId], p)
+		}
+	}
+
+	return grouped, nil
+}
+
+// fetchEach looks up every distinct id concurrently and returns the ones
+// that were found.
+func fetchEach[T any](ctx context.Context, h *Handler, ids map[string]bool,
+	get func(context.Context, string) (T, error)) map[string]T {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		sem   = make(chan struct{}, expandConcurrency)
+		found = make(map[string]T, len(ids))
+	)
+
+	for id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			v, err := get(ctx, id)
+			if err != nil {
+				h.Logger.Warn("error expanding resource", "id", id, "error", err)
+				return
+			}
+
+			mu.Lock()
+			found[id] = v
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return found
+}
+
+func (h *Handler) getProvider(ctx context.Context, id string) (*pbp.Provider, error) {
+	return h.Provider.GetProvider(ctx, &pbp.ID{Id: id})
+}
+
+func (h *Handler) getService(ctx context.Context, id string) (*pbs.Service, error) {
+	return h.Service.GetService(ctx, &pbs.ID{Id: id})
+}
+
+func (h *Handler) getBooking(ctx context.Context, id string) (*pb.Booking, error) {
+	return h.Booking.GetBooking(ctx, &pb.ID{Id: id})
+}
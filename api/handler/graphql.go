@@ -0,0 +1,973 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	pbpa "api-gateway/genproto/payments"
+	pbp "api-gateway/genproto/providers"
+	pbr "api-gateway/genproto/reviews"
+	pbs "api-gateway/genproto/services"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"api-gateway/pkg/payment"
+	"api-gateway/pkg/validation"
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var graphqlSchema string
+
+// apiPrefix is where the REST routes are mounted, used to name the route
+// whose policy a GraphQL field follows.
+const apiPrefix = "/car-wash"
+
+// GraphQL godoc
+// @Summary Runs a GraphQL query
+// @Description Runs a GraphQL query or mutation. Every field applies the Casbin
+// @Description policy of the REST route that serves the same data.
+// @Tags graphql
+// @Security ApiKeyAuth
+// @Param data body models.GraphQLRequest true "Query, operation name and variables"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} string "Invalid data format"
+// @Router /graphql [post]
+func (h *Handler) GraphQL(c *gin.Context) {
+	h.Logger.Info("GraphQL handler is invoked")
+
+	var req models.GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	ctx = context.WithValue(ctx, graphqlRequestKey{}, &graphqlRequest{
+		gin:     c,
+		loaders: h.newLoaders(),
+		access:  h.bookingAccess(c),
+	})
+
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	for _, err := range resp.Errors {
+		h.Logger.Error(err.Error())
+	}
+
+	h.Logger.Info("GraphQL handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+type graphqlRequestKey struct{}
+
+// graphqlRequest is the state shared by the resolvers of one request.
+type graphqlRequest struct {
+	gin     *gin.Context
+	loaders *loaders
+	access  func(context.Context, *pbb.Booking) bool
+}
+
+func requestFrom(ctx context.Context) *graphqlRequest {
+	return ctx.Value(graphqlRequestKey{}).(*graphqlRequest)
+}
+
+// policyEnforcer is the part of the Casbin enforcer that the Check
+// middleware leaves on the request.
+type policyEnforcer interface {
+	Enforce(rvals ...interface{}) (bool, error)
+}
+
+// allow applies the Casbin policy of the given REST route to the caller,
+// so that GraphQL reaches nothing the REST API forbids.
+func allow(ctx context.Context, method, path string) (*gin.Context, error) {
+	c := requestFrom(ctx).gin
+
+	role, err := getUserRole(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	v, _ := c.Get("enforcer")
+	e, ok := v.(policyEnforcer)
+	if !ok {
+		return nil, newStatusError(nil, "policy could not be loaded", http.StatusInternalServerError)
+	}
+
+	path = apiPrefix + path
+	if ok, err := e.Enforce(role, path, method); !ok || err != nil {
+		msg := fmt.Sprintf("access denied: %s cannot %s %s", role, method, path)
+		return nil, newStatusError(err, msg, http.StatusForbidden)
+	}
+
+	return c, nil
+}
+
+// allowUser is allow for routes that act on the caller's own data.
+func allowUser(ctx context.Context, method, path string) (*gin.Context, string, error) {
+	c, err := allow(ctx, method, path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	id, err := getUserID(c)
+	if err != nil {
+		return nil, "", newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	return c, id, nil
+}
+
+// validateInput applies the validation rules of the REST request models
+// to a converted GraphQL input.
+func validateInput(req interface{}) error {
+	err := binding.Validator.ValidateStruct(req)
+	if err == nil {
+		return nil
+	}
+
+	fields := validation.Fields(err)
+	if fields == nil {
+		return newStatusError(err, "invalid data format", http.StatusBadRequest)
+	}
+
+	se := newStatusError(nil, "invalid data format", http.StatusBadRequest)
+	se.extra = gin.H{"fields": fields}
+	return se
+}
+
+type pageArgs struct {
+	Page  *int32
+	Limit *int32
+}
+
+// params applies the same default and maximum page size as the REST
+// list endpoints.
+func (a pageArgs) params(h *Handler) (int32, int32) {
+	page, limit := int32(1), h.DefaultPageLimit
+	if a.Page != nil && *a.Page > 0 {
+		page = *a.Page
+	}
+	if a.Limit != nil && *a.Limit > 0 {
+		limit = min(*a.Limit, h.MaxPageLimit)
+	}
+	return page, limit
+}
+
+type idArgs struct {
+	ID graphql.ID
+}
+
+// deref returns the value of an optional argument, or its zero value
+// when it was left out.
+func deref[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+	return v
+}
+
+func idStrings(ids []graphql.ID) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = string(id)
+	}
+	return s
+}
+
+func wrapAll[T, R any](items []T, wrap func(T) R) []R {
+	wrapped := make([]R, 0, len(items))
+	for _, item := range items {
+		wrapped = append(wrapped, wrap(item))
+	}
+	return wrapped
+}
+
+// graphqlResolver is the root of the GraphQL schema.
+type graphqlResolver struct {
+	h *Handler
+}
+
+func (r *graphqlResolver) Me(ctx context.Context) (*profileResolver, error) {
+	_, id, err := allowUser(ctx, http.MethodGet, "/users/profile")
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := r.h.User.GetProfile(ctx, &pbu.ID{Id: id})
+	if err != nil {
+		return nil, newStatusError(err, "error getting user profile", http.StatusInternalServerError)
+	}
+
+	return &profileResolver{p}, nil
+}
+
+func (r *graphqlResolver) Provider(ctx context.Context, args idArgs) (*providerResolver, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodGet, "/providers/"+id); err != nil {
+		return nil, err
+	}
+
+	return loadProvider(ctx, id)
+}
+
+func (r *graphqlResolver) Providers(ctx context.Context, args pageArgs) ([]*providerResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/providers/all"); err != nil {
+		return nil, err
+	}
+
+	page, limit := args.params(r.h)
+	items, err := r.h.providersPage(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching providers", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newProviderResolver), nil
+}
+
+func (r *graphqlResolver) Service(ctx context.Context, args idArgs) (*serviceResolver, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodGet, "/services/"+id); err != nil {
+		return nil, err
+	}
+
+	return loadService(ctx, id)
+}
+
+func (r *graphqlResolver) Services(ctx context.Context, args pageArgs) ([]*serviceResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/services/all"); err != nil {
+		return nil, err
+	}
+
+	page, limit := args.params(r.h)
+	items, err := r.h.servicesPage(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching services", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newServiceResolver), nil
+}
+
+func (r *graphqlResolver) Booking(ctx context.Context, args idArgs) (*bookingResolver, error) {
+	return loadOwnBooking(ctx, string(args.ID))
+}
+
+func (r *graphqlResolver) Bookings(ctx context.Context, args pageArgs) ([]*bookingResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/bookings/all"); err != nil {
+		return nil, err
+	}
+
+	page, limit := args.params(r.h)
+	items, err := r.h.bookingsPage(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching bookings", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newBookingResolver), nil
+}
+
+func (r *graphqlResolver) MyBookings(ctx context.Context, args pageArgs) ([]*bookingResolver, error) {
+	_, id, err := allowUser(ctx, http.MethodGet, "/users/me/bookings")
+	if err != nil {
+		return nil, err
+	}
+
+	mine := filterAll(r.h.bookingScan.pages, func(b *pbb.Booking) bool {
+		return b.UserId == id
+	})
+
+	page, limit := args.params(r.h)
+	items, err := mine(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching bookings", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newBookingResolver), nil
+}
+
+func (r *graphqlResolver) Payment(ctx context.Context, args idArgs) (*paymentResolver, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodGet, "/payments/"+id); err != nil {
+		return nil, err
+	}
+
+	p, err := r.h.Payment.GetPayment(ctx, &pbpa.ID{Id: id})
+	if err != nil {
+		return nil, newStatusError(err, "error finding payment", http.StatusInternalServerError)
+	}
+
+	b, err := loadBooking(ctx, p.BookingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkBookingAccessOf(ctx, b.b); err != nil {
+		return nil, err
+	}
+
+	return &paymentResolver{p}, nil
+}
+
+func (r *graphqlResolver) Payments(ctx context.Context, args pageArgs) ([]*paymentResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/payments/all"); err != nil {
+		return nil, err
+	}
+
+	page, limit := args.params(r.h)
+	items, err := r.h.paymentsPage(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching payments", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newPaymentResolver), nil
+}
+
+func (r *graphqlResolver) MyPayments(ctx context.Context, args pageArgs) ([]*paymentResolver, error) {
+	_, id, err := allowUser(ctx, http.MethodGet, "/users/me/payments")
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := r.h.bookingScan.all(ctx)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching bookings", http.StatusInternalServerError)
+	}
+
+	mine := make(map[string]bool)
+	for _, b := range bookings {
+		if b.UserId == id {
+			mine[b.Id] = true
+		}
+	}
+
+	scoped := filterAll(r.h.paymentScan.pages, func(p *pbpa.Payment) bool {
+		return mine[p.BookingId]
+	})
+
+	page, limit := args.params(r.h)
+	items, err := scoped(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching payments", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newPaymentResolver), nil
+}
+
+func (r *graphqlResolver) Review(ctx context.Context, args idArgs) (*reviewResolver, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodGet, "/reviews/"+id); err != nil {
+		return nil, err
+	}
+
+	rv, err := r.h.Review.GetReview(ctx, &pbr.ID{Id: id})
+	if err != nil {
+		return nil, newStatusError(err, "error getting review", http.StatusInternalServerError)
+	}
+
+	return &reviewResolver{rv}, nil
+}
+
+func (r *graphqlResolver) Reviews(ctx context.Context, args pageArgs) ([]*reviewResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/reviews/all"); err != nil {
+		return nil, err
+	}
+
+	page, limit := args.params(r.h)
+	items, err := r.h.reviewsPage(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching reviews", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newReviewResolver), nil
+}
+
+func (r *graphqlResolver) MyReviews(ctx context.Context, args pageArgs) ([]*reviewResolver, error) {
+	_, id, err := allowUser(ctx, http.MethodGet, "/users/me/reviews")
+	if err != nil {
+		return nil, err
+	}
+
+	mine := filterAll(r.h.reviewScan.pages, func(rv *pbr.Review) bool {
+		return rv.UserId == id
+	})
+
+	page, limit := args.params(r.h)
+	items, err := mine(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching reviews", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newReviewResolver), nil
+}
+
+func (r *graphqlResolver) Notification(ctx context.Context, args idArgs) (*notificationResolver, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodGet, "/notifications/"+id); err != nil {
+		return nil, err
+	}
+
+	n, err := r.h.getOwnNotification(ctx, requestFrom(ctx).gin, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationResolver{n}, nil
+}
+
+func (r *graphqlResolver) MyNotifications(ctx context.Context, args pageArgs) ([]*notificationResolver, error) {
+	_, id, err := allowUser(ctx, http.MethodGet, "/users/me/notifications")
+	if err != nil {
+		return nil, err
+	}
+
+	page, limit := args.params(r.h)
+	items, err := r.h.notificationsPage(id)(ctx, page, limit)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching notifications", http.StatusInternalServerError)
+	}
+
+	return wrapAll(items, newNotificationResolver), nil
+}
+
+func (r *graphqlResolver) UpdateProfile(ctx context.Context, args struct{ Input profileInput }) (*profileUpdateResolver, error) {
+	_, id, err := allowUser(ctx, http.MethodPut, "/users/profile")
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.UserUpdate{
+		Email:       args.Input.Email,
+		FirstName:   args.Input.FirstName,
+		LastName:    args.Input.LastName,
+		PhoneNumber: args.Input.PhoneNumber,
+	}
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.h.User.UpdateProfile(ctx, &pbu.NewData{
+		Id:          id,
+		Email:       req.Email,
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		PhoneNumber: req.PhoneNumber,
+	})
+	if err != nil {
+		return nil, newStatusError(err, "error updating user profile", http.StatusInternalServerError)
+	}
+
+	return &profileUpdateResolver{resp}, nil
+}
+
+func (r *graphqlResolver) CreateProvider(ctx context.Context, args struct{ Input providerInput }) (*createResultResolver, error) {
+	_, id, err := allowUser(ctx, http.MethodPost, "/providers/register")
+	if err != nil {
+		return nil, err
+	}
+
+	req := args.Input.model()
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.h.createProvider(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createResultResolver{id: resp.Id, createdAt: resp.CreatedAt}, nil
+}
+
+func (r *graphqlResolver) UpdateProvider(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input providerUpdateInput
+}) (*updateResultResolver, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodPut, "/providers/"+id); err != nil {
+		return nil, err
+	}
+
+	req := args.Input.model()
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.h.updateProvider(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updateResultResolver{id: id, updatedAt: resp.UpdatedAt}, nil
+}
+
+func (r *graphqlResolver) DeleteProvider(ctx context.Context, args idArgs) (string, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodDelete, "/providers/"+id); err != nil {
+		return "", err
+	}
+
+	if _, err := r.h.Provider.DeleteProvider(ctx, &pbp.ID{Id: id}); err != nil {
+		return "", newStatusError(err, "error deleting provider", http.StatusInternalServerError)
+	}
+
+	return "Provider deleted", nil
+}
+
+func (r *graphqlResolver) CreateService(ctx context.Context, args struct{ Input serviceInput }) (*createResultResolver, error) {
+	if _, err := allow(ctx, http.MethodPost, "/services"); err != nil {
+		return nil, err
+	}
+
+	req := models.ServiceCreate{
+		Name:        args.Input.Name,
+		Description: args.Input.Description,
+		Price:       float32(args.Input.Price),
+		Duration:    args.Input.Duration,
+	}
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.h.Service.CreateService(ctx, &pbs.NewService{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Duration:    req.Duration,
+	})
+	if err != nil {
+		return nil, newStatusError(err, "error creating service", http.StatusInternalServerError)
+	}
+
+	return &createResultResolver{id: resp.Id, createdAt: resp.CreatedAt}, nil
+}
+
+func (r *graphqlResolver) UpdateService(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input serviceUpdateInput
+}) (*updateResultResolver, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodPut, "/services/"+id); err != nil {
+		return nil, err
+	}
+
+	req := models.ServiceUpdate{
+		Name:        deref(args.Input.Name),
+		Description: deref(args.Input.Description),
+		Price:       float32(deref(args.Input.Price)),
+		Duration:    deref(args.Input.Duration),
+	}
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.h.Service.UpdateService(ctx, &pbs.NewData{
+		Id:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Duration:    req.Duration,
+	})
+	if err != nil {
+		return nil, newStatusError(err, "error updating service", http.StatusInternalServerError)
+	}
+
+	return &updateResultResolver{id: id, updatedAt: resp.UpdatedAt}, nil
+}
+
+func (r *graphqlResolver) DeleteService(ctx context.Context, args idArgs) (string, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodDelete, "/services/"+id); err != nil {
+		return "", err
+	}
+
+	if _, err := r.h.Service.DeleteService(ctx, &pbs.ID{Id: id}); err != nil {
+		return "", newStatusError(err, "error deleting service", http.StatusInternalServerError)
+	}
+
+	return "Service deleted successfully", nil
+}
+
+func (r *graphqlResolver) CreateBooking(ctx context.Context, args struct{ Input bookingInput }) (*bookingQuoteResolver, error) {
+	_, id, err := allowUser(ctx, http.MethodPost, "/bookings")
+	if err != nil {
+		return nil, err
+	}
+
+	req := args.Input.model()
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	quote, err := r.h.createBooking(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bookingQuoteResolver{message: "Booking created", price: quote}, nil
+}
+
+func (r *graphqlResolver) UpdateBooking(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input bookingUpdateInput
+}) (*bookingQuoteResolver, error) {
+	id := string(args.ID)
+	c, err := allow(ctx, http.MethodPut, "/bookings/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	req := args.Input.model()
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	quote, err := r.h.updateBooking(ctx, c, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bookingQuoteResolver{message: "Booking updated", price: quote}, nil
+}
+
+func (r *graphqlResolver) CancelBooking(ctx context.Context, args idArgs) (string, error) {
+	return r.changeBookingStatus(ctx, args.ID, "/cancel", booking.StatusCancelled, "Booking canceled")
+}
+
+func (r *graphqlResolver) ConfirmBooking(ctx context.Context, args idArgs) (string, error) {
+	return r.changeBookingStatus(ctx, args.ID, "/confirm", booking.StatusConfirmed, "Booking confirmed")
+}
+
+func (r *graphqlResolver) StartBooking(ctx context.Context, args idArgs) (string, error) {
+	return r.changeBookingStatus(ctx, args.ID, "/start", booking.StatusInProgress, "Booking started")
+}
+
+func (r *graphqlResolver) CompleteBooking(ctx context.Context, args idArgs) (string, error) {
+	return r.changeBookingStatus(ctx, args.ID, "/complete", booking.StatusCompleted, "Booking completed")
+}
+
+func (r *graphqlResolver) changeBookingStatus(ctx context.Context, id graphql.ID, action, status, done string) (string, error) {
+	c, err := allow(ctx, http.MethodPut, "/bookings/"+string(id)+action)
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.h.setBookingStatus(ctx, c, string(id), status); err != nil {
+		return "", err
+	}
+
+	return done, nil
+}
+
+func (r *graphqlResolver) CreatePayment(ctx context.Context, args struct{ Input paymentInput }) (*paymentIntentResolver, error) {
+	c, err := allow(ctx, http.MethodPost, "/payments")
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.PaymentCreate{BookingID: string(args.Input.BookingID)}
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	pi, _, err := r.h.createPaymentIntent(ctx, c, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentIntentResolver{pi}, nil
+}
+
+func (r *graphqlResolver) ConfirmPayment(ctx context.Context, args struct {
+	ID            graphql.ID
+	PaymentMethod string
+}) (*paymentIntentResolver, error) {
+	id := string(args.ID)
+	c, err := allow(ctx, http.MethodPost, "/payments/intents/"+id+"/confirm")
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.PaymentConfirm{PaymentMethod: args.PaymentMethod}
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	pi, err := r.h.confirmPaymentIntent(ctx, c, id, payment.Method{Token: req.PaymentMethod})
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymentIntentResolver{pi}, nil
+}
+
+func (r *graphqlResolver) CreateReview(ctx context.Context, args struct{ Input reviewInput }) (string, error) {
+	_, id, err := allowUser(ctx, http.MethodPost, "/reviews")
+	if err != nil {
+		return "", err
+	}
+
+	req := models.ReviewCreate{
+		BookingID:  string(args.Input.BookingID),
+		ProviderID: string(args.Input.ProviderID),
+		Rating:     args.Input.Rating,
+		Comment:    args.Input.Comment,
+	}
+	if err := validateInput(&req); err != nil {
+		return "", err
+	}
+
+	if err := r.h.createReview(ctx, id, req); err != nil {
+		return "", err
+	}
+
+	return "Review created", nil
+}
+
+func (r *graphqlResolver) UpdateReview(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input reviewUpdateInput
+}) (*updateResultResolver, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodPut, "/reviews/"+id); err != nil {
+		return nil, err
+	}
+
+	req := models.ReviewUpdate{
+		Rating:  deref(args.Input.Rating),
+		Comment: deref(args.Input.Comment),
+	}
+	if err := validateInput(&req); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.h.Review.UpdateReview(ctx, &pbr.NewData{
+		Id:      id,
+		Rating:  req.Rating,
+		Comment: req.Comment,
+	})
+	if err != nil {
+		return nil, newStatusError(err, "error updating review", http.StatusInternalServerError)
+	}
+
+	return &updateResultResolver{id: id, updatedAt: resp.UpdatedAt}, nil
+}
+
+func (r *graphqlResolver) DeleteReview(ctx context.Context, args idArgs) (string, error) {
+	id := string(args.ID)
+	if _, err := allow(ctx, http.MethodDelete, "/reviews/"+id); err != nil {
+		return "", err
+	}
+
+	if _, err := r.h.Review.DeleteReview(ctx, &pbr.ID{Id: id}); err != nil {
+		return "", newStatusError(err, "error deleting review", http.StatusInternalServerError)
+	}
+
+	return "Review deleted successfully", nil
+}
+
+func (r *graphqlResolver) CreateNotification(ctx context.Context, args struct{ Input notificationInput }) (string, error) {
+	if _, err := allow(ctx, http.MethodPost, "/notifications"); err != nil {
+		return "", err
+	}
+
+	req := models.NotificationCreate{
+		UserID:  string(args.Input.UserID),
+		Title:   args.Input.Title,
+		Message: args.Input.Message,
+	}
+	if err := validateInput(&req); err != nil {
+		return "", err
+	}
+
+	if err := r.h.createNotification(ctx, req); err != nil {
+		return "", err
+	}
+
+	return "Notification created", nil
+}
+
+func (r *graphqlResolver) MarkNotificationRead(ctx context.Context, args idArgs) (string, error) {
+	id := string(args.ID)
+	c, err := allow(ctx, http.MethodPut, "/notifications/"+id+"/read")
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.h.markNotificationRead(ctx, c, id); err != nil {
+		return "", err
+	}
+
+	return "Notification marked as read", nil
+}
+
+func (r *graphqlResolver) DeleteNotification(ctx context.Context, args idArgs) (string, error) {
+	id := string(args.ID)
+	c, err := allow(ctx, http.MethodDelete, "/notifications/"+id)
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.h.deleteNotification(ctx, c, id); err != nil {
+		return "", err
+	}
+
+	return "Notification deleted successfully", nil
+}
+
+type profileInput struct {
+	Email       string
+	FirstName   string
+	LastName    string
+	PhoneNumber string
+}
+
+type locationInput struct {
+	Address   string
+	City      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+func (in locationInput) model() models.Location {
+	return models.Location{
+		Address:   in.Address,
+		City:      in.City,
+		Country:   in.Country,
+		Latitude:  float32(in.Latitude),
+		Longitude: float32(in.Longitude),
+	}
+}
+
+type providerInput struct {
+	CompanyName   string
+	Description   string
+	Services      []graphql.ID
+	Availability  []string
+	AverageRating *float64
+	Location      locationInput
+}
+
+func (in providerInput) model() models.ProviderCreate {
+	return models.ProviderCreate{
+		CompanyName:   in.CompanyName,
+		Description:   in.Description,
+		Services:      idStrings(in.Services),
+		Availability:  in.Availability,
+		AverageRating: float32(deref(in.AverageRating)),
+		Location:      in.Location.model(),
+	}
+}
+
+type providerUpdateInput struct {
+	CompanyName   *string
+	Description   *string
+	Services      *[]graphql.ID
+	Availability  *[]string
+	AverageRating *float64
+	Location      *locationInput
+}
+
+func (in providerUpdateInput) model() models.ProviderUpdate {
+	req := models.ProviderUpdate{
+		CompanyName:   deref(in.CompanyName),
+		Description:   deref(in.Description),
+		Availability:  deref(in.Availability),
+		AverageRating: float32(deref(in.AverageRating)),
+	}
+	if in.Services != nil {
+		req.Services = idStrings(*in.Services)
+	}
+	if in.Location != nil {
+		l := in.Location.model()
+		req.Location = &l
+	}
+	return req
+}
+
+type serviceInput struct {
+	Name        string
+	Description string
+	Price       float64
+	Duration    int32
+}
+
+type serviceUpdateInput struct {
+	Name        *string
+	Description *string
+	Price       *float64
+	Duration    *int32
+}
+
+type bookingInput struct {
+	ProviderID    graphql.ID
+	ServiceID     graphql.ID
+	ScheduledTime string
+	Location      locationInput
+	TotalPrice    *float64
+}
+
+func (in bookingInput) model() models.BookingCreate {
+	req := models.BookingCreate{
+		ProviderID:    string(in.ProviderID),
+		ServiceID:     string(in.ServiceID),
+		ScheduledTime: in.ScheduledTime,
+		Location:      in.Location.model(),
+	}
+	if in.TotalPrice != nil {
+		req.TotalPrice = float32(*in.TotalPrice)
+	}
+	return req
+}
+
+type bookingUpdateInput struct {
+	ScheduledTime *string
+	Location      *locationInput
+}
+
+func (in bookingUpdateInput) model() models.BookingUpdate {
+	req := models.BookingUpdate{ScheduledTime: deref(in.ScheduledTime)}
+	if in.Location != nil {
+		l := in.Location.model()
+		req.Location = &l
+	}
+	return req
+}
+
+type paymentInput struct {
+	BookingID graphql.ID
+}
+
+type reviewInput struct {
+	BookingID  graphql.ID
+	ProviderID graphql.ID
+	Rating     int32
+	Comment    string
+}
+
+type reviewUpdateInput struct {
+	Rating  *int32
+	Comment *string
+}
+
+type notificationInput struct {
+	UserID  graphql.ID
+	Title   string
+	Message string
+}
+
+// checkBookingAccessOf is the GraphQL counterpart of checkBookingAccess,
+// sharing the ownership lookups of the request.
+func checkBookingAccessOf(ctx context.Context, b *pbb.Booking) error {
+	if !requestFrom(ctx).access(ctx, b) {
+		return newStatusError(nil, "access denied", http.StatusForbidden)
+	}
+	return nil
+}
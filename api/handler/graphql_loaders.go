@@ -0,0 +1,160 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	pbpa "api-gateway/genproto/payments"
+	pbp "api-gateway/genproto/providers"
+	pbr "api-gateway/genproto/reviews"
+	pbs "api-gateway/genproto/services"
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// loaders batch and cache the backend lookups of one GraphQL request, so
+// that a list of bookings sharing a provider fetches it once.
+type loaders struct {
+	provider          *dataloader.Loader
+	service           *dataloader.Loader
+	booking           *dataloader.Loader
+	paymentsByBooking *dataloader.Loader
+	reviewsByBooking  *dataloader.Loader
+	reviewsByProvider *dataloader.Loader
+}
+
+func (h *Handler) newLoaders() *loaders {
+	return &loaders{
+		provider: dataloader.NewBatchedLoader(batchGet(h.getProvider)),
+		service:  dataloader.NewBatchedLoader(batchGet(h.getService)),
+		booking:  dataloader.NewBatchedLoader(batchGet(h.getBooking)),
+		paymentsByBooking: dataloader.NewBatchedLoader(batchGroup(func(ctx context.Context) ([]*pbpa.Payment, error) {
+			return h.paymentScan.all(ctx)
+		}, func(p *pbpa.Payment) string {
+			return p.BookingId
+		})),
+		reviewsByBooking: dataloader.NewBatchedLoader(batchGroup(func(ctx context.Context) ([]*pbr.Review, error) {
+			return h.reviewScan.all(ctx)
+		}, func(r *pbr.Review) string {
+			return r.BookingId
+		})),
+		reviewsByProvider: dataloader.NewBatchedLoader(batchGroup(func(ctx context.Context) ([]*pbr.Review, error) {
+			return h.reviewScan.all(ctx)
+		}, func(r *pbr.Review) string {
+			return r.ProviderId
+		})),
+	}
+}
+
+// batchGet turns a lookup by id into a batch function that looks up the
+// keys of a batch concurrently.
+func batchGet[T any](get func(context.Context, string) (T, error)) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		var (
+			wg      sync.WaitGroup
+			sem     = make(chan struct{}, expandConcurrency)
+			results = make([]*dataloader.Result, len(keys))
+		)
+
+		for i, key := range keys {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				v, err := get(ctx, key.String())
+				results[i] = &dataloader.Result{Data: v, Error: err}
+			}()
+		}
+		wg.Wait()
+
+		return results
+	}
+}
+
+// batchGroup turns a full backend list into a batch function that groups
+// the items by the key the batch asks for. The backend has no lookups by
+// these keys, so the list is walked once per batch.
+func batchGroup[T any](list func(context.Context) ([]T, error), key func(T) string) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+
+		items, err := list(ctx)
+		if err != nil {
+			for i := range keys {
+				results[i] = &dataloader.Result{Error: err}
+			}
+			return results
+		}
+
+		grouped := make(map[string][]T)
+		for _, item := range items {
+			k := key(item)
+			grouped[k] = append(grouped[k], item)
+		}
+
+		for i, k := range keys {
+			group := grouped[k.String()]
+			if group == nil {
+				group = []T{}
+			}
+			results[i] = &dataloader.Result{Data: group}
+		}
+
+		return results
+	}
+}
+
+func load[T any](ctx context.Context, l *dataloader.Loader, key string) (T, error) {
+	v, err := l.Load(ctx, dataloader.StringKey(key))()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+func loadProvider(ctx context.Context, id string) (*providerResolver, error) {
+	p, err := load[*pbp.Provider](ctx, requestFrom(ctx).loaders.provider, id)
+	if err != nil {
+		return nil, newStatusError(err, "error finding provider", http.StatusInternalServerError)
+	}
+	return &providerResolver{p}, nil
+}
+
+func loadService(ctx context.Context, id string) (*serviceResolver, error) {
+	s, err := load[*pbs.Service](ctx, requestFrom(ctx).loaders.service, id)
+	if err != nil {
+		return nil, newStatusError(err, "error finding service", http.StatusInternalServerError)
+	}
+	return &serviceResolver{s}, nil
+}
+
+func loadBooking(ctx context.Context, id string) (*bookingResolver, error) {
+	b, err := load[*pbb.Booking](ctx, requestFrom(ctx).loaders.booking, id)
+	if err != nil {
+		return nil, newStatusError(err, "error finding booking", http.StatusInternalServerError)
+	}
+	return &bookingResolver{b}, nil
+}
+
+// loadOwnBooking loads a booking the route's policy lets the caller see
+// and that they take part in.
+func loadOwnBooking(ctx context.Context, id string) (*bookingResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/bookings/"+id); err != nil {
+		return nil, err
+	}
+
+	b, err := loadBooking(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkBookingAccessOf(ctx, b.b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
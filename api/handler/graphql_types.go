@@ -0,0 +1,283 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	pbn "api-gateway/genproto/notifications"
+	pbpa "api-gateway/genproto/payments"
+	pbp "api-gateway/genproto/providers"
+	pbr "api-gateway/genproto/reviews"
+	pbs "api-gateway/genproto/services"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/pricing"
+	"context"
+	"net/http"
+
+	"github.com/graph-gophers/dataloader"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type profileResolver struct{ p *pbu.Profile }
+
+func (r *profileResolver) Email() string       { return r.p.Email }
+func (r *profileResolver) FirstName() string   { return r.p.FirstName }
+func (r *profileResolver) LastName() string    { return r.p.LastName }
+func (r *profileResolver) PhoneNumber() string { return r.p.PhoneNumber }
+func (r *profileResolver) CreatedAt() string   { return r.p.CreatedAt }
+func (r *profileResolver) UpdatedAt() string   { return r.p.UpdatedAt }
+
+type profileUpdateResolver struct{ u *pbu.UpdateResp }
+
+func (r *profileUpdateResolver) ID() graphql.ID    { return graphql.ID(r.u.Id) }
+func (r *profileUpdateResolver) UpdatedAt() string { return r.u.UpdatedAt }
+
+type createResultResolver struct{ id, createdAt string }
+
+func (r *createResultResolver) ID() graphql.ID    { return graphql.ID(r.id) }
+func (r *createResultResolver) CreatedAt() string { return r.createdAt }
+
+type updateResultResolver struct{ id, updatedAt string }
+
+func (r *updateResultResolver) ID() graphql.ID    { return graphql.ID(r.id) }
+func (r *updateResultResolver) UpdatedAt() string { return r.updatedAt }
+
+type locationResolver struct {
+	address, city, country string
+	latitude, longitude    float32
+}
+
+func (r *locationResolver) Address() string    { return r.address }
+func (r *locationResolver) City() string       { return r.city }
+func (r *locationResolver) Country() string    { return r.country }
+func (r *locationResolver) Latitude() float64  { return float64(r.latitude) }
+func (r *locationResolver) Longitude() float64 { return float64(r.longitude) }
+
+type providerResolver struct{ p *pbp.Provider }
+
+func newProviderResolver(p *pbp.Provider) *providerResolver { return &providerResolver{p} }
+
+func (r *providerResolver) ID() graphql.ID         { return graphql.ID(r.p.Id) }
+func (r *providerResolver) UserID() graphql.ID     { return graphql.ID(r.p.UserId) }
+func (r *providerResolver) CompanyName() string    { return r.p.CompanyName }
+func (r *providerResolver) Description() string    { return r.p.Description }
+func (r *providerResolver) Availability() []string { return r.p.Availability }
+func (r *providerResolver) AverageRating() float64 { return float64(r.p.AverageRating) }
+func (r *providerResolver) CreatedAt() string      { return r.p.CreatedAt }
+func (r *providerResolver) UpdatedAt() string      { return r.p.UpdatedAt }
+
+func (r *providerResolver) Location() *locationResolver {
+	l := r.p.Location
+	if l == nil {
+		return nil
+	}
+	return &locationResolver{l.Address, l.City, l.Country, l.Latitude, l.Longitude}
+}
+
+func (r *providerResolver) Services(ctx context.Context) ([]*serviceResolver, error) {
+	for _, id := range r.p.Services {
+		if _, err := allow(ctx, http.MethodGet, "/services/"+id); err != nil {
+			return nil, err
+		}
+	}
+
+	thunk := requestFrom(ctx).loaders.service.LoadMany(ctx, dataloader.NewKeysFromStrings(r.p.Services))
+	items, errs := thunk()
+	for _, err := range errs {
+		if err != nil {
+			return nil, newStatusError(err, "error finding service", http.StatusInternalServerError)
+		}
+	}
+
+	services := make([]*serviceResolver, 0, len(items))
+	for _, item := range items {
+		services = append(services, &serviceResolver{item.(*pbs.Service)})
+	}
+	return services, nil
+}
+
+func (r *providerResolver) Reviews(ctx context.Context) ([]*reviewResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/providers/"+r.p.Id+"/reviews"); err != nil {
+		return nil, err
+	}
+
+	reviews, err := load[[]*pbr.Review](ctx, requestFrom(ctx).loaders.reviewsByProvider, r.p.Id)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching reviews", http.StatusInternalServerError)
+	}
+
+	return wrapAll(reviews, newReviewResolver), nil
+}
+
+type serviceResolver struct{ s *pbs.Service }
+
+func newServiceResolver(s *pbs.Service) *serviceResolver { return &serviceResolver{s} }
+
+func (r *serviceResolver) ID() graphql.ID       { return graphql.ID(r.s.Id) }
+func (r *serviceResolver) Name() string         { return r.s.Name }
+func (r *serviceResolver) Description() string  { return r.s.Description }
+func (r *serviceResolver) Price() float64       { return float64(r.s.Price) }
+func (r *serviceResolver) Duration() int32      { return r.s.Duration }
+func (r *serviceResolver) TotalBookings() int32 { return r.s.TotalBookings }
+func (r *serviceResolver) CreatedAt() string    { return r.s.CreatedAt }
+func (r *serviceResolver) UpdatedAt() string    { return r.s.UpdatedAt }
+
+type bookingResolver struct{ b *pbb.Booking }
+
+func newBookingResolver(b *pbb.Booking) *bookingResolver { return &bookingResolver{b} }
+
+func (r *bookingResolver) ID() graphql.ID         { return graphql.ID(r.b.Id) }
+func (r *bookingResolver) UserID() graphql.ID     { return graphql.ID(r.b.UserId) }
+func (r *bookingResolver) ProviderID() graphql.ID { return graphql.ID(r.b.ProviderId) }
+func (r *bookingResolver) ServiceID() graphql.ID  { return graphql.ID(r.b.ServiceId) }
+func (r *bookingResolver) Status() string         { return r.b.Status }
+func (r *bookingResolver) ScheduledTime() string  { return r.b.ScheduledTime }
+func (r *bookingResolver) TotalPrice() float64    { return float64(r.b.TotalPrice) }
+func (r *bookingResolver) CreatedAt() string      { return r.b.CreatedAt }
+func (r *bookingResolver) UpdatedAt() string      { return r.b.UpdatedAt }
+
+func (r *bookingResolver) Location() *locationResolver {
+	l := r.b.Location
+	if l == nil {
+		return nil
+	}
+	return &locationResolver{l.Address, l.City, l.Country, l.Latitude, l.Longitude}
+}
+
+func (r *bookingResolver) Provider(ctx context.Context) (*providerResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/providers/"+r.b.ProviderId); err != nil {
+		return nil, err
+	}
+
+	return loadProvider(ctx, r.b.ProviderId)
+}
+
+func (r *bookingResolver) Service(ctx context.Context) (*serviceResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/services/"+r.b.ServiceId); err != nil {
+		return nil, err
+	}
+
+	return loadService(ctx, r.b.ServiceId)
+}
+
+// Payments and Review follow the booking details endpoint, which shows
+// them only to the parties of the booking.
+func (r *bookingResolver) Payments(ctx context.Context) ([]*paymentResolver, error) {
+	if err := r.allowDetails(ctx); err != nil {
+		return nil, err
+	}
+
+	payments, err := load[[]*pbpa.Payment](ctx, requestFrom(ctx).loaders.paymentsByBooking, r.b.Id)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching payments", http.StatusInternalServerError)
+	}
+
+	return wrapAll(payments, newPaymentResolver), nil
+}
+
+func (r *bookingResolver) Review(ctx context.Context) (*reviewResolver, error) {
+	if err := r.allowDetails(ctx); err != nil {
+		return nil, err
+	}
+
+	reviews, err := load[[]*pbr.Review](ctx, requestFrom(ctx).loaders.reviewsByBooking, r.b.Id)
+	if err != nil {
+		return nil, newStatusError(err, "error fetching reviews", http.StatusInternalServerError)
+	}
+
+	if len(reviews) == 0 {
+		return nil, nil
+	}
+	return &reviewResolver{reviews[0]}, nil
+}
+
+func (r *bookingResolver) allowDetails(ctx context.Context) error {
+	if _, err := allow(ctx, http.MethodGet, "/bookings/"+r.b.Id+"/details"); err != nil {
+		return err
+	}
+	return checkBookingAccessOf(ctx, r.b)
+}
+
+type bookingQuoteResolver struct {
+	message string
+	price   pricing.Quote
+}
+
+func (r *bookingQuoteResolver) Message() string       { return r.message }
+func (r *bookingQuoteResolver) Price() *priceResolver { return &priceResolver{r.price} }
+
+type priceResolver struct{ q pricing.Quote }
+
+func (r *priceResolver) BasePrice() float64         { return float64(r.q.BasePrice) }
+func (r *priceResolver) DistanceKm() float64        { return float64(r.q.DistanceKm) }
+func (r *priceResolver) DistanceSurcharge() float64 { return float64(r.q.DistanceSurcharge) }
+func (r *priceResolver) PeakSurcharge() float64     { return float64(r.q.PeakSurcharge) }
+func (r *priceResolver) Tax() float64               { return float64(r.q.Tax) }
+func (r *priceResolver) TotalPrice() float64        { return float64(r.q.TotalPrice) }
+
+type paymentIntentResolver struct{ pi *models.PaymentIntent }
+
+func (r *paymentIntentResolver) ID() graphql.ID         { return graphql.ID(r.pi.ID) }
+func (r *paymentIntentResolver) BookingID() graphql.ID  { return graphql.ID(r.pi.BookingID) }
+func (r *paymentIntentResolver) Amount() float64        { return float64(r.pi.Amount) }
+func (r *paymentIntentResolver) Currency() string       { return r.pi.Currency }
+func (r *paymentIntentResolver) Status() string         { return r.pi.Status }
+func (r *paymentIntentResolver) PaymentMethod() string  { return r.pi.PaymentMethod }
+func (r *paymentIntentResolver) FailureMessage() string { return r.pi.FailureMessage }
+func (r *paymentIntentResolver) CreatedAt() string      { return r.pi.CreatedAt }
+func (r *paymentIntentResolver) UpdatedAt() string      { return r.pi.UpdatedAt }
+
+type paymentResolver struct{ p *pbpa.Payment }
+
+func newPaymentResolver(p *pbpa.Payment) *paymentResolver { return &paymentResolver{p} }
+
+func (r *paymentResolver) ID() graphql.ID        { return graphql.ID(r.p.Id) }
+func (r *paymentResolver) BookingID() graphql.ID { return graphql.ID(r.p.BookingId) }
+func (r *paymentResolver) Amount() float64       { return float64(r.p.Amount) }
+func (r *paymentResolver) Status() string        { return r.p.Status }
+func (r *paymentResolver) PaymentMethod() string { return r.p.PaymentMethod }
+func (r *paymentResolver) TransactionID() string { return r.p.TransactionId }
+func (r *paymentResolver) CreatedAt() string     { return r.p.CreatedAt }
+
+func (r *paymentResolver) Booking(ctx context.Context) (*bookingResolver, error) {
+	return loadOwnBooking(ctx, r.p.BookingId)
+}
+
+type reviewResolver struct{ r *pbr.Review }
+
+func newReviewResolver(r *pbr.Review) *reviewResolver { return &reviewResolver{r} }
+
+func (r *reviewResolver) ID() graphql.ID         { return graphql.ID(r.r.Id) }
+func (r *reviewResolver) BookingID() graphql.ID  { return graphql.ID(r.r.BookingId) }
+func (r *reviewResolver) UserID() graphql.ID     { return graphql.ID(r.r.UserId) }
+func (r *reviewResolver) ProviderID() graphql.ID { return graphql.ID(r.r.ProviderId) }
+func (r *reviewResolver) Rating() int32          { return r.r.Rating }
+func (r *reviewResolver) Comment() string        { return r.r.Comment }
+func (r *reviewResolver) CreatedAt() string      { return r.r.CreatedAt }
+func (r *reviewResolver) UpdatedAt() string      { return r.r.UpdatedAt }
+
+func (r *reviewResolver) Booking(ctx context.Context) (*bookingResolver, error) {
+	return loadOwnBooking(ctx, r.r.BookingId)
+}
+
+func (r *reviewResolver) Provider(ctx context.Context) (*providerResolver, error) {
+	if _, err := allow(ctx, http.MethodGet, "/providers/"+r.r.ProviderId); err != nil {
+		return nil, err
+	}
+
+	return loadProvider(ctx, r.r.ProviderId)
+}
+
+type notificationResolver struct{ n *pbn.Notification }
+
+func newNotificationResolver(n *pbn.Notification) *notificationResolver {
+	return &notificationResolver{n}
+}
+
+func (r *notificationResolver) ID() graphql.ID     { return graphql.ID(r.n.Id) }
+func (r *notificationResolver) UserID() graphql.ID { return graphql.ID(r.n.UserId) }
+func (r *notificationResolver) Title() string      { return r.n.Title }
+func (r *notificationResolver) Message() string    { return r.n.Message }
+func (r *notificationResolver) Type() string       { return r.n.Type }
+func (r *notificationResolver) IsRead() bool       { return r.n.IsRead }
+func (r *notificationResolver) ReadAt() string     { return r.n.ReadAt }
+func (r *notificationResolver) CreatedAt() string  { return r.n.CreatedAt }
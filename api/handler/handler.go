@@ -9,14 +9,28 @@ import (
 	pbr "api-gateway/genproto/reviews"
 	pbs "api-gateway/genproto/services"
 	pbu "api-gateway/genproto/user"
+	"api-gateway/kafka/consumer"
 	"api-gateway/kafka/producer"
 	"api-gateway/pkg"
+	"api-gateway/pkg/delivery"
 	"api-gateway/pkg/logger"
+	"api-gateway/pkg/notify"
+	"api-gateway/pkg/outbound"
+	"api-gateway/pkg/payment"
+	"api-gateway/pkg/pricing"
+	"api-gateway/pkg/stream"
+	"api-gateway/pkg/validation"
+	"api-gateway/pkg/webhook"
+	"api-gateway/storage"
+	"log"
 	"log/slog"
+	"math"
+	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/pkg/errors"
 )
 
@@ -35,14 +49,44 @@ type Handler struct {
 	TopicBookingUpdated      string
 	TopicBookingCancelled    string
 	TopicPaymentCreated      string
+	TopicPaymentUpdated      string
+	TopicPaymentRefunded     string
 	TopicReviewCreated       string
 	TopicNotificationCreated string
+	Pricing                  *pricing.Calculator
+	TimeZone                 *time.Location
+	DefaultPageLimit         int32
+	MaxPageLimit             int32
+	BatchMaxRequests         int
+	BatchMaxConcurrency      int
+	ImportMaxRows            int
+	Events                   *stream.Hub
+	StreamHeartbeat          time.Duration
+	StreamBufferSize         int
+	Templates                *notify.Templates
+	Locale                   string
+	Storage                  storage.IStorage
+	Channels                 map[string]delivery.Channel
+	ReminderOffsets          []time.Duration
+	Webhooks                 *webhook.Sender
+	WebhookMaxAttempts       int
+	WebhookRetryBackoff      time.Duration
+	Processor                payment.PaymentProcessor
+	Currency                 string
+	RefundPolicy             payment.RefundPolicy
+	CommissionRate           float64
+	Router                   http.Handler
+
+	schema      *graphql.Schema
+	bookingScan *listScan[*pbb.Booking]
+	paymentScan *listScan[*pbpa.Payment]
+	reviewScan  *listScan[*pbr.Review]
 }
 
 func NewHandler(cfg *config.Config) *Handler {
 	kafkaBrokerAddress := cfg.KAFKA_HOST + ":" + cfg.KAFKA_PORT
 
-	return &Handler{
+	h := &Handler{
 		User:                     pkg.NewUserClient(cfg),
 		Provider:                 pkg.NewProvidersClient(cfg),
 		Service:                  pkg.NewServicesClient(cfg),
@@ -57,16 +101,193 @@ func NewHandler(cfg *config.Config) *Handler {
 		TopicBookingUpdated:      cfg.KAFKA_TOPIC_BOOKING_UPDATED,
 		TopicBookingCancelled:    cfg.KAFKA_TOPIC_BOOKING_CANCELLED,
 		TopicPaymentCreated:      cfg.KAFKA_TOPIC_PAYMENT_CREATED,
+		TopicPaymentUpdated:      cfg.KAFKA_TOPIC_PAYMENT_UPDATED,
+		TopicPaymentRefunded:     cfg.KAFKA_TOPIC_PAYMENT_REFUNDED,
 		TopicReviewCreated:       cfg.KAFKA_TOPIC_REVIEW_CREATED,
 		TopicNotificationCreated: cfg.KAFKA_TOPIC_NOTIFICATION_CREATED,
+		Pricing:                  pricing.NewCalculator(cfg),
+		TimeZone:                 loadTimeZone(cfg.SCHEDULE_TIMEZONE),
+		DefaultPageLimit:         cfg.PAGINATION_DEFAULT_LIMIT,
+		MaxPageLimit:             cfg.PAGINATION_MAX_LIMIT,
+		BatchMaxRequests:         cfg.BATCH_MAX_REQUESTS,
+		BatchMaxConcurrency:      cfg.BATCH_MAX_CONCURRENCY,
+		ImportMaxRows:            cfg.IMPORT_MAX_ROWS,
+		Events:                   stream.NewHub(cfg.STREAM_HISTORY_SIZE),
+		StreamHeartbeat:          cfg.STREAM_HEARTBEAT_INTERVAL,
+		StreamBufferSize:         cfg.STREAM_BUFFER_SIZE,
+		Templates:                loadTemplates(cfg.NOTIFICATION_TEMPLATES, cfg.NOTIFICATION_LOCALE),
+		Locale:                   cfg.NOTIFICATION_LOCALE,
+		Storage:                  loadStorage(cfg),
+		ReminderOffsets:          parseReminderOffsets(cfg.REMINDER_OFFSETS),
+		Webhooks:                 &webhook.Sender{Client: outbound.NewClient(cfg.WEBHOOK_TIMEOUT)},
+		WebhookMaxAttempts:       cfg.WEBHOOK_MAX_ATTEMPTS,
+		WebhookRetryBackoff:      cfg.WEBHOOK_RETRY_BACKOFF,
+		Currency:                 cfg.PAYMENT_CURRENCY,
+		RefundPolicy:             loadRefundPolicy(cfg.REFUND_POLICY),
+		CommissionRate:           cfg.COMMISSION_RATE,
+	}
+	h.Channels = delivery.NewChannels(cfg, h.Logger)
+	h.Processor = payment.NewProcessor(cfg, h.Logger)
+	h.schema = graphql.MustParseSchema(graphqlSchema, &graphqlResolver{h})
+	h.bookingScan = newListScan(h.bookingsPage, cfg.LIST_SCAN_TTL, cfg.LIST_SCAN_MAX_ITEMS)
+	h.paymentScan = newListScan(h.paymentsPage, cfg.LIST_SCAN_TTL, cfg.LIST_SCAN_MAX_ITEMS)
+	h.reviewScan = newListScan(h.reviewsPage, cfg.LIST_SCAN_TTL, cfg.LIST_SCAN_MAX_ITEMS)
+
+	go h.relayEvents(consumer.NewKafkaConsumer(
+		[]string{kafkaBrokerAddress},
+		streamGroupID(cfg.STREAM_CONSUMER_GROUP),
+		[]string{cfg.KAFKA_TOPIC_BOOKING_UPDATED, cfg.KAFKA_TOPIC_BOOKING_CANCELLED, cfg.KAFKA_TOPIC_NOTIFICATION_CREATED},
+	))
+	go h.notifyEvents(consumer.NewKafkaConsumer(
+		[]string{kafkaBrokerAddress},
+		cfg.NOTIFIER_CONSUMER_GROUP,
+		[]string{
+			cfg.KAFKA_TOPIC_BOOKING_CREATED, cfg.KAFKA_TOPIC_BOOKING_UPDATED, cfg.KAFKA_TOPIC_BOOKING_CANCELLED,
+			cfg.KAFKA_TOPIC_PAYMENT_CREATED, cfg.KAFKA_TOPIC_PAYMENT_UPDATED,
+			cfg.KAFKA_TOPIC_PAYMENT_REFUNDED, cfg.KAFKA_TOPIC_REVIEW_CREATED,
+		},
+	))
+	go h.deliverEvents(consumer.NewKafkaConsumer(
+		[]string{kafkaBrokerAddress},
+		cfg.DELIVERY_CONSUMER_GROUP,
+		[]string{cfg.KAFKA_TOPIC_NOTIFICATION_CREATED},
+	))
+	go h.remindEvents(consumer.NewKafkaConsumer(
+		[]string{kafkaBrokerAddress},
+		cfg.REMINDER_CONSUMER_GROUP,
+		[]string{cfg.KAFKA_TOPIC_BOOKING_UPDATED, cfg.KAFKA_TOPIC_BOOKING_CANCELLED},
+	))
+	go h.runReminders(cfg.REMINDER_POLL_INTERVAL, cfg.REMINDER_SCAN_INTERVAL)
+	go h.webhookEvents(consumer.NewKafkaConsumer(
+		[]string{kafkaBrokerAddress},
+		cfg.WEBHOOK_CONSUMER_GROUP,
+		[]string{
+			cfg.KAFKA_TOPIC_BOOKING_CREATED, cfg.KAFKA_TOPIC_BOOKING_UPDATED, cfg.KAFKA_TOPIC_BOOKING_CANCELLED,
+			cfg.KAFKA_TOPIC_PAYMENT_CREATED, cfg.KAFKA_TOPIC_PAYMENT_UPDATED,
+			cfg.KAFKA_TOPIC_PAYMENT_REFUNDED, cfg.KAFKA_TOPIC_REVIEW_CREATED,
+		},
+	))
+	go h.runWebhookDeliveries(cfg.WEBHOOK_POLL_INTERVAL)
+	go h.runOutbox(cfg.OUTBOX_POLL_INTERVAL)
+	go h.ledgerEvents(consumer.NewKafkaConsumer(
+		[]string{kafkaBrokerAddress},
+		cfg.LEDGER_CONSUMER_GROUP,
+		[]string{
+			cfg.KAFKA_TOPIC_PAYMENT_CREATED, cfg.KAFKA_TOPIC_PAYMENT_UPDATED,
+			cfg.KAFKA_TOPIC_PAYMENT_REFUNDED, cfg.KAFKA_TOPIC_BOOKING_CANCELLED,
+		},
+	))
+
+	return h
+}
+
+func loadTimeZone(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Println(errors.Wrap(err, "failed to load time zone, falling back to UTC"))
+		return time.UTC
+	}
+
+	return loc
+}
+
+func loadStorage(cfg *config.Config) storage.IStorage {
+	s, err := storage.NewStorage(cfg)
+	if err != nil {
+		log.Println(errors.Wrap(err, "failed to set up storage"))
+	}
+
+	return s
+}
+
+func loadTemplates(path, locale string) *notify.Templates {
+	t, err := notify.Load(path, locale)
+	if err != nil {
+		log.Println(errors.Wrap(err, "failed to load notification templates, falling back to built-in ones"))
+	}
+
+	return t
+}
+
+func loadRefundPolicy(s string) payment.RefundPolicy {
+	p, err := payment.ParseRefundPolicy(s)
+	if err != nil {
+		log.Println(errors.Wrap(err, "failed to parse refund policy, falling back to full refunds"))
+		return payment.RefundPolicy{Tiers: []payment.RefundTier{{Notice: math.MinInt64, Percent: 100}}}
 	}
+
+	return p
 }
 
+// listAllLimit is the page size used when walking every page of a
+// backend list.
+const listAllLimit = 100
+
 func handleError(c *gin.Context, h *Handler, err error, msg string, code int) {
-	er := errors.Wrap(err, msg).Error()
-	c.AbortWithStatusJSON(code, gin.H{"error": er})
+	if err == nil {
+		err = errors.New(msg)
+	} else {
+		err = errors.Wrap(err, msg)
+	}
+
+	er := err.Error()
+	if fields := validation.Fields(errors.Cause(err)); fields != nil {
+		c.AbortWithStatusJSON(code, gin.H{"error": msg, "fields": fields})
+	} else {
+		c.AbortWithStatusJSON(code, gin.H{"error": er})
+	}
 	h.Logger.Error(er)
+}
 
+// statusError is a failure of the logic shared by the REST handlers and
+// the GraphQL resolvers, carrying the status and message it is reported
+// with.
+type statusError struct {
+	code  int
+	msg   string
+	err   error
+	extra gin.H
+}
+
+func newStatusError(err error, msg string, code int) *statusError {
+	return &statusError{code: code, msg: msg, err: err}
+}
+
+func (e *statusError) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+// Extensions exposes the status and any extra details to GraphQL clients.
+func (e *statusError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"status": e.code}
+	for k, v := range e.extra {
+		ext[k] = v
+	}
+	return ext
+}
+
+// respondError writes the response for an error returned by shared logic.
+func respondError(c *gin.Context, h *Handler, err error) {
+	se, ok := err.(*statusError)
+	if !ok {
+		handleError(c, h, err, "server error", http.StatusInternalServerError)
+		return
+	}
+
+	if se.extra == nil {
+		handleError(c, h, se.err, se.msg, se.code)
+		return
+	}
+
+	body := gin.H{"error": se.msg}
+	for k, v := range se.extra {
+		body[k] = v
+	}
+	c.AbortWithStatusJSON(se.code, body)
+	h.Logger.Error(se.Error())
 }
 
 func getUserID(c *gin.Context) (string, error) {
@@ -83,12 +304,26 @@ func getUserID(c *gin.Context) (string, error) {
 	return idStr, nil
 }
 
+func getUserRole(c *gin.Context) (string, error) {
+	role, ok := c.Get("user_role")
+	if !ok {
+		return "", errors.New("user role not found")
+	}
+
+	roleStr, ok := role.(string)
+	if !ok {
+		return "", errors.New("invalid user role")
+	}
+
+	return roleStr, nil
+}
+
 func parseIntQueryParam(queryParam string) (int32, error) {
 	if queryParam == "" {
 		return -1, errors.New("empty integer parameter")
 	}
 
-	value, err := strconv.Atoi(queryParam)
+	value, err := strconv.ParseInt(queryParam, 10, 32)
 	if err != nil || value < 1 {
 		return -1, errors.New("invalid integer parameter")
 	}
@@ -0,0 +1,568 @@
+package handler
+
+import (
+	pbp "api-gateway/genproto/providers"
+	pbs "api-gateway/genproto/services"
+	"api-gateway/models"
+	"api-gateway/pkg/validation"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pkg/errors"
+)
+
+const (
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// listSeparator joins the values of list columns in CSV files.
+const listSeparator = "|"
+
+const (
+	importCreated = "created"
+	importValid   = "valid"
+	importInvalid = "invalid"
+	importFailed  = "failed"
+)
+
+var (
+	providerColumns = []string{
+		"id", "user_id", "company_name", "description", "services", "availability", "average_rating",
+		"address", "city", "country", "latitude", "longitude", "created_at", "updated_at",
+	}
+	serviceColumns = []string{
+		"id", "name", "description", "price", "duration", "total_bookings", "created_at", "updated_at",
+	}
+)
+
+// providerImport is a provider row of an import file. Providers are
+// created on behalf of the user who owns them rather than the caller.
+type providerImport struct {
+	UserID string `json:"user_id"`
+	models.ProviderCreate
+}
+
+type providerOwner struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
+
+// validate checks the owner and the provider apart, so that the errors
+// are keyed by the same paths as in the JSON form of the row.
+func (p *providerImport) validate() map[string]string {
+	fields := validation.Fields(binding.Validator.ValidateStruct(&providerOwner{p.UserID}))
+	return mergeFields(fields, validation.Fields(binding.Validator.ValidateStruct(&p.ProviderCreate)))
+}
+
+func validateRow[T any](row *T) map[string]string {
+	return validation.Fields(binding.Validator.ValidateStruct(row))
+}
+
+// ImportProviders godoc
+// @Summary Imports providers
+// @Description Creates providers from a CSV file with a header row or from NDJSON, one provider per line.
+// @Description CSV columns: user_id, company_name, description, services, availability, average_rating,
+// @Description address, city, country, latitude, longitude; list columns are separated by "|".
+// @Description Every row is validated like a single provider registration and reported on its own,
+// @Description with field errors keyed by the JSON path of the field.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Accept text/csv,application/x-ndjson
+// @Param format query string false "csv or ndjson, taken from Content-Type by default"
+// @Param dry_run query bool false "Only validate the rows"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Router /admin/providers/import [post]
+func (h *Handler) ImportProviders(c *gin.Context) {
+	h.Logger.Info("ImportProviders handler is invoked")
+
+	report, ok := runImport(c, h, providerFromRecord, (*providerImport).validate, func(ctx context.Context, row providerImport) (string, error) {
+		resp, err := h.Provider.CreateProvider(ctx, &pbp.NewProvider{
+			UserId:        row.UserID,
+			CompanyName:   row.CompanyName,
+			Description:   row.Description,
+			Services:      row.Services,
+			Availability:  row.Availability,
+			AverageRating: row.AverageRating,
+			Location: &pbp.Location{
+				Address:   row.Location.Address,
+				City:      row.Location.City,
+				Country:   row.Location.Country,
+				Latitude:  row.Location.Latitude,
+				Longitude: row.Location.Longitude,
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Id, nil
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("ImportProviders handler is completed")
+	c.JSON(http.StatusOK, report)
+}
+
+// ImportServices godoc
+// @Summary Imports services
+// @Description Creates services from a CSV file with a header row or from NDJSON, one service per line.
+// @Description CSV columns: name, description, price, duration.
+// @Description Every row is validated like a single service creation and reported on its own.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Accept text/csv,application/x-ndjson
+// @Param format query string false "csv or ndjson, taken from Content-Type by default"
+// @Param dry_run query bool false "Only validate the rows"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Router /admin/services/import [post]
+func (h *Handler) ImportServices(c *gin.Context) {
+	h.Logger.Info("ImportServices handler is invoked")
+
+	report, ok := runImport(c, h, serviceFromRecord, validateRow[models.ServiceCreate], func(ctx context.Context, row models.ServiceCreate) (string, error) {
+		resp, err := h.Service.CreateService(ctx, &pbs.NewService{
+			Name:        row.Name,
+			Description: row.Description,
+			Price:       row.Price,
+			Duration:    row.Duration,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Id, nil
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("ImportServices handler is completed")
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportProviders godoc
+// @Summary Exports providers
+// @Description Streams every provider as CSV, in the column layout accepted by the import, or as NDJSON.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Produce text/csv,application/x-ndjson
+// @Param format query string false "csv (default) or ndjson"
+// @Success 200 {string} string "Providers"
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/providers/export [get]
+func (h *Handler) ExportProviders(c *gin.Context) {
+	h.Logger.Info("ExportProviders handler is invoked")
+
+	ok := runExport(c, h, "providers", h.providersPage, providerColumns, func(p *pbp.Provider) []string {
+		l := p.Location
+		if l == nil {
+			l = &pbp.Location{}
+		}
+
+		return []string{
+			p.Id, p.UserId, p.CompanyName, p.Description,
+			strings.Join(p.Services, listSeparator), strings.Join(p.Availability, listSeparator),
+			formatFloat(p.AverageRating), l.Address, l.City, l.Country,
+			formatFloat(l.Latitude), formatFloat(l.Longitude), p.CreatedAt, p.UpdatedAt,
+		}
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("ExportProviders handler is completed")
+}
+
+// ExportServices godoc
+// @Summary Exports services
+// @Description Streams every service as CSV, in the column layout accepted by the import, or as NDJSON.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Produce text/csv,application/x-ndjson
+// @Param format query string false "csv (default) or ndjson"
+// @Success 200 {string} string "Services"
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/services/export [get]
+func (h *Handler) ExportServices(c *gin.Context) {
+	h.Logger.Info("ExportServices handler is invoked")
+
+	ok := runExport(c, h, "services", h.servicesPage, serviceColumns, func(s *pbs.Service) []string {
+		return []string{
+			s.Id, s.Name, s.Description, formatFloat(s.Price),
+			strconv.Itoa(int(s.Duration)), strconv.Itoa(int(s.TotalBookings)), s.CreatedAt, s.UpdatedAt,
+		}
+	})
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("ExportServices handler is completed")
+}
+
+// runImport reads and validates every row of the uploaded file and,
+// unless this is a dry run, creates the valid ones. The file is read in
+// full first, so that a file that is malformed or too long as a whole
+// creates nothing. Past that, rows are independent: a bad row is reported
+// and the rest are still imported. It writes the error response itself
+// when the file cannot be read.
+func runImport[T any](c *gin.Context, h *Handler, fromRecord func(map[string]string) (T, map[string]string),
+	validate func(*T) map[string]string, create func(context.Context, T) (string, error)) (*models.ImportReport, bool) {
+	format, err := importFormat(c)
+	if err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return nil, false
+	}
+
+	type pending struct {
+		row    T
+		result models.ImportRow
+	}
+
+	var rows []pending
+	err = readRows(c.Request.Body, format, fromRecord, func(line int, row T, fields map[string]string, decodeErr error) error {
+		if len(rows) == h.ImportMaxRows {
+			return errors.Errorf("an import can hold at most %d rows", h.ImportMaxRows)
+		}
+
+		result := models.ImportRow{Line: line}
+		if decodeErr != nil {
+			result.Status = importInvalid
+			result.Error = decodeErr.Error()
+		} else if result.Fields = mergeFields(validate(&row), fields); result.Fields != nil {
+			result.Status = importInvalid
+		}
+
+		rows = append(rows, pending{row, result})
+		return nil
+	})
+	if err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return nil, false
+	}
+
+	report := &models.ImportReport{
+		Total:  len(rows),
+		DryRun: c.Query("dry_run") == "true",
+		Rows:   make([]models.ImportRow, 0, len(rows)),
+	}
+
+	for _, p := range rows {
+		result := p.result
+
+		switch {
+		case result.Status == importInvalid:
+			report.Failed++
+		case report.DryRun:
+			result.Status = importValid
+			report.Valid++
+		default:
+			ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+			id, err := create(ctx, p.row)
+			cancel()
+			if err != nil {
+				h.Logger.Error(errors.Wrapf(err, "error importing line %d", result.Line).Error())
+				result.Status = importFailed
+				result.Error = err.Error()
+				report.Failed++
+				break
+			}
+
+			result.Status = importCreated
+			result.ID = id
+			report.Valid++
+			report.Created++
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, true
+}
+
+// readRows decodes the rows of a CSV or NDJSON stream one at a time.
+// CSV values that cannot be parsed are passed on as field errors, and CSV
+// rows of the wrong width and NDJSON lines that cannot be decoded as a
+// decode error of the row, so a bad row never fails the whole stream.
+func readRows[T any](r io.Reader, format string, fromRecord func(map[string]string) (T, map[string]string),
+	emit func(line int, row T, fields map[string]string, decodeErr error) error) error {
+	if format == formatNDJSON {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		for line := 1; scanner.Scan(); line++ {
+			data := bytes.TrimSpace(scanner.Bytes())
+			if len(data) == 0 {
+				continue
+			}
+
+			var row T
+			decodeErr := json.Unmarshal(data, &row)
+			if decodeErr != nil {
+				decodeErr = errors.Wrap(decodeErr, "invalid JSON")
+			}
+
+			if err := emit(line, row, nil, decodeErr); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return errors.Wrap(err, "error reading CSV header")
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.ToLower(header[i]))
+	}
+	reader.FieldsPerRecord = -1
+
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "error reading CSV")
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if len(values) != len(header) {
+			var row T
+			widthErr := errors.Errorf("row has %d fields, expected %d", len(values), len(header))
+			if err := emit(line, row, nil, widthErr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		record := make(map[string]string, len(header))
+		for i, name := range header {
+			record[name] = strings.TrimSpace(values[i])
+		}
+
+		row, fields := fromRecord(record)
+		if err := emit(line, row, fields, nil); err != nil {
+			return err
+		}
+	}
+}
+
+func importFormat(c *gin.Context) (string, error) {
+	format := c.Query("format")
+	if format == "" {
+		switch c.ContentType() {
+		case "application/x-ndjson", "application/jsonl":
+			format = formatNDJSON
+		default:
+			format = formatCSV
+		}
+	}
+
+	if format != formatCSV && format != formatNDJSON {
+		return "", errors.Errorf("unsupported format %q, expected csv or ndjson", format)
+	}
+	return format, nil
+}
+
+// recordParser collects the errors of the columns of a CSV record that
+// are not plain text, keyed by the path of the field in the JSON form of
+// the row.
+type recordParser struct {
+	record map[string]string
+	fields map[string]string
+}
+
+func (p *recordParser) float(column, path string) float32 {
+	v := p.record[column]
+	if v == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(v, 32)
+	if err != nil {
+		p.fail(path, "must be a number")
+	}
+	return float32(f)
+}
+
+func (p *recordParser) int(column, path string) int32 {
+	v := p.record[column]
+	if v == "" {
+		return 0
+	}
+
+	i, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		p.fail(path, "must be a whole number")
+	}
+	return int32(i)
+}
+
+func (p *recordParser) list(column string) []string {
+	var items []string
+	for _, item := range strings.Split(p.record[column], listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (p *recordParser) fail(path, msg string) {
+	if p.fields == nil {
+		p.fields = make(map[string]string)
+	}
+	p.fields[path] = msg
+}
+
+func providerFromRecord(record map[string]string) (providerImport, map[string]string) {
+	p := &recordParser{record: record}
+
+	row := providerImport{
+		UserID: record["user_id"],
+		ProviderCreate: models.ProviderCreate{
+			CompanyName:   record["company_name"],
+			Description:   record["description"],
+			Services:      p.list("services"),
+			Availability:  p.list("availability"),
+			AverageRating: p.float("average_rating", "average_rating"),
+			Location: models.Location{
+				Address:   record["address"],
+				City:      record["city"],
+				Country:   record["country"],
+				Latitude:  p.float("latitude", "location.latitude"),
+				Longitude: p.float("longitude", "location.longitude"),
+			},
+		},
+	}
+
+	return row, p.fields
+}
+
+func serviceFromRecord(record map[string]string) (models.ServiceCreate, map[string]string) {
+	p := &recordParser{record: record}
+
+	row := models.ServiceCreate{
+		Name:        record["name"],
+		Description: record["description"],
+		Price:       p.float("price", "price"),
+		Duration:    p.int("duration", "duration"),
+	}
+
+	return row, p.fields
+}
+
+// runExport streams every item of a backend list page by page, so that
+// the whole list never has to be held in memory. Once the first page has
+// been written the status can no longer change, so later failures cut
+// the stream short and are only logged. It reports whether every item
+// was written.
+func runExport[T any](c *gin.Context, h *Handler, name string, fetch pageFetcher[T], columns []string,
+	toRecord func(T) []string) bool {
+	format := c.DefaultQuery("format", formatCSV)
+	if format != formatCSV && format != formatNDJSON {
+		err := errors.Errorf("unsupported format %q, expected csv or ndjson", format)
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return false
+	}
+
+	var (
+		csvWriter *csv.Writer
+		encoder   *json.Encoder
+	)
+
+	for page := int32(1); ; page++ {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), h.ContextTimeout)
+		items, err := fetch(ctx, page, listAllLimit)
+		cancel()
+		if err != nil {
+			if page == 1 {
+				handleError(c, h, err, "error fetching "+name, http.StatusInternalServerError)
+			} else {
+				h.Logger.Error(errors.Wrapf(err, "error exporting %s, stream cut short", name).Error())
+			}
+			return false
+		}
+
+		if page == 1 {
+			contentType := "text/csv"
+			if format == formatNDJSON {
+				contentType = "application/x-ndjson"
+			}
+
+			c.Header("Content-Type", contentType)
+			c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", name, format))
+			c.Status(http.StatusOK)
+
+			if format == formatCSV {
+				csvWriter = csv.NewWriter(c.Writer)
+				csvWriter.Write(columns)
+			} else {
+				encoder = json.NewEncoder(c.Writer)
+			}
+		}
+
+		for _, item := range items {
+			if format == formatCSV {
+				err = csvWriter.Write(toRecord(item))
+			} else {
+				err = encoder.Encode(item)
+			}
+			if err != nil {
+				h.Logger.Error(errors.Wrapf(err, "error exporting %s, stream cut short", name).Error())
+				return false
+			}
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+		}
+		c.Writer.Flush()
+
+		if len(items) < listAllLimit {
+			return true
+		}
+	}
+}
+
+// mergeFields adds the errors in extra to fields. A CSV value that could
+// not be parsed also fails validation as its zero value, so the parse
+// error takes precedence.
+func mergeFields(fields, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return fields
+	}
+	if fields == nil {
+		fields = make(map[string]string, len(extra))
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	return fields
+}
+
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
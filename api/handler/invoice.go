@@ -0,0 +1,277 @@
+package handler
+
+import (
+	pb "api-gateway/genproto/bookings"
+	pbs "api-gateway/genproto/services"
+	pbu "api-gateway/genproto/user"
+	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"api-gateway/pkg/invoice"
+	"api-gateway/pkg/notify"
+	"api-gateway/pkg/payment"
+	"api-gateway/pkg/pricing"
+	"api-gateway/storage"
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetBookingInvoice godoc
+// @Summary Gets booking invoice
+// @Description Gets the invoice of a completed booking as JSON, an HTML page or a PDF document. The invoice
+// @Description is issued the first time it is requested, with the provider's next sequential number, and keeps
+// @Description it after that. It lists the charges of the booking with their taxes, and its payments.
+// @Tags booking
+// @Security ApiKeyAuth
+// @Produce json,html,application/pdf
+// @Param id path string true "Booking ID"
+// @Param format query string false "Output format: json (default), html or pdf"
+// @Success 200 {object} invoice.Invoice
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Booking is not completed"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /bookings/{id}/invoice [get]
+func (h *Handler) GetBookingInvoice(c *gin.Context) {
+	h.Logger.Info("GetBookingInvoice handler is invoked")
+
+	format := c.DefaultQuery("format", invoice.FormatJSON)
+	if format != invoice.FormatJSON && format != invoice.FormatHTML && format != invoice.FormatPDF {
+		handleError(c, h, nil, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	inv, _, err := h.bookingInvoice(ctx, c, c.Param("id"))
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	if format == invoice.FormatJSON {
+		h.Logger.Info("GetBookingInvoice handler is completed")
+		c.JSON(http.StatusOK, inv)
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/html; charset=utf-8"
+	if format == invoice.FormatPDF {
+		contentType = "application/pdf"
+		err = inv.WritePDF(&buf)
+	} else {
+		err = inv.WriteHTML(&buf)
+	}
+	if err != nil {
+		handleError(c, h, err, "error rendering invoice", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("GetBookingInvoice handler is completed")
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", inv.Filename(format)))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// SendBookingInvoice godoc
+// @Summary Sends booking invoice
+// @Description Sends the customer of a completed booking a notification with its invoice, issuing the invoice
+// @Description if it was not yet.
+// @Tags booking
+// @Security ApiKeyAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} string "Invoice sent"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Booking is not completed"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /bookings/{id}/invoice/send [post]
+func (h *Handler) SendBookingInvoice(c *gin.Context) {
+	h.Logger.Info("SendBookingInvoice handler is invoked")
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	inv, b, err := h.bookingInvoice(ctx, c, c.Param("id"))
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	data := notify.Data{
+		BookingID:     b.Id,
+		Service:       inv.Service,
+		Provider:      inv.Seller.Name,
+		ScheduledTime: b.ScheduledTime,
+		Address:       inv.Location,
+		Status:        b.Status,
+		TotalPrice:    inv.Total,
+		InvoiceNumber: inv.Number,
+	}
+
+	msg, ok, err := h.Templates.Render(h.userLocale(ctx, b.UserId), notify.EventBookingInvoice, notify.Customer, data)
+	if err == nil && !ok {
+		err = errors.New("no invoice notification template")
+	}
+	if err != nil {
+		handleError(c, h, err, "error rendering invoice notification", http.StatusInternalServerError)
+		return
+	}
+
+	err = h.createNotification(ctx, models.NotificationCreate{
+		UserID:  b.UserId,
+		Title:   msg.Title,
+		Message: msg.Message,
+		Type:    notify.EventBookingInvoice,
+	})
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("SendBookingInvoice handler is completed")
+	c.JSON(http.StatusOK, "Invoice sent")
+}
+
+// bookingInvoice builds the invoice of a completed booking the caller may
+// access, issuing its number the first time.
+func (h *Handler) bookingInvoice(ctx context.Context, c *gin.Context, id string) (*invoice.Invoice, *pb.Booking, error) {
+	b, err := h.getBooking(ctx, id)
+	if err != nil {
+		return nil, nil, newStatusError(err, "error finding booking", http.StatusInternalServerError)
+	}
+
+	if err := h.checkBookingAccess(ctx, c, b); err != nil {
+		return nil, nil, newStatusError(err, "access denied", http.StatusForbidden)
+	}
+
+	if b.Status != booking.StatusCompleted {
+		return nil, nil, newStatusError(nil, "booking is not completed", http.StatusConflict)
+	}
+
+	provider, err := h.getProvider(ctx, b.ProviderId)
+	if err != nil {
+		return nil, nil, newStatusError(err, "error finding provider", http.StatusInternalServerError)
+	}
+
+	service, err := h.getService(ctx, b.ServiceId)
+	if err != nil {
+		return nil, nil, newStatusError(err, "error finding service", http.StatusInternalServerError)
+	}
+
+	customer, err := h.User.GetProfile(ctx, &pbu.ID{Id: b.UserId})
+	if err != nil {
+		return nil, nil, newStatusError(err, "error finding customer", http.StatusInternalServerError)
+	}
+
+	payments, err := h.paymentsByBooking(ctx, map[string]bool{b.Id: true})
+	if err != nil {
+		return nil, nil, newStatusError(err, "error fetching payments", http.StatusInternalServerError)
+	}
+
+	number, err := h.Storage.IssueInvoice(ctx, b.Id, b.ProviderId)
+	if err != nil {
+		return nil, nil, newStatusError(err, "error issuing invoice", http.StatusInternalServerError)
+	}
+
+	inv := &invoice.Invoice{
+		Number:        invoice.Number(b.ProviderId, number.Number),
+		IssuedAt:      number.IssuedAt.In(h.TimeZone),
+		BookingID:     b.Id,
+		Service:       service.Name,
+		ScheduledTime: b.ScheduledTime,
+		Location:      joinNonEmpty(b.Location.GetAddress(), b.Location.GetCity(), b.Location.GetCountry()),
+		Currency:      h.Currency,
+		Seller: invoice.Party{
+			Name: provider.CompanyName,
+			Address: joinNonEmpty(provider.GetLocation().GetAddress(), provider.GetLocation().GetCity(),
+				provider.GetLocation().GetCountry()),
+		},
+		Customer: invoice.Party{
+			Name:  strings.TrimSpace(customer.FirstName + " " + customer.LastName),
+			Email: customer.Email,
+			Phone: customer.PhoneNumber,
+		},
+		Total:    b.TotalPrice,
+		Payments: []invoice.Payment{},
+	}
+
+	scheduledAt, _ := time.Parse(time.RFC3339, b.ScheduledTime)
+	lat, lng := b.Location.GetLatitude(), b.Location.GetLongitude()
+	inv.Lines, inv.Subtotal, inv.Taxes = h.invoiceCharges(h.quoteBooking(service, provider, scheduledAt, lat, lng), service, b.TotalPrice)
+
+	for _, p := range payments[b.Id] {
+		inv.Payments = append(inv.Payments, invoice.Payment{
+			TransactionID: p.TransactionId,
+			Method:        p.PaymentMethod,
+			Status:        p.Status,
+			Amount:        p.Amount,
+			Date:          p.CreatedAt,
+		})
+		if p.Status == payment.StatusSucceeded {
+			inv.AmountPaid += p.Amount
+		}
+	}
+
+	pi, err := h.Storage.GetActivePaymentIntent(ctx, b.Id)
+	if err != nil && err != storage.ErrNotFound {
+		return nil, nil, newStatusError(err, "error finding payment intent", http.StatusInternalServerError)
+	}
+	if pi != nil {
+		inv.AmountRefunded = pi.AmountRefunded
+	}
+
+	inv.AmountDue = float32(math.Max(0, roundCents(float64(inv.Total-inv.AmountPaid))))
+
+	return inv, b, nil
+}
+
+// invoiceCharges breaks the total of a booking down into charges and
+// taxes. The booking only keeps its total, so the breakdown is quoted
+// again; if prices changed since it was booked, the total is split into
+// the service and its tax instead.
+func (h *Handler) invoiceCharges(quote pricing.Quote, service *pbs.Service, total float32) ([]invoice.Line, float32, []invoice.TaxLine) {
+	tax := invoice.TaxLine{Name: "Tax", Rate: h.Pricing.TaxRate}
+
+	if !quote.Matches(total) {
+		subtotal := float32(roundCents(float64(total) / (1 + h.Pricing.TaxRate)))
+		tax.Amount = float32(roundCents(float64(total - subtotal)))
+		return []invoice.Line{{Description: service.Name, Amount: subtotal}}, subtotal, []invoice.TaxLine{tax}
+	}
+
+	lines := []invoice.Line{{Description: service.Name, Amount: quote.BasePrice}}
+	if quote.DistanceSurcharge > 0 {
+		lines = append(lines, invoice.Line{
+			Description: fmt.Sprintf("Distance surcharge (%.2f km)", quote.DistanceKm),
+			Amount:      quote.DistanceSurcharge,
+		})
+	}
+	if quote.PeakSurcharge > 0 {
+		lines = append(lines, invoice.Line{Description: "Peak hours surcharge", Amount: quote.PeakSurcharge})
+	}
+
+	tax.Amount = quote.Tax
+	subtotal := quote.BasePrice + quote.DistanceSurcharge + quote.PeakSurcharge
+	return lines, float32(roundCents(float64(subtotal))), []invoice.TaxLine{tax}
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func joinNonEmpty(parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
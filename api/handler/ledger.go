@@ -0,0 +1,406 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	pbpa "api-gateway/genproto/payments"
+	"api-gateway/kafka/consumer"
+	"api-gateway/models"
+	"api-gateway/pkg/ledger"
+	"api-gateway/pkg/payment"
+	"api-gateway/storage"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// Earnings are summed up by these periods. By default they cover the 30
+// days, 12 weeks or 12 months up to the last day, whole weeks starting on
+// Monday as date_trunc has them.
+var earningsPeriods = map[string]func(to time.Time) time.Time{
+	"day": func(to time.Time) time.Time { return to.AddDate(0, 0, -29) },
+	"week": func(to time.Time) time.Time {
+		monday := to.AddDate(0, 0, -(int(to.Weekday())+6)%7)
+		return monday.AddDate(0, 0, -7*11)
+	},
+	"month": func(to time.Time) time.Time {
+		return time.Date(to.Year(), to.Month()-11, 1, 0, 0, 0, 0, to.Location())
+	},
+}
+
+var payoutColumns = []string{"batch_id", "transaction_id", "provider_id", "company_name", "amount", "currency", "created_at"}
+
+// ledgerEvents posts the payments and refunds of bookings to the ledger.
+// Transactions are posted once by the ID of what they record, so events
+// that are delivered again, or a refund that is seen both in
+// payment_refunded and booking_cancelled, change nothing.
+func (h *Handler) ledgerEvents(events consumer.IKafkaConsumer) {
+	h.consumeEvents(events, "ledger", h.ledgerEvent)
+}
+
+func (h *Handler) ledgerEvent(topic string, msg []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	var err error
+
+	switch topic {
+	case h.TopicPaymentCreated:
+		var p pbpa.NewPayment
+		if err = json.Unmarshal(msg, &p); err == nil && p.Status == payment.StatusSucceeded {
+			err = h.postPayment(ctx, p.BookingId, p.TransactionId, p.Amount)
+		}
+	case h.TopicPaymentUpdated:
+		// A payment that was processing settled.
+		var p pbpa.PaymentUpdate
+		if err = json.Unmarshal(msg, &p); err == nil && p.Status == payment.StatusSucceeded {
+			err = h.postPayment(ctx, p.BookingId, p.TransactionId, p.Amount)
+		}
+	case h.TopicPaymentRefunded:
+		var r pbpa.PaymentRefund
+		if err = json.Unmarshal(msg, &r); err == nil && r.Status != payment.StatusFailed {
+			err = h.postRefund(ctx, r.Id, r.BookingId, r.Amount)
+		}
+	case h.TopicBookingCancelled:
+		// Cancelling a paid booking refunds it; the refunds made by then
+		// are posted here.
+		var b pbb.ID
+		if err = json.Unmarshal(msg, &b); err != nil {
+			break
+		}
+
+		var refunds []*models.Refund
+		if refunds, err = h.Storage.ListBookingRefunds(ctx, b.Id); err != nil {
+			break
+		}
+		for _, r := range refunds {
+			if err = h.postRefund(ctx, r.ID, r.BookingID, r.Amount); err != nil {
+				break
+			}
+		}
+	default:
+		return
+	}
+
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error posting to ledger").Error(), "topic", topic)
+	}
+}
+
+// postPayment posts a succeeded payment of a booking at the provider's
+// commission rate.
+func (h *Handler) postPayment(ctx context.Context, bookingID, transactionID string, amount float32) error {
+	b, err := h.getBooking(ctx, bookingID)
+	if err != nil {
+		return errors.Wrap(err, "error finding booking")
+	}
+
+	rate, err := h.commissionRate(ctx, b.ProviderId)
+	if err != nil {
+		return err
+	}
+
+	t := ledger.Payment("payment:"+transactionID, b.ProviderId, b.Id, payment.ToMinor(amount), rate, h.Currency)
+	_, err = h.Storage.PostLedgerTransaction(ctx, &t)
+	return err
+}
+
+// postRefund posts a refund of a booking at the rate its payment was
+// posted with. When the refund comes in first, the payment is posted
+// from its intent before it.
+func (h *Handler) postRefund(ctx context.Context, refundID, bookingID string, amount float32) error {
+	b, err := h.getBooking(ctx, bookingID)
+	if err != nil {
+		return errors.Wrap(err, "error finding booking")
+	}
+
+	rate, err := h.Storage.GetPaymentCommissionRate(ctx, b.Id)
+	if err == storage.ErrNotFound {
+		pi, perr := h.Storage.GetActivePaymentIntent(ctx, b.Id)
+		if perr != nil {
+			return errors.Wrap(perr, "error finding payment intent")
+		}
+		if perr = h.postPayment(ctx, b.Id, pi.ProcessorIntentID, pi.Amount); perr != nil {
+			return perr
+		}
+		rate, err = h.Storage.GetPaymentCommissionRate(ctx, b.Id)
+	}
+	if err != nil {
+		return errors.Wrap(err, "error finding commission rate")
+	}
+
+	t := ledger.Refund("refund:"+refundID, b.ProviderId, b.Id, payment.ToMinor(amount), rate, h.Currency)
+	_, err = h.Storage.PostLedgerTransaction(ctx, &t)
+	return err
+}
+
+// commissionRate returns the commission rate of a provider, or the
+// default one if it has none of its own.
+func (h *Handler) commissionRate(ctx context.Context, providerID string) (float64, error) {
+	rate, err := h.Storage.GetCommissionRate(ctx, providerID)
+	if err == storage.ErrNotFound {
+		return h.CommissionRate, nil
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "error finding commission rate")
+	}
+	return rate, nil
+}
+
+// GetProviderEarnings godoc
+// @Summary Gets provider earnings
+// @Description Sums up what a provider earned by day, week or month, from the ledger: the payments of their
+// @Description bookings, the refunds, the platform's commission, what is left to the provider and what was paid
+// @Description out to them. Dates are inclusive and in the schedule time zone; by default the range ends today
+// @Description and covers 30 days, 12 weeks or 12 months. The balance is what the provider is owed now.
+// @Tags provider
+// @Security ApiKeyAuth
+// @Param id path string true "Provider ID"
+// @Param period query string false "day, week or month (default)"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Success 200 {object} models.Earnings
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /providers/{id}/earnings [get]
+func (h *Handler) GetProviderEarnings(c *gin.Context) {
+	h.Logger.Info("GetProviderEarnings handler is invoked")
+
+	id := c.Param("id")
+	period := c.DefaultQuery("period", "month")
+	defaultFrom, ok := earningsPeriods[period]
+	if !ok {
+		handleError(c, h, nil, "invalid period", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().In(h.TimeZone)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, h.TimeZone)
+	if s := c.Query("to"); s != "" {
+		var err error
+		if to, err = time.ParseInLocation(time.DateOnly, s, h.TimeZone); err != nil {
+			handleError(c, h, err, "invalid to date", http.StatusBadRequest)
+			return
+		}
+	}
+
+	from := defaultFrom(to)
+	if s := c.Query("from"); s != "" {
+		var err error
+		if from, err = time.ParseInLocation(time.DateOnly, s, h.TimeZone); err != nil {
+			handleError(c, h, err, "invalid from date", http.StatusBadRequest)
+			return
+		}
+	}
+	if from.After(to) {
+		handleError(c, h, nil, "from is after to", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	if err := h.checkProviderAccess(ctx, c, id); err != nil {
+		handleError(c, h, err, "access denied", http.StatusForbidden)
+		return
+	}
+
+	rate, err := h.commissionRate(ctx, id)
+	if err != nil {
+		handleError(c, h, err, "error finding commission rate", http.StatusInternalServerError)
+		return
+	}
+
+	periods, err := h.Storage.ProviderEarnings(ctx, id, period, from, to.AddDate(0, 0, 1), h.TimeZone)
+	if err != nil {
+		handleError(c, h, err, "error fetching earnings", http.StatusInternalServerError)
+		return
+	}
+
+	balance, err := h.Storage.ProviderBalance(ctx, id)
+	if err != nil {
+		handleError(c, h, err, "error fetching balance", http.StatusInternalServerError)
+		return
+	}
+
+	resp := models.Earnings{
+		ProviderID:     id,
+		Currency:       h.Currency,
+		CommissionRate: rate,
+		Period:         period,
+		From:           from.Format(time.DateOnly),
+		To:             to.Format(time.DateOnly),
+		Periods:        periods,
+		Total:          models.EarningsPeriod{Start: from.Format(time.DateOnly)},
+		Balance:        balance,
+	}
+
+	var gross, refunded, commission, net, paidOut int64
+	for _, p := range periods {
+		gross += payment.ToMinor(p.Gross)
+		refunded += payment.ToMinor(p.Refunded)
+		commission += payment.ToMinor(p.Commission)
+		net += payment.ToMinor(p.Net)
+		paidOut += payment.ToMinor(p.PaidOut)
+	}
+	resp.Total.Gross = payment.FromMinor(gross)
+	resp.Total.Refunded = payment.FromMinor(refunded)
+	resp.Total.Commission = payment.FromMinor(commission)
+	resp.Total.Net = payment.FromMinor(net)
+	resp.Total.PaidOut = payment.FromMinor(paidOut)
+
+	h.Logger.Info("GetProviderEarnings handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetProviderCommission godoc
+// @Summary Sets provider commission
+// @Description Sets the share of a provider's payments the platform keeps, between 0 and 1. It applies to
+// @Description payments posted from now on; payments posted before, and their refunds, keep their rate.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "Provider ID"
+// @Param data body models.CommissionUpdate true "Commission rate"
+// @Success 200 {object} models.Commission
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/providers/{id}/commission [put]
+func (h *Handler) SetProviderCommission(c *gin.Context) {
+	h.Logger.Info("SetProviderCommission handler is invoked")
+
+	var req models.CommissionUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	provider, err := h.getProvider(ctx, c.Param("id"))
+	if err != nil {
+		handleError(c, h, err, "error finding provider", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.Storage.SetCommissionRate(ctx, provider.Id, *req.Rate, userID); err != nil {
+		handleError(c, h, err, "error saving commission rate", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("SetProviderCommission handler is completed")
+	c.JSON(http.StatusOK, models.Commission{ProviderID: provider.Id, Rate: *req.Rate})
+}
+
+// CreatePayoutBatch godoc
+// @Summary Creates payout batch
+// @Description Pays every provider who is owed money their whole balance, as one batch. Each payout is posted
+// @Description to the ledger; the batch is then exported as CSV for the bank transfers. Providers whose
+// @Description balance is negative, after refunds of bookings they were already paid for, are left out until
+// @Description their earnings make up for it.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Success 201 {object} models.PayoutBatch
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "No provider is owed money"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/payouts [post]
+func (h *Handler) CreatePayoutBatch(c *gin.Context) {
+	h.Logger.Info("CreatePayoutBatch handler is invoked")
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	b := &models.PayoutBatch{Currency: h.Currency, CreatedBy: userID}
+	err = h.Storage.CreatePayoutBatch(ctx, b)
+	if err == storage.ErrConflict {
+		handleError(c, h, nil, "no provider is owed money", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		handleError(c, h, err, "error creating payout batch", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("CreatePayoutBatch handler is completed")
+	c.JSON(http.StatusCreated, b)
+}
+
+// ExportPayoutBatch godoc
+// @Summary Exports payout batch
+// @Description Exports the payouts of a batch as CSV, one row per provider with their company name. The
+// @Description transaction ID is the payout's ledger transaction, for reconciliation.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Produce text/csv
+// @Param id path string true "Payout batch ID"
+// @Success 200 {string} string "Payouts"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Payout batch not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /admin/payouts/{id}/export [get]
+func (h *Handler) ExportPayoutBatch(c *gin.Context) {
+	h.Logger.Info("ExportPayoutBatch handler is invoked")
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	b, err := h.Storage.GetPayoutBatch(ctx, c.Param("id"))
+	if err == storage.ErrNotFound {
+		handleError(c, h, err, "payout batch not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		handleError(c, h, err, "error finding payout batch", http.StatusInternalServerError)
+		return
+	}
+
+	// Names are looked up before anything is written, so that a failure
+	// can still be reported.
+	records := make([][]string, 0, len(b.Payouts))
+	for _, p := range b.Payouts {
+		provider, err := h.getProvider(ctx, p.ProviderID)
+		if err != nil {
+			handleError(c, h, err, "error finding provider", http.StatusInternalServerError)
+			return
+		}
+
+		records = append(records, []string{
+			b.ID, p.TransactionID, p.ProviderID, provider.CompanyName,
+			fmt.Sprintf("%.2f", p.Amount), p.Currency, b.CreatedAt,
+		})
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=payouts-%s.%s", b.ID, formatCSV))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write(payoutColumns)
+	w.WriteAll(records)
+	if err := w.Error(); err != nil {
+		h.Logger.Error(errors.Wrap(err, "error exporting payout batch").Error())
+		return
+	}
+
+	h.Logger.Info("ExportPayoutBatch handler is completed")
+}
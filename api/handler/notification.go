@@ -2,6 +2,8 @@ package handler
 
 import (
 	pbn "api-gateway/genproto/notifications"
+	"api-gateway/models"
+	"api-gateway/pkg/booking"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -14,7 +16,7 @@ import (
 // @Description Adds a new notification
 // @Tags notification
 // @Security ApiKeyAuth
-// @Param data body notifications.NewNotification true "Receiver ID, Title and Message"
+// @Param data body models.NotificationCreate true "Receiver ID, Title and Message"
 // @Success 201 {object} string "Notification created"
 // @Failure 400 {object} string "Invalid data format"
 // @Failure 500 {object} string "Server error while processing request"
@@ -22,24 +24,17 @@ import (
 func (h *Handler) CreateNotification(c *gin.Context) {
 	h.Logger.Info("CreateNotification handler is invoked")
 
-	var req pbn.NewNotification
+	var req models.NotificationCreate
 	if err := c.ShouldBind(&req); err != nil {
 		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
 		return
 	}
 
-	message, err := json.Marshal(&req)
-	if err != nil {
-		handleError(c, h, err, "error serializing notification", http.StatusInternalServerError)
-		return
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
-	err = h.KafkaProducer.Produce(ctx, h.TopicNotificationCreated, []byte(message))
-	if err != nil {
-		handleError(c, h, err, "error creating notification", http.StatusInternalServerError)
+	if err := h.createNotification(ctx, req); err != nil {
+		respondError(c, h, err)
 		return
 	}
 
@@ -47,14 +42,35 @@ func (h *Handler) CreateNotification(c *gin.Context) {
 	c.JSON(http.StatusCreated, "Notification created")
 }
 
+// createNotification publishes a notification for its receiver.
+func (h *Handler) createNotification(ctx context.Context, req models.NotificationCreate) error {
+	message, err := json.Marshal(&pbn.NewNotification{
+		UserId:  req.UserID,
+		Title:   req.Title,
+		Message: req.Message,
+		Type:    req.Type,
+	})
+	if err != nil {
+		return newStatusError(err, "error serializing notification", http.StatusInternalServerError)
+	}
+
+	err = h.KafkaProducer.Produce(ctx, h.TopicNotificationCreated, []byte(message))
+	if err != nil {
+		return newStatusError(err, "error creating notification", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
 // GetNotification godoc
 // @Summary Gets notification
-// @Description Gets notification
+// @Description Gets a notification of the current user
 // @Tags notification
 // @Security ApiKeyAuth
 // @Param id path string true "Notification ID"
 // @Success 200 {object} notifications.Notification
 // @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /notifications/{id} [get]
 func (h *Handler) GetNotification(c *gin.Context) {
@@ -69,12 +85,230 @@ func (h *Handler) GetNotification(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
-	resp, err := h.Notification.GetNotification(ctx, &pbn.ID{Id: id})
+	resp, err := h.getOwnNotification(ctx, c, id)
 	if err != nil {
-		handleError(c, h, err, "error finding notification", http.StatusInternalServerError)
+		respondError(c, h, err)
 		return
 	}
 
 	h.Logger.Info("GetNotification handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
+
+// MarkNotificationRead godoc
+// @Summary Marks notification as read
+// @Description Marks a notification of the current user as read
+// @Tags notification
+// @Security ApiKeyAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} string "Notification marked as read"
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /notifications/{id}/read [put]
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	h.Logger.Info("MarkNotificationRead handler is invoked")
+
+	id := c.Param("id")
+	if id == "" {
+		handleError(c, h, nil, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	if err := h.markNotificationRead(ctx, c, id); err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("MarkNotificationRead handler is completed")
+	c.JSON(http.StatusOK, "Notification marked as read")
+}
+
+// markNotificationRead marks a notification the caller may see as read.
+func (h *Handler) markNotificationRead(ctx context.Context, c *gin.Context, id string) error {
+	if _, err := h.getOwnNotification(ctx, c, id); err != nil {
+		return err
+	}
+
+	_, err := h.Notification.MarkNotificationRead(ctx, &pbn.ID{Id: id})
+	if err != nil {
+		return newStatusError(err, "error updating notification", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Marks all notifications as read
+// @Description Marks every notification of the current user as read
+// @Tags notification
+// @Security ApiKeyAuth
+// @Success 200 {object} string "Notifications marked as read"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /notifications/read-all [put]
+func (h *Handler) MarkAllNotificationsRead(c *gin.Context) {
+	h.Logger.Info("MarkAllNotificationsRead handler is invoked")
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	_, err = h.Notification.MarkAllNotificationsRead(ctx, &pbn.UserID{UserId: userID})
+	if err != nil {
+		handleError(c, h, err, "error updating notifications", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("MarkAllNotificationsRead handler is completed")
+	c.JSON(http.StatusOK, "Notifications marked as read")
+}
+
+// DeleteNotification godoc
+// @Summary Deletes notification
+// @Description Deletes a notification of the current user
+// @Tags notification
+// @Security ApiKeyAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} string "Notification deleted successfully"
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /notifications/{id} [delete]
+func (h *Handler) DeleteNotification(c *gin.Context) {
+	h.Logger.Info("DeleteNotification handler is invoked")
+
+	id := c.Param("id")
+	if id == "" {
+		handleError(c, h, nil, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	if err := h.deleteNotification(ctx, c, id); err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("DeleteNotification handler is completed")
+	c.JSON(http.StatusOK, "Notification deleted successfully")
+}
+
+// deleteNotification deletes a notification the caller may see.
+func (h *Handler) deleteNotification(ctx context.Context, c *gin.Context, id string) error {
+	if _, err := h.getOwnNotification(ctx, c, id); err != nil {
+		return err
+	}
+
+	_, err := h.Notification.DeleteNotification(ctx, &pbn.ID{Id: id})
+	if err != nil {
+		return newStatusError(err, "error deleting notification", http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+// getOwnNotification fetches a notification the caller is allowed to
+// see: their own, or any for an admin.
+func (h *Handler) getOwnNotification(ctx context.Context, c *gin.Context, id string) (*pbn.Notification, error) {
+	userID, err := getUserID(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	role, err := getUserRole(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	n, err := h.Notification.GetNotification(ctx, &pbn.ID{Id: id})
+	if err != nil {
+		return nil, newStatusError(err, "error finding notification", http.StatusInternalServerError)
+	}
+
+	if n.UserId != userID && role != booking.RoleAdmin {
+		return nil, newStatusError(nil, "access denied", http.StatusForbidden)
+	}
+
+	return n, nil
+}
+
+// FetchMyNotifications godoc
+// @Summary Fetches my notifications
+// @Description Fetches notifications sent to the current user, with the number of unread ones
+// @Tags notification
+// @Security ApiKeyAuth
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {object} models.NotificationListResp{data=[]notifications.Notification}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /users/me/notifications [get]
+func (h *Handler) FetchMyNotifications(c *gin.Context) {
+	h.Logger.Info("FetchMyNotifications handler is invoked")
+
+	id, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	fetch := h.notificationsPage(id)
+	if c.Query("unread") == "true" {
+		fetch = h.unreadNotificationsPage(id)
+	}
+
+	resp, ok := paginate(c, h, fetch, "error fetching notifications")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	unread, err := h.Notification.CountUnreadNotifications(ctx, &pbn.UserID{UserId: id})
+	if err != nil {
+		handleError(c, h, err, "error counting notifications", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("FetchMyNotifications handler is completed")
+	c.JSON(http.StatusOK, models.NotificationListResp{ListResp: *resp, UnreadCount: unread.Count})
+}
+
+func (h *Handler) notificationsPage(userID string) pageFetcher[*pbn.Notification] {
+	return h.listNotifications(userID, false)
+}
+
+func (h *Handler) unreadNotificationsPage(userID string) pageFetcher[*pbn.Notification] {
+	return h.listNotifications(userID, true)
+}
+
+func (h *Handler) listNotifications(userID string, unreadOnly bool) pageFetcher[*pbn.Notification] {
+	return func(ctx context.Context, page, limit int32) ([]*pbn.Notification, error) {
+		resp, err := h.Notification.ListNotifications(ctx, &pbn.Filter{
+			UserId:     userID,
+			Page:       page,
+			Limit:      limit,
+			UnreadOnly: unreadOnly,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Notifications, nil
+	}
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	pbpa "api-gateway/genproto/payments"
+	pbr "api-gateway/genproto/reviews"
+	"api-gateway/kafka/consumer"
+	"api-gateway/models"
+	"api-gateway/pkg/notify"
+	"api-gateway/pkg/payment"
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// notifyEvents sends the customer and the provider of a booking the
+// notifications of its events. The instances share one consumer group, so
+// every event is notified once.
+func (h *Handler) notifyEvents(events consumer.IKafkaConsumer) {
+	h.consumeEvents(events, "notifier", h.notifyEvent)
+}
+
+func (h *Handler) notifyEvent(topic string, msg []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	var (
+		event     string
+		data      notify.Data
+		receivers map[string]string
+		err       error
+	)
+
+	switch topic {
+	case h.TopicBookingCreated:
+		event = notify.EventBookingCreated
+
+		var b pbb.NewBooking
+		if err = json.Unmarshal(msg, &b); err == nil {
+			data = notify.Data{
+				Status:        b.Status,
+				ScheduledTime: b.ScheduledTime,
+				Address:       b.Location.GetAddress(),
+				TotalPrice:    b.TotalPrice,
+			}
+			receivers, err = h.bookingParties(ctx, &data, b.UserId, b.ProviderId, b.ServiceId)
+		}
+	case h.TopicBookingUpdated:
+		event = notify.EventBookingUpdated
+
+		var u pbb.NewData
+		if err = json.Unmarshal(msg, &u); err == nil {
+			// Status changes have templates of their own, e.g.
+			// booking_confirmed; reschedules keep the status.
+			if e := "booking_" + u.Status; h.Templates.Has(e) {
+				event = e
+			}
+
+			data = notify.Data{
+				Status:        u.Status,
+				ScheduledTime: u.ScheduledTime,
+				Address:       u.Location.GetAddress(),
+				TotalPrice:    u.TotalPrice,
+			}
+			receivers, err = h.bookingNotification(ctx, &data, u.Id)
+		}
+	case h.TopicBookingCancelled:
+		event = notify.EventBookingCancelled
+
+		var b pbb.ID
+		if err = json.Unmarshal(msg, &b); err == nil {
+			receivers, err = h.bookingNotification(ctx, &data, b.Id)
+		}
+	case h.TopicPaymentCreated:
+		event = notify.EventPaymentCreated
+
+		var p pbpa.NewPayment
+		if err = json.Unmarshal(msg, &p); err == nil {
+			// Only payments that went through are notified.
+			if p.Status != payment.StatusSucceeded {
+				return
+			}
+
+			data.Amount = p.Amount
+			receivers, err = h.bookingNotification(ctx, &data, p.BookingId)
+		}
+	case h.TopicPaymentUpdated:
+		var p pbpa.PaymentUpdate
+		if err = json.Unmarshal(msg, &p); err == nil {
+			// A payment that was processing settled.
+			switch p.Status {
+			case payment.StatusSucceeded:
+				event = notify.EventPaymentCreated
+			case payment.StatusFailed:
+				event = notify.EventPaymentFailed
+			default:
+				return
+			}
+
+			data.Amount = p.Amount
+			receivers, err = h.bookingNotification(ctx, &data, p.BookingId)
+		}
+	case h.TopicPaymentRefunded:
+		event = notify.EventPaymentRefunded
+
+		var r pbpa.PaymentRefund
+		if err = json.Unmarshal(msg, &r); err == nil {
+			data.Amount = r.Amount
+			receivers, err = h.bookingNotification(ctx, &data, r.BookingId)
+		}
+	case h.TopicReviewCreated:
+		event = notify.EventReviewCreated
+
+		var r pbr.NewReview
+		if err = json.Unmarshal(msg, &r); err == nil {
+			data.Rating = r.Rating
+			data.Comment = r.Comment
+			receivers, err = h.bookingNotification(ctx, &data, r.BookingId)
+		}
+	default:
+		return
+	}
+
+	if err != nil {
+		h.Logger.Error(errors.Wrapf(err, "error notifying %s event", event).Error())
+		return
+	}
+
+	for receiver, userID := range receivers {
+		msg, ok, err := h.Templates.Render(h.userLocale(ctx, userID), event, receiver, data)
+		if err != nil {
+			h.Logger.Error(errors.Wrapf(err, "error rendering %s notification", event).Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		err = h.createNotification(ctx, models.NotificationCreate{
+			UserID:  userID,
+			Title:   msg.Title,
+			Message: msg.Message,
+			Type:    event,
+		})
+		if err != nil {
+			h.Logger.Error(errors.Wrapf(err, "error sending %s notification", event).Error())
+		}
+	}
+}
+
+// userLocale returns the locale the user chose for notifications, or the
+// default one.
+func (h *Handler) userLocale(ctx context.Context, userID string) string {
+	prefs, err := h.preferences(ctx, userID)
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error fetching preferences").Error())
+	}
+	if err != nil || prefs.Locale == "" {
+		return h.Locale
+	}
+	return prefs.Locale
+}
+
+// bookingNotification fills data with the booking and its parties, keeping
+// the values the event already set, and returns the receivers.
+func (h *Handler) bookingNotification(ctx context.Context, data *notify.Data, bookingID string) (map[string]string, error) {
+	b, err := h.getBooking(ctx, bookingID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding booking")
+	}
+
+	data.BookingID = b.Id
+	if data.Status == "" {
+		data.Status = b.Status
+	}
+	if data.ScheduledTime == "" {
+		data.ScheduledTime = b.ScheduledTime
+	}
+	if data.Address == "" {
+		data.Address = b.Location.GetAddress()
+	}
+	if data.TotalPrice == 0 {
+		data.TotalPrice = b.TotalPrice
+	}
+
+	return h.bookingParties(ctx, data, b.UserId, b.ProviderId, b.ServiceId)
+}
+
+// bookingParties fills in the names of the provider and the service and
+// returns the user IDs of the customer and of the provider.
+func (h *Handler) bookingParties(ctx context.Context, data *notify.Data, userID, providerID, serviceID string) (map[string]string, error) {
+	p, err := h.getProvider(ctx, providerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding provider")
+	}
+
+	s, err := h.getService(ctx, serviceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding service")
+	}
+
+	data.Provider = p.CompanyName
+	data.Service = s.Name
+
+	return map[string]string{notify.Customer: userID, notify.Provider: p.UserId}, nil
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// outboxBatchSize is how many saved messages are published at a time.
+const outboxBatchSize = 100
+
+// runOutbox publishes the saved outbox messages every pollInterval. They
+// are published right after they are saved as well; the poll catches the
+// ones whose publish failed then.
+func (h *Handler) runOutbox(pollInterval time.Duration) {
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+
+	for range poll.C {
+		ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+		h.publishOutbox(ctx)
+		cancel()
+	}
+}
+
+// publishOutbox publishes the saved outbox messages. Failures are logged
+// and left to the next poll.
+func (h *Handler) publishOutbox(ctx context.Context) {
+	for {
+		n, err := h.Storage.PublishOutbox(ctx, outboxBatchSize, func(topic string, message []byte) error {
+			return h.KafkaProducer.Produce(ctx, topic, message)
+		})
+		if err != nil {
+			h.Logger.Error(errors.Wrap(err, "error publishing outbox").Error())
+			return
+		}
+		if n < outboxBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,430 @@
+package handler
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/pagination"
+	"api-gateway/pkg/query"
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// pageFetcher loads one page of items from a backend list.
+type pageFetcher[T any] func(ctx context.Context, page, limit int32) ([]T, error)
+
+// parsePagination reads the cursor, page and limit query parameters,
+// applying the configured default and maximum page size.
+func (h *Handler) parsePagination(c *gin.Context) (pagination.Params, error) {
+	if cursor := c.Query("cursor"); cursor != "" {
+		p, err := pagination.DecodeCursor(cursor)
+		if err != nil {
+			return pagination.Params{}, err
+		}
+		p.Limit = min(p.Limit, h.MaxPageLimit)
+		return p, checkPageRange(p)
+	}
+
+	p := pagination.Params{Page: 1, Limit: h.DefaultPageLimit}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		page, err := parseIntQueryParam(pageStr)
+		if err != nil {
+			return pagination.Params{}, err
+		}
+		p.Page = page
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := parseIntQueryParam(limitStr)
+		if err != nil {
+			return pagination.Params{}, err
+		}
+		p.Limit = min(limit, h.MaxPageLimit)
+	}
+
+	return p, checkPageRange(p)
+}
+
+// checkPageRange rejects pages whose items lie beyond what the backends
+// can address, so that offsets computed from them do not overflow.
+func checkPageRange(p pagination.Params) error {
+	if int64(p.Page)*int64(p.Limit) >= math.MaxInt32 {
+		return errors.New("page is out of range")
+	}
+	return nil
+}
+
+// paginate fetches the requested page and wraps it in the list envelope,
+// setting the Link header on the way. The next cursor is keyed on the
+// last item of the page. It writes the error response itself and reports
+// whether the page was loaded.
+func paginate[T any](c *gin.Context, h *Handler, fetch pageFetcher[T], errMsg string) (*models.ListResp, bool) {
+	p, err := h.parsePagination(c)
+	if err != nil {
+		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	var lp listPage[T]
+	if p.After != nil {
+		lp, err = seekPage(ctx, fetch, p)
+	} else {
+		lp, err = fetchPage(ctx, fetch, p)
+	}
+	if err != nil {
+		handleError(c, h, err, errMsg, listErrorCode(err))
+		return nil, false
+	}
+	if lp.items == nil {
+		lp.items = []T{}
+	}
+
+	info := models.PageInfo{HasMore: lp.hasMore}
+
+	if c.Query("include_total") == "true" {
+		total, err := countAll(ctx, fetch)
+		if err != nil {
+			handleError(c, h, err, errMsg, listErrorCode(err))
+			return nil, false
+		}
+		info.Total = &total
+	}
+
+	rels := map[string]pagination.Params{"first": p.First()}
+	if lp.first > 1 {
+		rels["prev"] = pagination.Params{Page: lp.first - 1, Limit: p.Limit}
+	}
+	if info.HasMore {
+		next := pagination.Params{Page: lp.last + 1, Limit: p.Limit}
+		if key := itemKey(lp.items[len(lp.items)-1]); key.ID != "" {
+			next = pagination.Params{Page: lp.last, Limit: p.Limit, After: &key}
+		}
+		rels["next"] = next
+		info.NextCursor = pagination.EncodeCursor(next)
+	}
+	c.Header("Link", pagination.Links(c.Request.URL, rels))
+
+	return &models.ListResp{Data: lp.items, PageInfo: info}, true
+}
+
+// listErrorCode is the status code of an error loading a list.
+func listErrorCode(err error) int {
+	if errors.Is(err, errListTooLong) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// listPage is a page of a list with the backend pages its first and last
+// items are on.
+type listPage[T any] struct {
+	items       []T
+	first, last int32
+	hasMore     bool
+}
+
+// fetchPage loads the page p selects by number.
+func fetchPage[T any](ctx context.Context, fetch pageFetcher[T], p pagination.Params) (listPage[T], error) {
+	items, err := fetch(ctx, p.Page, p.Limit)
+	if err != nil {
+		return listPage[T]{}, err
+	}
+
+	lp := listPage[T]{items: items, first: p.Page, last: p.Page}
+
+	// A full page does not tell whether anything follows it, so probe
+	// the first item of the next page.
+	if int32(len(items)) >= p.Limit {
+		next, err := fetch(ctx, p.Offset()+p.Limit+1, 1)
+		if err != nil {
+			return listPage[T]{}, err
+		}
+		lp.hasMore = len(next) > 0
+	}
+
+	return lp, nil
+}
+
+// seekPage loads the page that follows the item p.After. The backends
+// page by number, so the item is looked for on the page it was on and the
+// ones either side of it; up to a page of items added or removed before
+// it meanwhile shifts nothing. When the item itself is gone, the page
+// starts after the items created before it.
+func seekPage[T any](ctx context.Context, fetch pageFetcher[T], p pagination.Params) (listPage[T], error) {
+	start := max(p.Page-1, 1)
+	limit := int(p.Limit)
+
+	var window []T
+	page, full := start, true
+	load := func() error {
+		items, err := fetch(ctx, page, p.Limit)
+		if err != nil {
+			return err
+		}
+		window = append(window, items...)
+		full = len(items) == limit && checkPageRange(pagination.Params{Page: page + 1, Limit: p.Limit}) == nil
+		page++
+		return nil
+	}
+
+	for full && page <= p.Page+1 {
+		if err := load(); err != nil {
+			return listPage[T]{}, err
+		}
+	}
+
+	at := slices.IndexFunc(window, func(item T) bool { return itemKey(item).ID == p.After.ID }) + 1
+	if at == 0 {
+		at = afterKey(window, *p.After)
+	}
+	if at < 0 {
+		// Neither found nor ordered by creation time; carry on from
+		// where the item was.
+		at = min(int(p.Page-start+1)*limit, len(window))
+	}
+
+	// Load what the page and the probe for more still need.
+	for full && len(window) <= at+limit {
+		if err := load(); err != nil {
+			return listPage[T]{}, err
+		}
+	}
+
+	end := min(at+limit, len(window))
+	return listPage[T]{
+		items:   window[at:end],
+		first:   start + int32(at/limit),
+		last:    start + int32(max(end-1, at)/limit),
+		hasMore: len(window) > end,
+	}, nil
+}
+
+// afterKey returns the index of the first item that comes after key in a
+// list ordered by creation time, oldest or newest first, with ties in ID
+// order. It returns -1 when the list is not ordered so.
+func afterKey[T any](items []T, key pagination.Key) int {
+	at, ok := parseCreatedAt(key.CreatedAt)
+	if !ok || len(items) == 0 {
+		return -1
+	}
+
+	times := make([]time.Time, len(items))
+	for i, item := range items {
+		if times[i], ok = parseCreatedAt(itemKey(item).CreatedAt); !ok {
+			return -1
+		}
+	}
+
+	newestFirst := times[0].After(times[len(times)-1])
+	for i := 1; i < len(times); i++ {
+		if newestFirst && times[i].After(times[i-1]) || !newestFirst && times[i].Before(times[i-1]) {
+			return -1
+		}
+	}
+
+	for i, t := range times {
+		if newestFirst && t.Before(at) || !newestFirst && t.After(at) ||
+			t.Equal(at) && itemKey(items[i]).ID > key.ID {
+			return i
+		}
+	}
+	return len(items)
+}
+
+var createdAtLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999"}
+
+func parseCreatedAt(s string) (time.Time, bool) {
+	for _, layout := range createdAtLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// itemKey returns the key of a list item, or an empty one for items
+// without an ID.
+func itemKey(item any) pagination.Key {
+	switch v := item.(type) {
+	case interface {
+		GetId() string
+		GetCreatedAt() string
+	}:
+		return pagination.Key{CreatedAt: v.GetCreatedAt(), ID: v.GetId()}
+	case *models.DeliveryLog:
+		return pagination.Key{CreatedAt: v.CreatedAt, ID: v.ID}
+	case *models.Webhook:
+		return pagination.Key{CreatedAt: v.CreatedAt, ID: v.ID}
+	case *models.WebhookDelivery:
+		return pagination.Key{CreatedAt: v.CreatedAt, ID: v.ID}
+	default:
+		return pagination.Key{}
+	}
+}
+
+// listAll walks every page of a backend list.
+func listAll[T any](ctx context.Context, fetch pageFetcher[T]) ([]T, error) {
+	var all []T
+
+	for page := int32(1); ; page++ {
+		items, err := fetch(ctx, page, listAllLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+		if len(items) < listAllLimit {
+			return all, nil
+		}
+	}
+}
+
+// errListTooLong is returned for a list with more items than a scan of
+// it may hold.
+var errListTooLong = errors.New("list is too long to scan")
+
+// listScan holds a whole backend list for the scoping, filtering and
+// ordering the backend cannot do itself. The list is loaded at most once
+// per ttl and shared by the requests in between, and a list with more
+// than maxItems items is refused rather than loaded.
+type listScan[T any] struct {
+	fetch    pageFetcher[T]
+	ttl      time.Duration
+	maxItems int
+
+	mu       sync.Mutex
+	items    []T
+	loadedAt time.Time
+}
+
+func newListScan[T any](fetch pageFetcher[T], ttl time.Duration, maxItems int) *listScan[T] {
+	return &listScan[T]{fetch: fetch, ttl: ttl, maxItems: maxItems}
+}
+
+// all returns every item of the list. The items are shared and must not
+// be changed.
+func (s *listScan[T]) all(ctx context.Context) ([]T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.items != nil && time.Since(s.loadedAt) < s.ttl {
+		return s.items, nil
+	}
+
+	var items []T
+	for page := int32(1); ; page++ {
+		batch, err := s.fetch(ctx, page, listAllLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, batch...)
+		if len(items) > s.maxItems {
+			return nil, errListTooLong
+		}
+		if len(batch) < listAllLimit {
+			break
+		}
+	}
+
+	if items == nil {
+		items = []T{}
+	}
+	s.items, s.loadedAt = items, time.Now()
+
+	return items, nil
+}
+
+// pages pages over the loaded list.
+func (s *listScan[T]) pages(ctx context.Context, page, limit int32) ([]T, error) {
+	items, err := s.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageOf(items, page, limit), nil
+}
+
+// source returns the list to apply q to: the backend's own pages when q
+// leaves the list as it is, and the loaded list otherwise.
+func (s *listScan[T]) source(q *query.Query) pageFetcher[T] {
+	if q.IsEmpty() {
+		return s.fetch
+	}
+	return s.pages
+}
+
+// filterAll loads every item of a backend list that satisfies keep and
+// serves the result as an in-memory list, for scoping that the backend
+// cannot do itself.
+func filterAll[T any](fetch pageFetcher[T], keep func(T) bool) pageFetcher[T] {
+	return memoryList(fetch, func(all []T) []T {
+		var items []T
+		for _, item := range all {
+			if keep(item) {
+				items = append(items, item)
+			}
+		}
+		return items
+	})
+}
+
+// memoryList loads every item of a backend list once, passes it through
+// prepare and pages over the result in memory.
+func memoryList[T any](fetch pageFetcher[T], prepare func([]T) []T) pageFetcher[T] {
+	var (
+		items  []T
+		loaded bool
+	)
+
+	return func(ctx context.Context, page, limit int32) ([]T, error) {
+		if !loaded {
+			all, err := listAll(ctx, fetch)
+			if err != nil {
+				return nil, err
+			}
+
+			items = prepare(all)
+			loaded = true
+		}
+
+		return pageOf(items, page, limit), nil
+	}
+}
+
+// pageOf returns the given page of items, or nothing when the page lies
+// outside them.
+func pageOf[T any](items []T, page, limit int32) []T {
+	start := (int64(page) - 1) * int64(limit)
+	if page < 1 || limit < 1 || start >= int64(len(items)) {
+		return nil
+	}
+
+	return items[start:min(start+int64(limit), int64(len(items)))]
+}
+
+// countAll walks every page of a backend list to count its items.
+func countAll[T any](ctx context.Context, fetch pageFetcher[T]) (int64, error) {
+	var total int64
+
+	for page := int32(1); ; page++ {
+		items, err := fetch(ctx, page, listAllLimit)
+		if err != nil {
+			return 0, errors.Wrap(err, "error counting items")
+		}
+
+		total += int64(len(items))
+		if len(items) < listAllLimit {
+			return total, nil
+		}
+	}
+}
@@ -1,50 +1,484 @@
 package handler
 
 import (
+	pbb "api-gateway/genproto/bookings"
 	pb "api-gateway/genproto/payments"
+	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"api-gateway/pkg/payment"
+	"api-gateway/storage"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
 // CreatePayment godoc
 // @Summary Creates payment
-// @Description Adds a new payment
+// @Description Starts the payment of a booking of the current user: creates a payment intent with the
+// @Description processor for the booking's price. A booking has one open intent at a time; while it is
+// @Description pending or processing it is returned again. Confirm it with a payment method to pay.
 // @Tags payment
 // @Security ApiKeyAuth
-// @Param data body payments.NewPayment true "New payment"
-// @Success 201 {object} string "Payment created"
+// @Param data body models.PaymentCreate true "Booking to pay"
+// @Success 201 {object} models.PaymentIntent
+// @Success 200 {object} models.PaymentIntent "The open intent of the booking"
 // @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Booking cannot be paid"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Payments are not configured"
 // @Router /payments [post]
 func (h *Handler) CreatePayment(c *gin.Context) {
 	h.Logger.Info("CreatePayment handler is invoked")
 
-	var req pb.NewPayment
+	var req models.PaymentCreate
 	if err := c.ShouldBind(&req); err != nil {
 		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
 		return
 	}
 
-	message, err := json.Marshal(&req)
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	pi, created, err := h.createPaymentIntent(ctx, c, req)
 	if err != nil {
-		handleError(c, h, err, "error serializing payment", http.StatusInternalServerError)
+		respondError(c, h, err)
 		return
 	}
 
+	code := http.StatusOK
+	if created {
+		code = http.StatusCreated
+	}
+
+	h.Logger.Info("CreatePayment handler is completed")
+	c.JSON(code, pi)
+}
+
+// createPaymentIntent opens a payment intent for the booking's price, or
+// returns the one already open, reporting whether it created one.
+func (h *Handler) createPaymentIntent(ctx context.Context, c *gin.Context, req models.PaymentCreate) (*models.PaymentIntent, bool, error) {
+	userID, err := getUserID(c)
+	if err != nil {
+		return nil, false, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	role, err := getUserRole(c)
+	if err != nil {
+		return nil, false, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	b, err := h.getBooking(ctx, req.BookingID)
+	if err != nil {
+		return nil, false, newStatusError(err, "error finding booking", http.StatusInternalServerError)
+	}
+
+	if b.UserId != userID && role != booking.RoleAdmin {
+		return nil, false, newStatusError(nil, "access denied", http.StatusForbidden)
+	}
+	if b.Status == booking.StatusCancelled {
+		return nil, false, newStatusError(nil, "booking is cancelled", http.StatusConflict)
+	}
+	if b.TotalPrice <= 0 {
+		return nil, false, newStatusError(nil, "booking has no price", http.StatusConflict)
+	}
+
+	open, err := h.activePaymentIntent(ctx, b.Id)
+	if err != nil {
+		return nil, false, err
+	}
+	if open != nil {
+		return open, false, nil
+	}
+
+	pi := &models.PaymentIntent{
+		ID:        uuid.NewString(),
+		BookingID: b.Id,
+		UserID:    b.UserId,
+		Processor: h.Processor.Name(),
+		Currency:  h.Currency,
+	}
+
+	intent, err := h.Processor.CreateIntent(ctx, payment.IntentParams{
+		Amount:         payment.ToMinor(b.TotalPrice),
+		Currency:       h.Currency,
+		Reference:      b.Id,
+		IdempotencyKey: pi.ID,
+	})
+	if err != nil {
+		return nil, false, processorError(err, "error creating payment intent")
+	}
+
+	pi.ProcessorIntentID = intent.ID
+	pi.Amount = payment.FromMinor(intent.Amount)
+	pi.Status = intent.Status
+
+	err = h.Storage.CreatePaymentIntent(ctx, pi)
+	if err == storage.ErrConflict {
+		// Another request opened one first.
+		open, err := h.activePaymentIntent(ctx, b.Id)
+		if err != nil || open != nil {
+			return open, false, err
+		}
+	}
+	if err != nil {
+		return nil, false, newStatusError(err, "error saving payment intent", http.StatusInternalServerError)
+	}
+
+	return pi, true, nil
+}
+
+// processorError reports a failed processor call as a bad gateway, or as
+// unavailable when no processor is configured.
+func processorError(err error, msg string) *statusError {
+	if err == payment.ErrNotConfigured {
+		return newStatusError(err, msg, http.StatusServiceUnavailable)
+	}
+	return newStatusError(err, msg, http.StatusBadGateway)
+}
+
+// activePaymentIntent returns the pending or processing intent of a
+// booking, nil if it has none, and fails if the booking is paid.
+func (h *Handler) activePaymentIntent(ctx context.Context, bookingID string) (*models.PaymentIntent, error) {
+	pi, err := h.Storage.GetActivePaymentIntent(ctx, bookingID)
+	if err == storage.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, newStatusError(err, "error finding payment intent", http.StatusInternalServerError)
+	}
+
+	if pi.Status == payment.StatusSucceeded {
+		return nil, newStatusError(nil, "booking is already paid", http.StatusConflict)
+	}
+	return pi, nil
+}
+
+// checkNoOpenIntent refuses to change the price of a booking while a
+// payment intent for it is pending or processing: the intent would still
+// charge the old price.
+func (h *Handler) checkNoOpenIntent(ctx context.Context, bookingID string) error {
+	pi, err := h.Storage.GetActivePaymentIntent(ctx, bookingID)
+	if err == storage.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return newStatusError(err, "error finding payment intent", http.StatusInternalServerError)
+	}
+
+	if pi.Status == payment.StatusPending || pi.Status == payment.StatusProcessing {
+		return newStatusError(nil, "booking has a payment in progress", http.StatusConflict)
+	}
+	return nil
+}
+
+// GetPaymentIntent godoc
+// @Summary Gets payment intent
+// @Description Gets a payment intent of the current user
+// @Tags payment
+// @Security ApiKeyAuth
+// @Param id path string true "Payment intent ID"
+// @Success 200 {object} models.PaymentIntent
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Payment intent not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /payments/intents/{id} [get]
+func (h *Handler) GetPaymentIntent(c *gin.Context) {
+	h.Logger.Info("GetPaymentIntent handler is invoked")
+
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
-	err = h.KafkaProducer.Produce(ctx, h.TopicPaymentCreated, []byte(message))
+	pi, err := h.getOwnPaymentIntent(ctx, c, c.Param("id"))
 	if err != nil {
-		handleError(c, h, err, "error creating payment", http.StatusInternalServerError)
+		respondError(c, h, err)
 		return
 	}
 
-	h.Logger.Info("CreatePayment handler is completed")
-	c.JSON(http.StatusCreated, "Payment created")
+	h.Logger.Info("GetPaymentIntent handler is completed")
+	c.JSON(http.StatusOK, pi)
+}
+
+// ConfirmPayment godoc
+// @Summary Confirms payment
+// @Description Pays a pending payment intent of the current user with a payment method tokenized by
+// @Description the processor's client library; card details are never sent to the gateway. The intent
+// @Description comes back succeeded, processing while the processor settles it, or still pending with
+// @Description a failure message when the method was declined, in which case another one can be tried.
+// @Description The payment is recorded once the intent succeeds or is processing.
+// @Tags payment
+// @Security ApiKeyAuth
+// @Param id path string true "Payment intent ID"
+// @Param data body models.PaymentConfirm true "Payment method"
+// @Success 200 {object} models.PaymentIntent
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 402 {object} models.PaymentIntent "Payment method declined"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Payment intent not found"
+// @Failure 409 {object} string "Payment intent is not pending"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "Payments are not configured"
+// @Router /payments/intents/{id}/confirm [post]
+func (h *Handler) ConfirmPayment(c *gin.Context) {
+	h.Logger.Info("ConfirmPayment handler is invoked")
+
+	var req models.PaymentConfirm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	pi, err := h.confirmPaymentIntent(ctx, c, c.Param("id"), payment.Method{Token: req.PaymentMethod})
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	code := http.StatusOK
+	if pi.Status == payment.StatusPending {
+		code = http.StatusPaymentRequired
+	}
+
+	h.Logger.Info("ConfirmPayment handler is completed")
+	c.JSON(code, pi)
+}
+
+// confirmPaymentIntent charges a pending intent with the payment method
+// and records the payment when it goes through.
+func (h *Handler) confirmPaymentIntent(ctx context.Context, c *gin.Context, id string, method payment.Method) (*models.PaymentIntent, error) {
+	pi, err := h.getOwnPaymentIntent(ctx, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if pi.Status != payment.StatusPending {
+		return nil, newStatusError(nil, "payment intent is "+pi.Status, http.StatusConflict)
+	}
+
+	intent, err := h.Processor.ConfirmIntent(ctx, pi.ProcessorIntentID, method)
+	if err != nil {
+		return nil, processorError(err, "error confirming payment")
+	}
+
+	pi.Status = intent.Status
+	pi.PaymentMethod = intent.MethodType
+	pi.FailureMessage = intent.FailureMessage
+
+	// The payment is published from the outbox, so that it is published
+	// exactly when the intent is saved.
+	var out []storage.OutboxMessage
+	if pi.Status == payment.StatusSucceeded || pi.Status == payment.StatusProcessing {
+		m, err := h.paymentMessage(pi)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+
+	err = h.Storage.UpdatePaymentIntent(ctx, pi, payment.StatusPending, out...)
+	if err == storage.ErrConflict {
+		// A webhook event settled the intent first, and recorded it.
+		return h.Storage.GetPaymentIntent(ctx, pi.ID)
+	}
+	if err != nil {
+		return nil, newStatusError(err, "error saving payment intent", http.StatusInternalServerError)
+	}
+
+	h.publishOutbox(ctx)
+
+	return pi, nil
+}
+
+// paymentMessage is the payment_created message of an intent, with the
+// status the processor gave it.
+func (h *Handler) paymentMessage(pi *models.PaymentIntent) (storage.OutboxMessage, error) {
+	message, err := json.Marshal(&pb.NewPayment{
+		BookingId:     pi.BookingID,
+		Amount:        pi.Amount,
+		Status:        pi.Status,
+		PaymentMethod: pi.PaymentMethod,
+		TransactionId: pi.ProcessorIntentID,
+	})
+	if err != nil {
+		return storage.OutboxMessage{}, newStatusError(err, "error serializing payment", http.StatusInternalServerError)
+	}
+
+	return storage.OutboxMessage{Topic: h.TopicPaymentCreated, Message: message}, nil
+}
+
+// PaymentWebhook godoc
+// @Summary Receives payment processor events
+// @Description Receives the webhook events of the payment processor, e.g. stripe. It needs no token:
+// @Description each event is checked against the processor's signature, and events it already received
+// @Description are acknowledged without being handled again. Events about payment intents move the
+// @Description intent on, and a payment that settles after processing is published as payment_updated.
+// @Description Events about unknown intents and other events are acknowledged and ignored.
+// @Tags payment
+// @Param provider path string true "Payment processor"
+// @Success 200 {object} string "Event received"
+// @Failure 400 {object} string "Invalid signature"
+// @Failure 404 {object} string "Unknown payment processor"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /payments/webhooks/{provider} [post]
+func (h *Handler) PaymentWebhook(c *gin.Context) {
+	h.Logger.Info("PaymentWebhook handler is invoked")
+
+	if c.Param("provider") != h.Processor.Name() {
+		handleError(c, h, nil, "unknown payment processor", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.Processor.ParseEvent(body, c.Request.Header)
+	if err == payment.ErrInvalidSignature {
+		handleError(c, h, err, "invalid signature", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	processor := h.Processor.Name()
+	fresh, err := h.Storage.RecordPaymentEvent(ctx, processor, event.ID, event.Type)
+	if err != nil {
+		handleError(c, h, err, "error recording event", http.StatusInternalServerError)
+		return
+	}
+	if !fresh {
+		h.Logger.Info("PaymentWebhook handler is completed", "event_id", event.ID, "duplicate", true)
+		c.JSON(http.StatusOK, gin.H{"received": true})
+		return
+	}
+
+	if err := h.applyPaymentEvent(ctx, event); err != nil {
+		// The processor retries events that were not acknowledged, so the
+		// event is forgotten to be handled then.
+		if ferr := h.Storage.ForgetPaymentEvent(ctx, processor, event.ID); ferr != nil {
+			h.Logger.Error(errors.Wrap(ferr, "error forgetting payment event").Error())
+		}
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("PaymentWebhook handler is completed", "event_id", event.ID)
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// applyPaymentEvent moves the intent of an event to the status the event
+// reports. An intent that was pending is recorded as a payment; one that
+// was already recorded while processing has its update published.
+func (h *Handler) applyPaymentEvent(ctx context.Context, event *payment.Event) error {
+	if event.Intent == nil {
+		return nil
+	}
+
+	pi, err := h.Storage.GetPaymentIntentByProcessorID(ctx, h.Processor.Name(), event.Intent.ID)
+	if err == storage.ErrNotFound {
+		h.Logger.Warn("payment event for unknown intent", "event_id", event.ID, "intent_id", event.Intent.ID)
+		return nil
+	}
+	if err != nil {
+		return newStatusError(err, "error finding payment intent", http.StatusInternalServerError)
+	}
+
+	// Events may come late or out of order.
+	if !payment.CanTransition(pi.Status, event.Intent.Status) {
+		return nil
+	}
+
+	from := pi.Status
+	pi.Status = event.Intent.Status
+	pi.FailureMessage = event.Intent.FailureMessage
+	if event.Intent.MethodType != "" {
+		pi.PaymentMethod = event.Intent.MethodType
+	}
+
+	var m storage.OutboxMessage
+	switch {
+	case from == payment.StatusPending && (pi.Status == payment.StatusSucceeded || pi.Status == payment.StatusProcessing):
+		m, err = h.paymentMessage(pi)
+	case from == payment.StatusProcessing:
+		m, err = h.paymentUpdateMessage(pi)
+	}
+	if err != nil {
+		return err
+	}
+
+	var out []storage.OutboxMessage
+	if m.Topic != "" {
+		out = append(out, m)
+	}
+
+	// The message is saved with the intent, so a failed publish is not
+	// lost when the retried event finds the intent settled.
+	if err := h.Storage.UpdatePaymentIntent(ctx, pi, from, out...); err != nil {
+		return newStatusError(err, "error saving payment intent", http.StatusInternalServerError)
+	}
+
+	h.publishOutbox(ctx)
+	return nil
+}
+
+// paymentUpdateMessage is the payment_updated message of a recorded
+// payment that moved on.
+func (h *Handler) paymentUpdateMessage(pi *models.PaymentIntent) (storage.OutboxMessage, error) {
+	message, err := json.Marshal(&pb.PaymentUpdate{
+		TransactionId: pi.ProcessorIntentID,
+		BookingId:     pi.BookingID,
+		Amount:        pi.Amount,
+		Status:        pi.Status,
+		PaymentMethod: pi.PaymentMethod,
+	})
+	if err != nil {
+		return storage.OutboxMessage{}, newStatusError(err, "error serializing payment update", http.StatusInternalServerError)
+	}
+
+	return storage.OutboxMessage{Topic: h.TopicPaymentUpdated, Message: message}, nil
+}
+
+// getOwnPaymentIntent fetches a payment intent the caller is allowed to
+// see: one of their own, or any for an admin.
+func (h *Handler) getOwnPaymentIntent(ctx context.Context, c *gin.Context, id string) (*models.PaymentIntent, error) {
+	userID, err := getUserID(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	role, err := getUserRole(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	pi, err := h.Storage.GetPaymentIntent(ctx, id)
+	if err == storage.ErrNotFound {
+		return nil, newStatusError(nil, "payment intent not found", http.StatusNotFound)
+	}
+	if err != nil {
+		return nil, newStatusError(err, "error finding payment intent", http.StatusInternalServerError)
+	}
+
+	if pi.UserID != userID && role != booking.RoleAdmin {
+		return nil, newStatusError(nil, "access denied", http.StatusForbidden)
+	}
+
+	return pi, nil
 }
 
 // GetPayment godoc
@@ -55,6 +489,7 @@ func (h *Handler) CreatePayment(c *gin.Context) {
 // @Param id path string true "Payment ID"
 // @Success 200 {object} payments.Payment
 // @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /payments/{id} [get]
 func (h *Handler) GetPayment(c *gin.Context) {
@@ -75,6 +510,17 @@ func (h *Handler) GetPayment(c *gin.Context) {
 		return
 	}
 
+	b, err := h.Booking.GetBooking(ctx, &pbb.ID{Id: resp.BookingId})
+	if err != nil {
+		handleError(c, h, err, "error finding booking", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.checkBookingAccess(ctx, c, b); err != nil {
+		handleError(c, h, err, "access denied", http.StatusForbidden)
+		return
+	}
+
 	h.Logger.Info("GetPayment handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
@@ -84,39 +530,100 @@ func (h *Handler) GetPayment(c *gin.Context) {
 // @Description Fetches payments
 // @Tags payment
 // @Security ApiKeyAuth
-// @Param page query int true "Page number"
-// @Param limit query int true "Number of items per page"
-// @Success 200 {object} payments.PaymentsList
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. amount:gte:50,created_at:gte:2026-10-01"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Success 200 {object} models.ListResp{data=[]payments.Payment}
 // @Failure 400 {object} string "Invalid data format"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "List too long to filter in the gateway"
 // @Router /payments/all [get]
 func (h *Handler) FetchPayments(c *gin.Context) {
 	h.Logger.Info("FetchPayments handler is invoked")
 
-	pageStr := c.Query("page")
-	limitStr := c.Query("limit")
-
-	page, err := parseIntQueryParam(pageStr)
+	q, err := parseQuery(c, paymentFields)
 	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
+		return
+	}
+
+	resp, ok := paginate(c, h, applyQuery(h.paymentScan.source(q), q), "error fetching payments")
+	if !ok {
 		return
 	}
 
-	limit, err := parseIntQueryParam(limitStr)
+	h.Logger.Info("FetchPayments handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) paymentsPage(ctx context.Context, page, limit int32) ([]*pb.Payment, error) {
+	resp, err := h.Payment.ListPayments(ctx, &pb.Pagination{Page: page, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Payments, nil
+}
+
+// FetchMyPayments godoc
+// @Summary Fetches my payments
+// @Description Fetches payments for bookings of the current user
+// @Tags payment
+// @Security ApiKeyAuth
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. amount:gte:50,created_at:gte:2026-10-01"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Success 200 {object} models.ListResp{data=[]payments.Payment}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "List too long to filter in the gateway"
+// @Router /users/me/payments [get]
+func (h *Handler) FetchMyPayments(c *gin.Context) {
+	h.Logger.Info("FetchMyPayments handler is invoked")
+
+	id, err := getUserID(c)
 	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
-	resp, err := h.Payment.ListPayments(ctx, &pb.Pagination{Page: page, Limit: limit})
+	bookings, err := h.bookingScan.all(ctx)
 	if err != nil {
-		handleError(c, h, err, "error fetching payments", http.StatusInternalServerError)
+		handleError(c, h, err, "error fetching bookings", listErrorCode(err))
 		return
 	}
 
-	h.Logger.Info("FetchPayments handler is completed")
+	mine := make(map[string]bool)
+	for _, b := range bookings {
+		if b.UserId == id {
+			mine[b.Id] = true
+		}
+	}
+
+	q, err := parseQuery(c, paymentFields)
+	if err != nil {
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
+		return
+	}
+
+	scoped := filterAll(h.paymentScan.pages, func(p *pb.Payment) bool {
+		return mine[p.BookingId]
+	})
+
+	resp, ok := paginate(c, h, applyQuery(scoped, q), "error fetching payments")
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("FetchMyPayments handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
@@ -2,11 +2,20 @@ package handler
 
 import (
 	pb "api-gateway/genproto/providers"
+	pbs "api-gateway/genproto/services"
 	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"api-gateway/pkg/geo"
+	"api-gateway/pkg/schedule"
 	"context"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
 // CreateProvider godoc
@@ -37,28 +46,32 @@ func (h *Handler) CreateProvider(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
+	resp, err := h.createProvider(ctx, id, req)
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("CreateProvider handler is completed")
+	c.JSON(http.StatusCreated, resp)
+}
+
+// createProvider registers the user as a provider.
+func (h *Handler) createProvider(ctx context.Context, userID string, req models.ProviderCreate) (*pb.CreateResp, error) {
 	resp, err := h.Provider.CreateProvider(ctx, &pb.NewProvider{
-		UserId:        id,
+		UserId:        userID,
 		CompanyName:   req.CompanyName,
 		Description:   req.Description,
 		Services:      req.Services,
 		Availability:  req.Availability,
 		AverageRating: req.AverageRating,
-		Location: &pb.Location{
-			Address:   req.Location.Address,
-			City:      req.Location.City,
-			Country:   req.Location.Country,
-			Latitude:  req.Location.Latitude,
-			Longitude: req.Location.Longitude,
-		},
+		Location:      providerLocation(&req.Location),
 	})
 	if err != nil {
-		handleError(c, h, err, "error creating provider", http.StatusInternalServerError)
-		return
+		return nil, newStatusError(err, "error creating provider", http.StatusInternalServerError)
 	}
 
-	h.Logger.Info("CreateProvider handler is completed")
-	c.JSON(http.StatusCreated, resp)
+	return resp, nil
 }
 
 // GetProvider godoc
@@ -122,6 +135,18 @@ func (h *Handler) UpdateProvider(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
+	resp, err := h.updateProvider(ctx, id, req)
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("UpdateProvider handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// updateProvider changes the given fields of a provider.
+func (h *Handler) updateProvider(ctx context.Context, id string, req models.ProviderUpdate) (*pb.UpdateResp, error) {
 	resp, err := h.Provider.UpdateProvider(ctx, &pb.NewData{
 		Id:            id,
 		CompanyName:   req.CompanyName,
@@ -129,21 +154,27 @@ func (h *Handler) UpdateProvider(c *gin.Context) {
 		Services:      req.Services,
 		Availability:  req.Availability,
 		AverageRating: req.AverageRating,
-		Location: &pb.Location{
-			Address:   req.Location.Address,
-			City:      req.Location.City,
-			Country:   req.Location.Country,
-			Latitude:  req.Location.Latitude,
-			Longitude: req.Location.Longitude,
-		},
+		Location:      providerLocation(req.Location),
 	})
 	if err != nil {
-		handleError(c, h, err, "error updating provider", http.StatusInternalServerError)
-		return
+		return nil, newStatusError(err, "error updating provider", http.StatusInternalServerError)
 	}
 
-	h.Logger.Info("UpdateProvider handler is completed")
-	c.JSON(http.StatusOK, resp)
+	return resp, nil
+}
+
+func providerLocation(l *models.Location) *pb.Location {
+	if l == nil {
+		return nil
+	}
+
+	return &pb.Location{
+		Address:   l.Address,
+		City:      l.City,
+		Country:   l.Country,
+		Latitude:  l.Latitude,
+		Longitude: l.Longitude,
+	}
 }
 
 // DeleteProvider godoc
@@ -183,36 +214,19 @@ func (h *Handler) DeleteProvider(c *gin.Context) {
 // @Description Fetches providers
 // @Tags provider
 // @Security ApiKeyAuth
-// @Param page query int true "Page number"
-// @Param limit query int true "Number of items per page"
-// @Success 200 {object} providers.ProvidersList
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Success 200 {object} models.ListResp{data=[]providers.Provider}
 // @Failure 400 {object} string "Invalid data format"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /providers/all [get]
 func (h *Handler) FetchProviders(c *gin.Context) {
 	h.Logger.Info("FetchProviders handler is invoked")
 
-	pageStr := c.Query("page")
-	limitStr := c.Query("limit")
-
-	page, err := parseIntQueryParam(pageStr)
-	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
-		return
-	}
-
-	limit, err := parseIntQueryParam(limitStr)
-	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
-	defer cancel()
-
-	resp, err := h.Provider.ListProviders(ctx, &pb.Pagination{Page: page, Limit: limit})
-	if err != nil {
-		handleError(c, h, err, "error fetching providers", http.StatusInternalServerError)
+	resp, ok := paginate(c, h, h.providersPage, "error fetching providers")
+	if !ok {
 		return
 	}
 
@@ -222,12 +236,16 @@ func (h *Handler) FetchProviders(c *gin.Context) {
 
 // SearchProviders godoc
 // @Summary Searches providers
-// @Description Searches providers
+// @Description Searches providers, optionally within a radius of a point
 // @Tags provider
 // @Security ApiKeyAuth
 // @Param company_name query string false "Company name"
 // @Param average_rating query float32 false "Average rating"
-// @Success 200 {object} providers.SearchResp
+// @Param lat query number false "Customer latitude"
+// @Param lng query number false "Customer longitude"
+// @Param radius_km query number false "Search radius in kilometers, requires lat and lng"
+// @Param sort query string false "Sort order" Enums(distance)
+// @Success 200 {object} models.ProviderSearchResp
 // @Failure 400 {object} string "Invalid data format"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /providers/search [get]
@@ -246,6 +264,18 @@ func (h *Handler) SearchProviders(c *gin.Context) {
 		filter.AverageRating = avgRating
 	}
 
+	near, err := parseGeoQuery(c)
+	if err != nil {
+		handleError(c, h, err, "invalid location parameter", http.StatusBadRequest)
+		return
+	}
+
+	sortBy := c.Query("sort")
+	if sortBy != "" && (sortBy != "distance" || near == nil) {
+		handleError(c, h, nil, "invalid sort parameter", http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
@@ -255,6 +285,218 @@ func (h *Handler) SearchProviders(c *gin.Context) {
 		return
 	}
 
+	// The backend filter has no notion of location, so distance filtering
+	// and ordering happen here.
+	providers := make([]models.ProviderDistance, 0, len(resp.Providers))
+	for _, p := range resp.Providers {
+		if near == nil {
+			providers = append(providers, models.ProviderDistance{Provider: p})
+			continue
+		}
+
+		loc := p.GetLocation()
+		if loc == nil {
+			continue
+		}
+
+		distance := geo.Distance(near.lat, near.lng, float64(loc.Latitude), float64(loc.Longitude))
+		if near.radiusKm > 0 && distance > near.radiusKm {
+			continue
+		}
+
+		distance = math.Round(distance*100) / 100
+		providers = append(providers, models.ProviderDistance{Provider: p, DistanceKm: &distance})
+	}
+
+	if sortBy == "distance" {
+		sort.SliceStable(providers, func(i, j int) bool {
+			return *providers[i].DistanceKm < *providers[j].DistanceKm
+		})
+	}
+
 	h.Logger.Info("SearchProviders handler is completed")
+	c.JSON(http.StatusOK, models.ProviderSearchResp{Providers: providers})
+}
+
+type geoQuery struct {
+	lat, lng, radiusKm float64
+}
+
+// parseGeoQuery reads the lat, lng and radius_km query parameters. It
+// returns nil when no location was given.
+func parseGeoQuery(c *gin.Context) (*geoQuery, error) {
+	latStr, lngStr, radiusStr := c.Query("lat"), c.Query("lng"), c.Query("radius_km")
+
+	if latStr == "" && lngStr == "" {
+		if radiusStr != "" {
+			return nil, errors.New("radius_km requires lat and lng")
+		}
+		return nil, nil
+	}
+
+	if latStr == "" || lngStr == "" {
+		return nil, errors.New("lat and lng must be given together")
+	}
+
+	q := &geoQuery{}
+	var err error
+
+	q.lat, err = parseFiniteFloat(latStr)
+	if err != nil || q.lat < -90 || q.lat > 90 {
+		return nil, errors.New("lat must be between -90 and 90")
+	}
+
+	q.lng, err = parseFiniteFloat(lngStr)
+	if err != nil || q.lng < -180 || q.lng > 180 {
+		return nil, errors.New("lng must be between -180 and 180")
+	}
+
+	if radiusStr != "" {
+		q.radiusKm, err = parseFiniteFloat(radiusStr)
+		if err != nil || q.radiusKm <= 0 {
+			return nil, errors.New("radius_km must be a positive number")
+		}
+	}
+
+	return q, nil
+}
+
+// parseFiniteFloat parses a number, rejecting NaN and the infinities,
+// which slip through range checks.
+func parseFiniteFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errors.New("not a finite number")
+	}
+	return f, nil
+}
+
+// GetProviderSlots godoc
+// @Summary Gets free slots
+// @Description Lists the provider's free time slots for a service on a date. A provider without
+// @Description working hours is open all day, in the gateway's time zone.
+// @Tags provider
+// @Security ApiKeyAuth
+// @Param id path string true "Provider ID"
+// @Param service_id query string true "Service ID"
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Success 200 {object} models.SlotsResp
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /providers/{id}/slots [get]
+func (h *Handler) GetProviderSlots(c *gin.Context) {
+	h.Logger.Info("GetProviderSlots handler is invoked")
+
+	id := c.Param("id")
+	serviceID := c.Query("service_id")
+	date := c.Query("date")
+	if id == "" || serviceID == "" || date == "" {
+		handleError(c, h, nil, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		handleError(c, h, err, "invalid date", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	provider, err := h.Provider.GetProvider(ctx, &pb.ID{Id: id})
+	if err != nil {
+		handleError(c, h, err, "error getting provider", http.StatusInternalServerError)
+		return
+	}
+
+	service, err := h.Service.GetService(ctx, &pbs.ID{Id: serviceID})
+	if err != nil {
+		handleError(c, h, err, "error finding service", http.StatusInternalServerError)
+		return
+	}
+
+	slots, err := h.providerSchedule(provider).Slots(date, time.Duration(service.Duration)*time.Minute)
+	if err != nil {
+		handleError(c, h, err, "error building slots", http.StatusBadRequest)
+		return
+	}
+
+	var busy []schedule.Interval
+	if len(slots) > 0 {
+		// Slots are sorted by start, but windows in other time zones may
+		// end later than the last one.
+		to := slots[0].End
+		for _, slot := range slots {
+			if slot.End.After(to) {
+				to = slot.End
+			}
+		}
+
+		busy, err = h.providerBusyIntervals(ctx, id, slots[0].Start, to)
+		if err != nil {
+			handleError(c, h, err, "error fetching bookings", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	resp := models.SlotsResp{
+		ProviderID: id,
+		ServiceID:  serviceID,
+		Date:       date,
+		Slots:      []models.Slot{},
+	}
+
+	now := time.Now()
+	for _, slot := range schedule.Free(slots, busy) {
+		if slot.Start.Before(now) {
+			continue
+		}
+		resp.Slots = append(resp.Slots, models.Slot{
+			Start: slot.Start.Format(time.RFC3339),
+			End:   slot.End.Format(time.RFC3339),
+		})
+	}
+
+	h.Logger.Info("GetProviderSlots handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *Handler) providersPage(ctx context.Context, page, limit int32) ([]*pb.Provider, error) {
+	resp, err := h.Provider.ListProviders(ctx, &pb.Pagination{Page: page, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Providers, nil
+}
+
+// checkProviderAccess verifies that the caller is the user behind the
+// provider, or an admin.
+func (h *Handler) checkProviderAccess(ctx context.Context, c *gin.Context, providerID string) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return err
+	}
+
+	role, err := getUserRole(c)
+	if err != nil {
+		return err
+	}
+
+	switch role {
+	case booking.RoleAdmin:
+		return nil
+	case booking.RoleProvider:
+		provider, err := h.Provider.GetProvider(ctx, &pb.ID{Id: providerID})
+		if err != nil {
+			return errors.Wrap(err, "error finding provider")
+		}
+		if provider.UserId == userID {
+			return nil
+		}
+	}
+
+	return errors.New("provider does not belong to user")
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"api-gateway/pkg/query"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+var bookingFields = query.Schema{
+	"user_id":          query.String,
+	"provider_id":      query.String,
+	"service_id":       query.String,
+	"status":           query.String,
+	"scheduled_time":   query.Time,
+	"location.city":    query.String,
+	"location.country": query.String,
+	"total_price":      query.Number,
+	"created_at":       query.Time,
+	"updated_at":       query.Time,
+}
+
+var paymentFields = query.Schema{
+	"booking_id":     query.String,
+	"amount":         query.Number,
+	"status":         query.String,
+	"payment_method": query.String,
+	"created_at":     query.Time,
+}
+
+var reviewFields = query.Schema{
+	"booking_id":  query.String,
+	"user_id":     query.String,
+	"provider_id": query.String,
+	"rating":      query.Number,
+	"created_at":  query.Time,
+	"updated_at":  query.Time,
+}
+
+var serviceFields = query.Schema{
+	"name":           query.String,
+	"price":          query.Number,
+	"duration":       query.Number,
+	"total_bookings": query.Number,
+	"created_at":     query.Time,
+	"updated_at":     query.Time,
+}
+
+// parseQuery reads the filter and sort query parameters against the
+// resource's whitelist.
+func parseQuery(c *gin.Context, schema query.Schema) (*query.Query, error) {
+	return query.Parse(c.Query("filter"), c.Query("sort"), schema)
+}
+
+// applyQuery narrows and orders a list in the gateway. The list is
+// returned unchanged when the query is empty, so the backend keeps
+// paginating on its own.
+func applyQuery[T proto.Message](fetch pageFetcher[T], q *query.Query) pageFetcher[T] {
+	if q.IsEmpty() {
+		return fetch
+	}
+
+	return memoryList(fetch, func(all []T) []T {
+		var items []T
+		for _, item := range all {
+			if q.Match(item) {
+				items = append(items, item)
+			}
+		}
+		query.SortSlice(q, items)
+		return items
+	})
+}
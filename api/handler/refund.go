@@ -0,0 +1,267 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	pb "api-gateway/genproto/payments"
+	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"api-gateway/pkg/payment"
+	"api-gateway/storage"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// refundReasonCancelled is the reason of the refunds made when a booking
+// is cancelled.
+const refundReasonCancelled = "booking cancelled"
+
+// RefundPayment godoc
+// @Summary Refunds payment
+// @Description Gives back part or all of a payment collected through the processor, with a reason; without an
+// @Description amount, everything that can still be refunded is. The booking's provider and admins can refund
+// @Description the whole payment. Customers can only get refunds of cancelled bookings, within the cancellation
+// @Description policy: by default a full refund when cancelled more than 24 hours before the booking starts and
+// @Description 50% after that, or a full one when the provider or an admin cancelled.
+// @Description Cancelling a paid booking refunds it by the same rules, so this is mostly needed when that failed.
+// @Description A refund that would exceed what can be refunded fails with the amount that still can.
+// @Tags payment
+// @Security ApiKeyAuth
+// @Param id path string true "Payment ID"
+// @Param data body models.RefundCreate true "Refund"
+// @Success 201 {object} models.Refund
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 409 {object} string "Payment cannot be refunded"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 502 {object} string "Processor failed to refund"
+// @Failure 503 {object} string "Payments are not configured"
+// @Router /payments/{id}/refund [post]
+func (h *Handler) RefundPayment(c *gin.Context) {
+	h.Logger.Info("RefundPayment handler is invoked")
+
+	var req models.RefundCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	r, err := h.refundPayment(ctx, c, c.Param("id"), req)
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("RefundPayment handler is completed")
+	c.JSON(http.StatusCreated, r)
+}
+
+// refundPayment refunds a payment on behalf of the caller, as much as
+// they are allowed to.
+func (h *Handler) refundPayment(ctx context.Context, c *gin.Context, id string, req models.RefundCreate) (*models.Refund, error) {
+	userID, err := getUserID(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	role, err := getUserRole(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	p, err := h.Payment.GetPayment(ctx, &pb.ID{Id: id})
+	if err != nil {
+		return nil, newStatusError(err, "error finding payment", http.StatusInternalServerError)
+	}
+
+	b, err := h.getBooking(ctx, p.BookingId)
+	if err != nil {
+		return nil, newStatusError(err, "error finding booking", http.StatusInternalServerError)
+	}
+
+	if err := h.checkBookingAccess(ctx, c, b); err != nil {
+		return nil, newStatusError(err, "access denied", http.StatusForbidden)
+	}
+
+	pi, err := h.Storage.GetPaymentIntentByProcessorID(ctx, h.Processor.Name(), p.TransactionId)
+	if err == storage.ErrNotFound {
+		return nil, newStatusError(nil, "payment was not collected through the processor", http.StatusConflict)
+	}
+	if err != nil {
+		return nil, newStatusError(err, "error finding payment intent", http.StatusInternalServerError)
+	}
+
+	if pi.Status != payment.StatusSucceeded {
+		return nil, newStatusError(nil, "payment intent is "+pi.Status, http.StatusConflict)
+	}
+	if role == booking.RoleCustomer && b.Status != booking.StatusCancelled {
+		return nil, newStatusError(nil, "booking is not cancelled", http.StatusConflict)
+	}
+
+	limit, err := h.refundLimit(ctx, role, b, pi)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.refund(ctx, pi, req.Amount, req.Reason, userID, limit)
+}
+
+// refundLimit returns how much of the intent may be refunded in all on
+// behalf of a user with the given role. Customers get what the
+// cancellation policy grants for the notice they gave when the booking
+// was cancelled, and everything when the provider or an admin cancelled
+// it; the provider and admins can refund everything.
+func (h *Handler) refundLimit(ctx context.Context, role string, b *pbb.Booking, pi *models.PaymentIntent) (float32, error) {
+	if role != booking.RoleCustomer {
+		return pi.Amount, nil
+	}
+
+	scheduledAt, err := time.Parse(time.RFC3339, b.ScheduledTime)
+	if err != nil {
+		return 0, newStatusError(err, "invalid booking time", http.StatusInternalServerError)
+	}
+
+	// Bookings cancelled before cancellations were recorded count from
+	// now.
+	cancelledAt := time.Now()
+	cancellation, err := h.Storage.GetCancellation(ctx, b.Id)
+	switch err {
+	case nil:
+		if cancellation.Role != booking.RoleCustomer {
+			return pi.Amount, nil
+		}
+		cancelledAt = cancellation.CancelledAt
+	case storage.ErrNotFound:
+	default:
+		return 0, newStatusError(err, "error finding cancellation", http.StatusInternalServerError)
+	}
+
+	notice := scheduledAt.Sub(cancelledAt)
+	return payment.FromMinor(h.RefundPolicy.Refundable(payment.ToMinor(pi.Amount), notice)), nil
+}
+
+// refund gives back amount of a succeeded intent, or all that is left
+// under limit when amount is zero, and publishes the refund.
+func (h *Handler) refund(ctx context.Context, pi *models.PaymentIntent, amount float32, reason, requestedBy string,
+	limit float32) (*models.Refund, error) {
+	refundable := payment.FromMinor(payment.ToMinor(limit) - payment.ToMinor(pi.AmountRefunded))
+	if refundable <= 0 {
+		return nil, newStatusError(nil, "nothing left to refund", http.StatusConflict)
+	}
+	if amount == 0 {
+		amount = refundable
+	}
+	if payment.ToMinor(amount) > payment.ToMinor(refundable) {
+		err := newStatusError(nil, "refund exceeds the refundable amount", http.StatusConflict)
+		err.extra = gin.H{"refundable": refundable}
+		return nil, err
+	}
+
+	r := &models.Refund{
+		ID:              uuid.NewString(),
+		PaymentIntentID: pi.ID,
+		BookingID:       pi.BookingID,
+		Amount:          amount,
+		Currency:        pi.Currency,
+		Reason:          reason,
+		Status:          payment.StatusPending,
+		RequestedBy:     requestedBy,
+	}
+
+	err := h.Storage.CreateRefund(ctx, r, limit)
+	if err == storage.ErrConflict {
+		// Another refund was made meanwhile.
+		return nil, newStatusError(err, "refund exceeds the refundable amount", http.StatusConflict)
+	}
+	if err != nil {
+		return nil, newStatusError(err, "error saving refund", http.StatusInternalServerError)
+	}
+
+	refund, err := h.Processor.Refund(ctx, payment.RefundParams{
+		IntentID:       pi.ProcessorIntentID,
+		Amount:         payment.ToMinor(amount),
+		Reason:         reason,
+		IdempotencyKey: r.ID,
+	})
+	if err != nil {
+		r.Status = payment.StatusFailed
+		if uerr := h.Storage.UpdateRefund(ctx, r); uerr != nil {
+			h.Logger.Error(errors.Wrap(uerr, "error saving refund").Error())
+		}
+
+		if err == payment.ErrRefundRejected {
+			return nil, newStatusError(err, "processor rejected the refund", http.StatusConflict)
+		}
+		return nil, processorError(err, "error refunding payment")
+	}
+
+	r.ProcessorRefundID = refund.ID
+	r.Status = refund.Status
+
+	// The money was sent back either way, so the refund is published even
+	// if saving it fails.
+	if err := h.Storage.UpdateRefund(ctx, r); err != nil {
+		h.Logger.Error(errors.Wrap(err, "error saving refund").Error(), "refund_id", r.ID)
+	}
+
+	message, err := json.Marshal(&pb.PaymentRefund{
+		Id:            r.ID,
+		TransactionId: pi.ProcessorIntentID,
+		BookingId:     r.BookingID,
+		Amount:        r.Amount,
+		Reason:        r.Reason,
+		Status:        r.Status,
+	})
+	if err != nil {
+		return nil, newStatusError(err, "error serializing refund", http.StatusInternalServerError)
+	}
+
+	err = h.KafkaProducer.Produce(ctx, h.TopicPaymentRefunded, message)
+	if err != nil {
+		return nil, newStatusError(err, "error publishing refund", http.StatusInternalServerError)
+	}
+
+	return r, nil
+}
+
+// refundCancelledBooking refunds the payment of a booking cancelled on
+// behalf of a user with the given role, as the cancellation policy says
+// when the customer cancelled and fully otherwise. Failures are logged
+// rather than failing the cancellation; the refund can then be made with
+// RefundPayment.
+func (h *Handler) refundCancelledBooking(ctx context.Context, b *pbb.Booking, role, userID string) {
+	pi, err := h.Storage.GetActivePaymentIntent(ctx, b.Id)
+	if err == storage.ErrNotFound {
+		return
+	}
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error finding payment intent of cancelled booking").Error(), "booking_id", b.Id)
+		return
+	}
+
+	// Only captured payments are refunded.
+	if pi.Status != payment.StatusSucceeded {
+		return
+	}
+
+	limit, err := h.refundLimit(ctx, role, b, pi)
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error refunding cancelled booking").Error(), "booking_id", b.Id)
+		return
+	}
+	if payment.ToMinor(limit) <= payment.ToMinor(pi.AmountRefunded) {
+		return
+	}
+
+	if _, err := h.refund(ctx, pi, 0, refundReasonCancelled, userID, limit); err != nil {
+		h.Logger.Error(errors.Wrap(err, "error refunding cancelled booking").Error(), "booking_id", b.Id)
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	"api-gateway/kafka/consumer"
+	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"api-gateway/pkg/notify"
+	"api-gateway/storage"
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// reminderBatchSize is how many due reminders an instance claims at a
+	// time.
+	reminderBatchSize = 50
+	// reminderLease is how long a claimed reminder is held for the
+	// instance sending it before another may take it over.
+	reminderLease = 5 * time.Minute
+)
+
+// runReminders sends the due booking reminders every pollInterval and
+// schedules the reminders of the upcoming bookings every scanInterval.
+// Reminders are kept in storage, so they outlive restarts, and each is
+// claimed by one instance before it is sent.
+func (h *Handler) runReminders(pollInterval, scanInterval time.Duration) {
+	if len(h.ReminderOffsets) == 0 {
+		return
+	}
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	scan := time.NewTicker(scanInterval)
+	defer scan.Stop()
+
+	h.scheduleUpcomingReminders(scanInterval)
+	for {
+		select {
+		case <-poll.C:
+			h.sendDueReminders()
+		case <-scan.C:
+			h.scheduleUpcomingReminders(scanInterval)
+		}
+	}
+}
+
+// scheduleUpcomingReminders schedules the reminders of the active bookings
+// whose first reminder falls before the scan after next. The events keep
+// them up to date in between; the scan catches the bookings whose events
+// were missed.
+func (h *Handler) scheduleUpcomingReminders(scanInterval time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), scanInterval)
+	defer cancel()
+
+	bookings, err := listAll(ctx, h.bookingsPage)
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error fetching bookings for reminders").Error())
+		return
+	}
+
+	now := time.Now()
+	horizon := now.Add(h.ReminderOffsets[0] + 2*scanInterval)
+
+	for _, b := range bookings {
+		if !booking.IsModifiable(b.Status) {
+			continue
+		}
+
+		scheduledAt, err := time.Parse(time.RFC3339, b.ScheduledTime)
+		if err != nil || !scheduledAt.After(now) || scheduledAt.After(horizon) {
+			continue
+		}
+
+		if err := h.Storage.ScheduleReminders(ctx, b.Id, scheduledAt, h.ReminderOffsets); err != nil {
+			h.Logger.Error(errors.Wrap(err, "error scheduling reminders").Error())
+		}
+	}
+}
+
+func (h *Handler) sendDueReminders() {
+	ctx, cancel := context.WithTimeout(context.Background(), reminderLease)
+	defer cancel()
+
+	reminders, err := h.Storage.ClaimDueReminders(ctx, reminderBatchSize, reminderLease)
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error claiming reminders").Error())
+		return
+	}
+
+	for _, r := range reminders {
+		status, err := h.sendReminder(ctx, r)
+		if err != nil {
+			// The claim runs out and the reminder is retried.
+			h.Logger.Error(errors.Wrap(err, "error sending reminder").Error())
+			continue
+		}
+
+		if err := h.Storage.FinishReminder(ctx, r, status); err != nil {
+			h.Logger.Error(errors.Wrap(err, "error finishing reminder").Error())
+		}
+	}
+}
+
+// sendReminder notifies the customer of a booking that it is coming up and
+// returns the status the reminder ends with. A booking that is no longer
+// active or has moved gets no reminder.
+func (h *Handler) sendReminder(ctx context.Context, r *storage.Reminder) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.ContextTimeout)
+	defer cancel()
+
+	b, err := h.getBooking(ctx, r.BookingID)
+	if err != nil {
+		return "", errors.Wrap(err, "error finding booking")
+	}
+
+	scheduledAt, err := time.Parse(time.RFC3339, b.ScheduledTime)
+	if !booking.IsModifiable(b.Status) || err != nil || !scheduledAt.Equal(r.ScheduledTime) {
+		return storage.ReminderCancelled, nil
+	}
+
+	data := notify.Data{StartsIn: startsIn(time.Until(scheduledAt))}
+	receivers, err := h.bookingNotification(ctx, &data, b.Id)
+	if err != nil {
+		return "", err
+	}
+
+	userID := receivers[notify.Customer]
+	msg, ok, err := h.Templates.Render(h.userLocale(ctx, userID), notify.EventBookingReminder, notify.Customer, data)
+	if err != nil {
+		return "", errors.Wrap(err, "error rendering reminder")
+	}
+	if !ok {
+		return storage.ReminderCancelled, nil
+	}
+
+	err = h.createNotification(ctx, models.NotificationCreate{
+		UserID:  userID,
+		Title:   msg.Title,
+		Message: msg.Message,
+		Type:    notify.EventBookingReminder,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return storage.ReminderSent, nil
+}
+
+// remindEvents keeps the reminders in step with the bookings: a booking
+// that is cancelled or finished has its reminders cancelled, and one that
+// is rescheduled has them set for the new time.
+func (h *Handler) remindEvents(events consumer.IKafkaConsumer) {
+	h.consumeEvents(events, "reminder", h.remindEvent)
+}
+
+func (h *Handler) remindEvent(topic string, msg []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	var err error
+
+	switch topic {
+	case h.TopicBookingCancelled:
+		var b pbb.ID
+		if err = json.Unmarshal(msg, &b); err == nil {
+			err = h.Storage.CancelReminders(ctx, b.Id)
+		}
+	case h.TopicBookingUpdated:
+		var u pbb.NewData
+		if err = json.Unmarshal(msg, &u); err != nil {
+			break
+		}
+
+		if u.Status != "" && !booking.IsModifiable(u.Status) {
+			err = h.Storage.CancelReminders(ctx, u.Id)
+			break
+		}
+
+		if u.ScheduledTime != "" {
+			var scheduledAt time.Time
+			if scheduledAt, err = time.Parse(time.RFC3339, u.ScheduledTime); err == nil {
+				err = h.Storage.ScheduleReminders(ctx, u.Id, scheduledAt, h.ReminderOffsets)
+			}
+		}
+	default:
+		return
+	}
+
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error updating reminders").Error())
+	}
+}
+
+// startsIn formats the time until a booking to the nearest five minutes,
+// e.g. 24h or 1h30m.
+func startsIn(d time.Duration) string {
+	d = d.Round(5 * time.Minute)
+	if d < 5*time.Minute {
+		d = 5 * time.Minute
+	}
+
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// parseReminderOffsets reads a comma-separated list of durations, e.g.
+// "24h,1h", longest first. Invalid entries are skipped.
+func parseReminderOffsets(list string) []time.Duration {
+	var offsets []time.Duration
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			log.Println(errors.Errorf("invalid reminder offset %q, skipping it", s))
+			continue
+		}
+		offsets = append(offsets, d)
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+	return offsets
+}
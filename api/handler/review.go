@@ -35,29 +35,37 @@ func (h *Handler) CreateReview(c *gin.Context) {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	if err := h.createReview(ctx, id, req); err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("CreateReview handler is completed")
+	c.JSON(http.StatusCreated, "Review created")
+}
+
+// createReview publishes a review written by the user.
+func (h *Handler) createReview(ctx context.Context, userID string, req models.ReviewCreate) error {
 	message, err := json.Marshal(pb.NewReview{
-		UserId:     id,
+		UserId:     userID,
 		BookingId:  req.BookingID,
 		ProviderId: req.ProviderID,
 		Rating:     req.Rating,
 		Comment:    req.Comment,
 	})
 	if err != nil {
-		handleError(c, h, err, "error serializing review", http.StatusInternalServerError)
-		return
+		return newStatusError(err, "error serializing review", http.StatusInternalServerError)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
-	defer cancel()
-
 	err = h.KafkaProducer.Produce(ctx, h.TopicReviewCreated, []byte(message))
 	if err != nil {
-		handleError(c, h, err, "error creating review", http.StatusInternalServerError)
-		return
+		return newStatusError(err, "error creating review", http.StatusInternalServerError)
 	}
 
-	h.Logger.Info("CreateReview handler is completed")
-	c.JSON(http.StatusCreated, "Review created")
+	return nil
 }
 
 // GetReview godoc
@@ -66,7 +74,8 @@ func (h *Handler) CreateReview(c *gin.Context) {
 // @Tags review
 // @Security ApiKeyAuth
 // @Param id path string true "Review ID"
-// @Success 200 {object} reviews.Review
+// @Param expand query string false "Related resources to inline: booking, provider"
+// @Success 200 {object} models.ReviewExpanded
 // @Failure 400 {object} string "Invalid data format"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /reviews/{id} [get]
@@ -79,15 +88,26 @@ func (h *Handler) GetReview(c *gin.Context) {
 		return
 	}
 
+	expand, err := parseExpand(c, reviewRelations)
+	if err != nil {
+		handleError(c, h, err, "invalid expand parameter", http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
-	resp, err := h.Review.GetReview(ctx, &pb.ID{Id: id})
+	item, err := h.Review.GetReview(ctx, &pb.ID{Id: id})
 	if err != nil {
 		handleError(c, h, err, "error getting review", http.StatusInternalServerError)
 		return
 	}
 
+	var resp interface{} = item
+	if len(expand) > 0 {
+		resp = h.expandReviews(ctx, c, []*pb.Review{item}, expand)[0]
+	}
+
 	h.Logger.Info("GetReview handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
@@ -172,39 +192,156 @@ func (h *Handler) DeleteReview(c *gin.Context) {
 // @Description Fetches reviews
 // @Tags review
 // @Security ApiKeyAuth
-// @Param page query int true "Page number"
-// @Param limit query int true "Number of items per page"
-// @Success 200 {object} reviews.ReviewsList
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. rating:gte:4,created_at:gte:2026-10-01"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Param expand query string false "Related resources to inline: booking, provider"
+// @Success 200 {object} models.ListResp{data=[]models.ReviewExpanded}
 // @Failure 400 {object} string "Invalid data format"
 // @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "List too long to filter in the gateway"
 // @Router /reviews/all [get]
 func (h *Handler) FetchReviews(c *gin.Context) {
 	h.Logger.Info("FetchReviews handler is invoked")
 
-	pageStr := c.Query("page")
-	limitStr := c.Query("limit")
-
-	page, err := parseIntQueryParam(pageStr)
+	expand, err := parseExpand(c, reviewRelations)
 	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
+		handleError(c, h, err, "invalid expand parameter", http.StatusBadRequest)
 		return
 	}
 
-	limit, err := parseIntQueryParam(limitStr)
+	q, err := parseQuery(c, reviewFields)
 	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
-	defer cancel()
+	resp, ok := paginate(c, h, applyQuery(h.reviewScan.source(q), q), "error fetching reviews")
+	if !ok {
+		return
+	}
+
+	h.expandReviewList(c, resp, expand)
+
+	h.Logger.Info("FetchReviews handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
 
+func (h *Handler) reviewsPage(ctx context.Context, page, limit int32) ([]*pb.Review, error) {
 	resp, err := h.Review.ListReviews(ctx, &pb.Pagination{Page: page, Limit: limit})
 	if err != nil {
-		handleError(c, h, err, "error fetching reviews", http.StatusInternalServerError)
+		return nil, err
+	}
+	return resp.Reviews, nil
+}
+
+// FetchMyReviews godoc
+// @Summary Fetches my reviews
+// @Description Fetches reviews written by the current user
+// @Tags review
+// @Security ApiKeyAuth
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. rating:gte:4,created_at:gte:2026-10-01"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Param expand query string false "Related resources to inline: booking, provider"
+// @Success 200 {object} models.ListResp{data=[]models.ReviewExpanded}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "List too long to filter in the gateway"
+// @Router /users/me/reviews [get]
+func (h *Handler) FetchMyReviews(c *gin.Context) {
+	h.Logger.Info("FetchMyReviews handler is invoked")
+
+	id, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
 		return
 	}
 
-	h.Logger.Info("FetchReviews handler is completed")
+	expand, err := parseExpand(c, reviewRelations)
+	if err != nil {
+		handleError(c, h, err, "invalid expand parameter", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseQuery(c, reviewFields)
+	if err != nil {
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
+		return
+	}
+
+	scoped := filterAll(h.reviewScan.pages, func(r *pb.Review) bool {
+		return r.UserId == id
+	})
+
+	resp, ok := paginate(c, h, applyQuery(scoped, q), "error fetching reviews")
+	if !ok {
+		return
+	}
+
+	h.expandReviewList(c, resp, expand)
+
+	h.Logger.Info("FetchMyReviews handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// FetchProviderReviews godoc
+// @Summary Fetches provider reviews
+// @Description Fetches reviews left for a provider
+// @Tags review
+// @Security ApiKeyAuth
+// @Param id path string true "Provider ID"
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. rating:gte:4,created_at:gte:2026-10-01"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Param expand query string false "Related resources to inline: booking, provider"
+// @Success 200 {object} models.ListResp{data=[]models.ReviewExpanded}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 500 {object} string "Server error while processing request"
+// @Failure 503 {object} string "List too long to filter in the gateway"
+// @Router /providers/{id}/reviews [get]
+func (h *Handler) FetchProviderReviews(c *gin.Context) {
+	h.Logger.Info("FetchProviderReviews handler is invoked")
+
+	id := c.Param("id")
+	if id == "" {
+		handleError(c, h, nil, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	expand, err := parseExpand(c, reviewRelations)
+	if err != nil {
+		handleError(c, h, err, "invalid expand parameter", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseQuery(c, reviewFields)
+	if err != nil {
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
+		return
+	}
+
+	scoped := filterAll(h.reviewScan.pages, func(r *pb.Review) bool {
+		return r.ProviderId == id
+	})
+
+	resp, ok := paginate(c, h, applyQuery(scoped, q), "error fetching reviews")
+	if !ok {
+		return
+	}
+
+	h.expandReviewList(c, resp, expand)
+
+	h.Logger.Info("FetchProviderReviews handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
@@ -3,6 +3,7 @@ package handler
 import (
 	pb "api-gateway/genproto/services"
 	"api-gateway/models"
+	"api-gateway/pkg/query"
 	"context"
 	"net/http"
 
@@ -14,7 +15,7 @@ import (
 // @Description Adds a new service
 // @Tags service
 // @Security ApiKeyAuth
-// @Param data body services.NewService true "New service"
+// @Param data body models.ServiceCreate true "New service"
 // @Success 201 {object} services.CreateResp
 // @Failure 400 {object} string "Invalid data format"
 // @Failure 500 {object} string "Server error while processing request"
@@ -22,7 +23,7 @@ import (
 func (h *Handler) CreateService(c *gin.Context) {
 	h.Logger.Info("CreateService handler is invoked")
 
-	var req pb.NewService
+	var req models.ServiceCreate
 	if err := c.ShouldBind(&req); err != nil {
 		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
 		return
@@ -31,7 +32,12 @@ func (h *Handler) CreateService(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
 	defer cancel()
 
-	resp, err := h.Service.CreateService(ctx, &req)
+	resp, err := h.Service.CreateService(ctx, &pb.NewService{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Duration:    req.Duration,
+	})
 	if err != nil {
 		handleError(c, h, err, "error creating service", http.StatusInternalServerError)
 		return
@@ -155,39 +161,36 @@ func (h *Handler) DeleteService(c *gin.Context) {
 // @Description Fetches services
 // @Tags service
 // @Security ApiKeyAuth
-// @Param page query int true "Page number"
-// @Param limit query int true "Number of items per page"
-// @Success 200 {object} services.ServicesList
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Param filter query string false "Filter, e.g. price:lte:100,duration:lte:60"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -created_at"
+// @Success 200 {object} models.ListResp{data=[]services.Service}
 // @Failure 400 {object} string "Invalid pagination parameter"
 // @Failure 500 {object} string "Server error while processing request"
 // @Router /services/all [get]
 func (h *Handler) FetchServices(c *gin.Context) {
 	h.Logger.Info("FetchServices handler is invoked")
 
-	pageStr := c.Query("page")
-	limitStr := c.Query("limit")
-
-	page, err := parseIntQueryParam(pageStr)
+	q, err := parseQuery(c, serviceFields)
 	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
+		handleError(c, h, err, "invalid query parameter", http.StatusBadRequest)
 		return
 	}
 
-	limit, err := parseIntQueryParam(limitStr)
-	if err != nil {
-		handleError(c, h, err, "invalid pagination parameter", http.StatusBadRequest)
-		return
+	// The backend search understands names, so use it to narrow the list
+	// before the remaining conditions are applied here.
+	source := h.servicesPage
+	if name, ok := q.Lookup("name", query.OpEq); ok {
+		source = h.servicesSearch(&pb.Filter{Name: name})
+	} else if name, ok := q.Lookup("name", query.OpLike); ok {
+		source = h.servicesSearch(&pb.Filter{Name: name})
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
-	defer cancel()
-
-	resp, err := h.Service.ListServices(ctx, &pb.Pagination{
-		Page:  page,
-		Limit: limit,
-	})
-	if err != nil {
-		handleError(c, h, err, "error fetching services", http.StatusInternalServerError)
+	resp, ok := paginate(c, h, applyQuery(source, q), "error fetching services")
+	if !ok {
 		return
 	}
 
@@ -267,3 +270,23 @@ func (h *Handler) GetPopularServices(c *gin.Context) {
 	h.Logger.Info("GetPopularServices handler is completed")
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *Handler) servicesPage(ctx context.Context, page, limit int32) ([]*pb.Service, error) {
+	resp, err := h.Service.ListServices(ctx, &pb.Pagination{Page: page, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Services, nil
+}
+
+// servicesSearch serves the unpaginated backend search as a list.
+func (h *Handler) servicesSearch(filter *pb.Filter) pageFetcher[*pb.Service] {
+	return func(ctx context.Context, page, limit int32) ([]*pb.Service, error) {
+		resp, err := h.Service.SearchServices(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return pageOf(resp.Services, page, limit), nil
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	pbn "api-gateway/genproto/notifications"
+	pbp "api-gateway/genproto/providers"
+	"api-gateway/kafka/consumer"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// Event types pushed on the stream, one per relayed topic.
+const (
+	eventBookingUpdated      = "booking_updated"
+	eventBookingCancelled    = "booking_cancelled"
+	eventNotificationCreated = "notification_created"
+)
+
+// consumeRetryDelay is how long a consumer of events waits before reading
+// again after it failed.
+const consumeRetryDelay = 5 * time.Second
+
+// Stream godoc
+// @Summary Streams the caller's events
+// @Description Pushes booking updates and cancellations of the caller's bookings, as customer or as
+// @Description provider, and notifications of the caller as Server-Sent Events. The event name is
+// @Description booking_updated, booking_cancelled or notification_created and the data is the message
+// @Description of the event. Comment lines are sent as heartbeats.
+// @Description To resume after a disconnect, pass the ID of the last event received; the events since
+// @Description are sent first. When some of them are no longer kept, a reset event is sent instead and
+// @Description the client should refetch its bookings. A client that falls too far behind gets an
+// @Description overflow event and is disconnected, and should reconnect to resume.
+// @Tags stream
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "Same as Last-Event-ID, for clients that cannot set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 401 {object} string "Invalid user"
+// @Router /stream [get]
+func (h *Handler) Stream(c *gin.Context) {
+	h.Logger.Info("Stream handler is invoked")
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub := h.Events.Subscribe(userID, lastEventID, h.StreamBufferSize)
+	defer h.Events.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if sub.Missed {
+		c.Render(-1, sse.Event{Event: "reset", Data: gin.H{"message": "some events are no longer available"}})
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			h.Logger.Info("Stream handler is completed")
+			return
+		case <-heartbeat.C:
+			c.Writer.WriteString(": heartbeat\n\n")
+		case e, ok := <-sub.Events():
+			if !ok {
+				h.Logger.Warn("stream of user " + userID + " fell behind and was dropped")
+				c.Render(-1, sse.Event{Event: "overflow", Data: gin.H{"message": "too many pending events, reconnect to resume"}})
+				c.Writer.Flush()
+				return
+			}
+
+			c.Render(-1, sse.Event{Id: e.ID, Event: e.Type, Data: e.Data})
+		}
+		c.Writer.Flush()
+	}
+}
+
+// relayEvents publishes the booking and notification messages on the
+// stream hub, addressed to the users they concern. Every gateway instance
+// serves its own clients, so each reads the topics in a group of its own.
+func (h *Handler) relayEvents(events consumer.IKafkaConsumer) {
+	h.consumeEvents(events, "stream", h.relayEvent)
+}
+
+// consumeEvents passes every message of events to handle for as long as
+// the gateway runs.
+func (h *Handler) consumeEvents(events consumer.IKafkaConsumer, name string, handle func(topic string, msg []byte)) {
+	for {
+		err := events.Consume(context.Background(), handle)
+		h.Logger.Error(errors.Wrapf(err, "error consuming %s events", name).Error())
+		time.Sleep(consumeRetryDelay)
+	}
+}
+
+func (h *Handler) relayEvent(topic string, msg []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	var (
+		typ   string
+		users []string
+		err   error
+	)
+
+	switch topic {
+	case h.TopicBookingUpdated, h.TopicBookingCancelled:
+		typ = eventBookingUpdated
+		if topic == h.TopicBookingCancelled {
+			typ = eventBookingCancelled
+		}
+
+		var b pbb.ID
+		if err = json.Unmarshal(msg, &b); err == nil {
+			users, err = h.bookingAudience(ctx, b.Id)
+		}
+	case h.TopicNotificationCreated:
+		typ = eventNotificationCreated
+
+		var n pbn.NewNotification
+		if err = json.Unmarshal(msg, &n); err == nil {
+			users = []string{n.UserId}
+		}
+	default:
+		return
+	}
+
+	if err != nil {
+		h.Logger.Error(errors.Wrapf(err, "error relaying %s event", typ).Error())
+		return
+	}
+
+	h.Events.Publish(typ, msg, users)
+}
+
+// bookingAudience returns the customer of a booking and the user who runs
+// its provider.
+func (h *Handler) bookingAudience(ctx context.Context, bookingID string) ([]string, error) {
+	b, err := h.Booking.GetBooking(ctx, &pbb.ID{Id: bookingID})
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding booking")
+	}
+
+	p, err := h.Provider.GetProvider(ctx, &pbp.ID{Id: b.ProviderId})
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding provider")
+	}
+
+	return []string{b.UserId, p.UserId}, nil
+}
+
+// streamGroupID gives the instance a consumer group of its own, so that
+// every instance gets every message.
+func streamGroupID(prefix string) string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return prefix + "-" + host
+}
@@ -0,0 +1,579 @@
+package handler
+
+import (
+	pbb "api-gateway/genproto/bookings"
+	pbpa "api-gateway/genproto/payments"
+	pbr "api-gateway/genproto/reviews"
+	"api-gateway/kafka/consumer"
+	"api-gateway/models"
+	"api-gateway/pkg/booking"
+	"api-gateway/pkg/webhook"
+	"api-gateway/storage"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	// webhookBatchSize is how many due deliveries an instance claims at a
+	// time.
+	webhookBatchSize = 50
+	// webhookConcurrency is how many claimed deliveries are sent at once.
+	// With the sender's timeout it keeps a batch well inside the lease.
+	webhookConcurrency = 10
+	// webhookLease is how long a claimed delivery is held for the
+	// instance sending it before another may take it over.
+	webhookLease = 2 * time.Minute
+	// webhookMaxBackoff caps the wait between attempts.
+	webhookMaxBackoff = time.Hour
+)
+
+// CreateWebhook godoc
+// @Summary Creates webhook
+// @Description Subscribes a URL to events of the current user's bookings, as customer or as provider,
+// @Description and of their payments and reviews; an admin's subscriptions get the events of all bookings.
+// @Description Events are booking_created, booking_updated, booking_cancelled, payment_created,
+// @Description payment_updated, payment_refunded and review_created. Each delivery is a POST of {id, event, created_at, data}, where data is
+// @Description the message of the event, with the headers X-Webhook-Id, X-Webhook-Event,
+// @Description X-Webhook-Timestamp (Unix seconds) and X-Webhook-Signature, which is "v1=" followed by the
+// @Description hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret. Receivers should reject
+// @Description deliveries whose timestamp is more than a few minutes old. The secret is only returned here.
+// @Description Deliveries not answered with a 2xx are retried with exponential backoff, and end up dead
+// @Description when the attempts run out.
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param data body models.WebhookCreate true "Webhook"
+// @Success 201 {object} models.Webhook
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /webhooks [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	h.Logger.Info("CreateWebhook handler is invoked")
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	role, err := getUserRole(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.WebhookCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		handleError(c, h, err, "error generating secret", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	w := &models.Webhook{UserID: userID, URL: req.URL, Events: req.Events, Secret: secret}
+	if err := h.Storage.CreateWebhook(ctx, w, role); err != nil {
+		handleError(c, h, err, "error creating webhook", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("CreateWebhook handler is completed")
+	c.JSON(http.StatusCreated, w)
+}
+
+// GetWebhook godoc
+// @Summary Gets webhook
+// @Description Gets a webhook of the current user
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.Webhook
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Webhook not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /webhooks/{id} [get]
+func (h *Handler) GetWebhook(c *gin.Context) {
+	h.Logger.Info("GetWebhook handler is invoked")
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	w, err := h.getOwnWebhook(ctx, c, c.Param("id"))
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("GetWebhook handler is completed")
+	c.JSON(http.StatusOK, w)
+}
+
+// UpdateWebhook godoc
+// @Summary Updates webhook
+// @Description Changes the URL or the events of a webhook of the current user, or pauses and resumes it.
+// @Description The deliveries of a paused webhook wait until it is resumed.
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param data body models.WebhookUpdate true "Changes"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Webhook not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /webhooks/{id} [put]
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	h.Logger.Info("UpdateWebhook handler is invoked")
+
+	var req models.WebhookUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, h, err, "invalid data format", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	w, err := h.getOwnWebhook(ctx, c, c.Param("id"))
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	if req.URL != "" {
+		w.URL = req.URL
+	}
+	if len(req.Events) > 0 {
+		w.Events = req.Events
+	}
+	if req.Active != nil {
+		w.Active = *req.Active
+	}
+
+	if err := h.Storage.UpdateWebhook(ctx, w); err != nil {
+		handleError(c, h, err, "error updating webhook", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("UpdateWebhook handler is completed")
+	c.JSON(http.StatusOK, w)
+}
+
+// DeleteWebhook godoc
+// @Summary Deletes webhook
+// @Description Deletes a webhook of the current user along with its deliveries
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} string "Webhook deleted successfully"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Webhook not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /webhooks/{id} [delete]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	h.Logger.Info("DeleteWebhook handler is invoked")
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	w, err := h.getOwnWebhook(ctx, c, c.Param("id"))
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	if err := h.Storage.DeleteWebhook(ctx, w.ID); err != nil {
+		handleError(c, h, err, "error deleting webhook", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("DeleteWebhook handler is completed")
+	c.JSON(http.StatusOK, "Webhook deleted successfully")
+}
+
+// FetchMyWebhooks godoc
+// @Summary Fetches my webhooks
+// @Description Fetches the webhooks of the current user, newest first
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Success 200 {object} models.ListResp{data=[]models.Webhook}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 401 {object} string "Invalid user"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /webhooks [get]
+func (h *Handler) FetchMyWebhooks(c *gin.Context) {
+	h.Logger.Info("FetchMyWebhooks handler is invoked")
+
+	userID, err := getUserID(c)
+	if err != nil {
+		handleError(c, h, err, "invalid user", http.StatusUnauthorized)
+		return
+	}
+
+	resp, ok := paginate(c, h, func(ctx context.Context, page, limit int32) ([]*models.Webhook, error) {
+		return h.Storage.ListWebhooks(ctx, userID, page, limit)
+	}, "error fetching webhooks")
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("FetchMyWebhooks handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// FetchWebhookDeliveries godoc
+// @Summary Fetches webhook deliveries
+// @Description Fetches the deliveries of a webhook of the current user, newest first. Filter by status
+// @Description dead to see the deliveries whose attempts ran out.
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Status: pending, delivered or dead"
+// @Param cursor query string false "Cursor from a previous page"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of items per page"
+// @Param include_total query bool false "Count all items"
+// @Success 200 {object} models.ListResp{data=[]models.WebhookDelivery}
+// @Failure 400 {object} string "Invalid data format"
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Webhook not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *Handler) FetchWebhookDeliveries(c *gin.Context) {
+	h.Logger.Info("FetchWebhookDeliveries handler is invoked")
+
+	status := c.Query("status")
+	switch status {
+	case "", storage.WebhookPending, storage.WebhookDelivered, storage.WebhookDead:
+	default:
+		handleError(c, h, nil, "invalid status", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	w, err := h.getOwnWebhook(ctx, c, c.Param("id"))
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	resp, ok := paginate(c, h, func(ctx context.Context, page, limit int32) ([]*models.WebhookDelivery, error) {
+		return h.Storage.ListWebhookDeliveries(ctx, w.ID, status, page, limit)
+	}, "error fetching deliveries")
+	if !ok {
+		return
+	}
+
+	h.Logger.Info("FetchWebhookDeliveries handler is completed")
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWebhookDelivery godoc
+// @Summary Gets webhook delivery
+// @Description Gets a delivery of a webhook of the current user with the log of its attempts
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Delivery not found"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /webhooks/{id}/deliveries/{delivery_id} [get]
+func (h *Handler) GetWebhookDelivery(c *gin.Context) {
+	h.Logger.Info("GetWebhookDelivery handler is invoked")
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	d, err := h.getOwnWebhookDelivery(ctx, c)
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	h.Logger.Info("GetWebhookDelivery handler is completed")
+	c.JSON(http.StatusOK, d)
+}
+
+// RedeliverWebhook godoc
+// @Summary Redelivers webhook delivery
+// @Description Sends a delivery of a webhook of the current user again, as a new delivery with the same
+// @Description ID in the body and a fresh set of attempts. Deliveries still pending cannot be redelivered.
+// @Tags webhook
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 202 {object} models.WebhookDelivery
+// @Failure 403 {object} string "Access denied"
+// @Failure 404 {object} string "Delivery not found"
+// @Failure 409 {object} string "Delivery is still pending"
+// @Failure 500 {object} string "Server error while processing request"
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *Handler) RedeliverWebhook(c *gin.Context) {
+	h.Logger.Info("RedeliverWebhook handler is invoked")
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	d, err := h.getOwnWebhookDelivery(ctx, c)
+	if err != nil {
+		respondError(c, h, err)
+		return
+	}
+
+	if d.Status == storage.WebhookPending {
+		handleError(c, h, nil, "delivery is still pending", http.StatusConflict)
+		return
+	}
+
+	r, err := h.Storage.RedeliverWebhook(ctx, d)
+	if err != nil {
+		handleError(c, h, err, "error redelivering", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Info("RedeliverWebhook handler is completed")
+	c.JSON(http.StatusAccepted, r)
+}
+
+// getOwnWebhook fetches a webhook the caller is allowed to manage: their
+// own, or any for an admin.
+func (h *Handler) getOwnWebhook(ctx context.Context, c *gin.Context, id string) (*models.Webhook, error) {
+	userID, err := getUserID(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	role, err := getUserRole(c)
+	if err != nil {
+		return nil, newStatusError(err, "invalid user", http.StatusUnauthorized)
+	}
+
+	w, err := h.Storage.GetWebhook(ctx, id)
+	if err == storage.ErrNotFound {
+		return nil, newStatusError(nil, "webhook not found", http.StatusNotFound)
+	}
+	if err != nil {
+		return nil, newStatusError(err, "error finding webhook", http.StatusInternalServerError)
+	}
+
+	if w.UserID != userID && role != booking.RoleAdmin {
+		return nil, newStatusError(nil, "access denied", http.StatusForbidden)
+	}
+
+	return w, nil
+}
+
+// getOwnWebhookDelivery fetches the delivery from the path, checking
+// that it belongs to a webhook the caller is allowed to manage.
+func (h *Handler) getOwnWebhookDelivery(ctx context.Context, c *gin.Context) (*models.WebhookDelivery, error) {
+	w, err := h.getOwnWebhook(ctx, c, c.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := h.Storage.GetWebhookDelivery(ctx, c.Param("delivery_id"))
+	if err == storage.ErrNotFound || err == nil && d.WebhookID != w.ID {
+		return nil, newStatusError(nil, "delivery not found", http.StatusNotFound)
+	}
+	if err != nil {
+		return nil, newStatusError(err, "error finding delivery", http.StatusInternalServerError)
+	}
+
+	return d, nil
+}
+
+// webhookEvents queues a delivery of every event to the webhooks
+// subscribed to it. The instances share one consumer group, so every
+// event is queued once.
+func (h *Handler) webhookEvents(events consumer.IKafkaConsumer) {
+	h.consumeEvents(events, "webhook", h.webhookEvent)
+}
+
+func (h *Handler) webhookEvent(topic string, msg []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	var (
+		event string
+		users []string
+		err   error
+	)
+
+	switch topic {
+	case h.TopicBookingCreated:
+		event = "booking_created"
+
+		var b pbb.NewBooking
+		if err = json.Unmarshal(msg, &b); err != nil {
+			break
+		}
+
+		p, perr := h.getProvider(ctx, b.ProviderId)
+		if perr != nil {
+			err = errors.Wrap(perr, "error finding provider")
+			break
+		}
+		users = []string{b.UserId, p.UserId}
+	case h.TopicBookingUpdated, h.TopicBookingCancelled:
+		event = "booking_updated"
+		if topic == h.TopicBookingCancelled {
+			event = "booking_cancelled"
+		}
+
+		var b pbb.ID
+		if err = json.Unmarshal(msg, &b); err == nil {
+			users, err = h.bookingAudience(ctx, b.Id)
+		}
+	case h.TopicPaymentCreated:
+		event = "payment_created"
+
+		var p pbpa.NewPayment
+		if err = json.Unmarshal(msg, &p); err == nil {
+			users, err = h.bookingAudience(ctx, p.BookingId)
+		}
+	case h.TopicPaymentUpdated:
+		event = "payment_updated"
+
+		var p pbpa.PaymentUpdate
+		if err = json.Unmarshal(msg, &p); err == nil {
+			users, err = h.bookingAudience(ctx, p.BookingId)
+		}
+	case h.TopicPaymentRefunded:
+		event = "payment_refunded"
+
+		var r pbpa.PaymentRefund
+		if err = json.Unmarshal(msg, &r); err == nil {
+			users, err = h.bookingAudience(ctx, r.BookingId)
+		}
+	case h.TopicReviewCreated:
+		event = "review_created"
+
+		var r pbr.NewReview
+		if err = json.Unmarshal(msg, &r); err == nil {
+			users, err = h.bookingAudience(ctx, r.BookingId)
+		}
+	default:
+		return
+	}
+
+	if err == nil {
+		err = h.Storage.EnqueueWebhookEvent(ctx, event, msg, users, booking.RoleAdmin)
+	}
+	if err != nil {
+		h.Logger.Error(errors.Wrapf(err, "error queueing %s webhooks", event).Error())
+	}
+}
+
+// runWebhookDeliveries sends the due webhook deliveries every
+// pollInterval. Deliveries are kept in storage and claimed by one
+// instance before they are sent.
+func (h *Handler) runWebhookDeliveries(pollInterval time.Duration) {
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+
+	for range poll.C {
+		h.sendDueWebhooks()
+	}
+}
+
+// sendDueWebhooks claims the due deliveries and sends them, a few at a
+// time, so that slow receivers do not hold up the rest of the batch.
+func (h *Handler) sendDueWebhooks() {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	jobs, err := h.Storage.ClaimWebhookDeliveries(ctx, webhookBatchSize, webhookLease)
+	cancel()
+	if err != nil {
+		h.Logger.Error(errors.Wrap(err, "error claiming webhook deliveries").Error())
+		return
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, webhookConcurrency)
+	)
+
+	for _, j := range jobs {
+		sem <- struct{}{}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			h.deliverWebhook(j)
+		}()
+	}
+	wg.Wait()
+}
+
+// deliverWebhook makes one attempt at a claimed delivery and records it,
+// each under its own timeout.
+func (h *Handler) deliverWebhook(j *storage.WebhookJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	result := h.sendWebhook(ctx, j)
+	cancel()
+
+	ctx, cancel = context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	if err := h.Storage.RecordWebhookAttempt(ctx, j.Delivery.ID, result); err != nil {
+		h.Logger.Error(errors.Wrap(err, "error recording webhook attempt").Error())
+	}
+}
+
+// sendWebhook makes one attempt at a delivery and decides what follows:
+// done, another attempt after a backoff, or dead once the attempts run
+// out.
+func (h *Handler) sendWebhook(ctx context.Context, j *storage.WebhookJob) storage.WebhookResult {
+	// Redeliveries go out under the ID of the original, so receivers can
+	// tell them apart from new events.
+	id := j.Delivery.ID
+	if j.Delivery.RedeliveryOf != "" {
+		id = j.Delivery.RedeliveryOf
+	}
+
+	start := time.Now()
+	status, err := h.Webhooks.Send(ctx, j.URL, j.Secret, webhook.Delivery{
+		ID:        id,
+		Event:     j.Delivery.Event,
+		CreatedAt: j.Delivery.CreatedAt,
+		Data:      j.Delivery.Payload,
+	})
+
+	result := storage.WebhookResult{ResponseStatus: status, Duration: time.Since(start), Status: storage.WebhookDelivered}
+	if err == nil {
+		return result
+	}
+
+	result.Error = err.Error()
+	attempts := j.Delivery.Attempts + 1
+	if attempts >= h.WebhookMaxAttempts {
+		h.Logger.Warn("webhook delivery " + j.Delivery.ID + " ran out of attempts: " + result.Error)
+		result.Status = storage.WebhookDead
+		return result
+	}
+
+	result.Status = storage.WebhookPending
+	result.NextAttemptAt = time.Now().Add(webhook.Backoff(attempts, h.WebhookRetryBackoff, webhookMaxBackoff))
+	return result
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldTree holds the requested fields, one level per dotted segment.
+// An empty subtree keeps the whole value.
+type fieldTree map[string]fieldTree
+
+// Fields prunes successful JSON responses down to the fields listed in
+// the "fields" query parameter, e.g. ?fields=id,company_name,location.city.
+// Paths apply to every element of a list, to every item of a list
+// envelope's data and to every item of an object that only wraps a list,
+// such as {"providers": [...]}, rather than to the wrapper itself.
+// Responses other than JSON, such as event streams and CSV exports, are
+// passed through as they are written.
+func Fields() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := c.Query("fields")
+		if fields == "" {
+			c.Next()
+			return
+		}
+
+		tree := parseFields(fields)
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.passthrough {
+			return
+		}
+
+		body := w.buf.Bytes()
+		if w.Status() < 300 {
+			if pruned, err := pruneJSON(body, tree); err == nil {
+				body = pruned
+			}
+		}
+
+		w.ResponseWriter.Write(body)
+	}
+}
+
+// bufferedWriter holds back a JSON response so it can be pruned. Any
+// other response is passed through, which it decides on the first write
+// from the content type set by then.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	decided     bool
+	passthrough bool
+}
+
+func (w *bufferedWriter) decide() {
+	if !w.decided {
+		w.decided = true
+		w.passthrough = !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.decide()
+	if w.passthrough {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.buf.WriteString(s)
+}
+
+// Flush sends what a streamed response has written so far. A buffered
+// response is sent whole at the end.
+func (w *bufferedWriter) Flush() {
+	w.decide()
+	if w.passthrough {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func parseFields(s string) fieldTree {
+	tree := fieldTree{}
+
+	for _, path := range strings.Split(s, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		node := tree
+		for _, part := range strings.Split(path, ".") {
+			next, ok := node[part]
+			if !ok {
+				next = fieldTree{}
+				node[part] = next
+			}
+			node = next
+		}
+	}
+
+	return tree
+}
+
+func pruneJSON(body []byte, tree fieldTree) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if envelope, ok := doc.(map[string]interface{}); ok {
+		if _, isList := envelope["page_info"]; isList {
+			envelope["data"] = prune(envelope["data"], tree)
+			return json.Marshal(envelope)
+		}
+
+		if len(envelope) == 1 {
+			for key, field := range envelope {
+				if list, isList := field.([]interface{}); isList {
+					envelope[key] = prune(list, tree)
+					return json.Marshal(envelope)
+				}
+			}
+		}
+	}
+
+	return json.Marshal(prune(doc, tree))
+}
+
+func prune(v interface{}, tree fieldTree) interface{} {
+	if len(tree) == 0 {
+		return v
+	}
+
+	switch val := v.(type) {
+	case []interface{}:
+		for i := range val {
+			val[i] = prune(val[i], tree)
+		}
+		return val
+	case map[string]interface{}:
+		kept := make(map[string]interface{}, len(tree))
+		for key, sub := range tree {
+			if field, ok := val[key]; ok {
+				kept[key] = prune(field, sub)
+			}
+		}
+		return kept
+	default:
+		return v
+	}
+}
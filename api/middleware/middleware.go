@@ -90,6 +90,7 @@ func Check(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		c.Set("enforcer", e)
 		c.Next()
 	}
 }
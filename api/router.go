@@ -5,8 +5,10 @@ import (
 	"api-gateway/api/handler"
 	"api-gateway/api/middleware"
 	"api-gateway/config"
+	"api-gateway/pkg/validation"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -22,17 +24,35 @@ import (
 // @name Authorization
 func NewRouter(cfg *config.Config) *gin.Engine {
 	h := handler.NewHandler(cfg)
+	binding.Validator = validation.New()
 
 	router := gin.Default()
+	h.Router = router
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Payment processors cannot sign in, so their webhooks are outside
+	// Check; each event is verified by its signature instead.
+	router.POST("/car-wash/payments/webhooks/:provider", h.PaymentWebhook)
+
 	api := router.Group("/car-wash")
 	api.Use(middleware.Check(cfg))
+	api.Use(middleware.Fields())
+
+	api.POST("/graphql", h.GraphQL)
+	api.POST("/batch", h.Batch)
+	api.GET("/stream", h.Stream)
 
 	u := api.Group("/users")
 	{
 		u.GET("/profile", h.GetProfile)
 		u.PUT("/profile", h.UpdateProfile)
+		u.GET("/me/bookings", h.FetchMyBookings)
+		u.GET("/me/payments", h.FetchMyPayments)
+		u.GET("/me/reviews", h.FetchMyReviews)
+		u.GET("/me/notifications", h.FetchMyNotifications)
+		u.GET("/me/notification-preferences", h.GetNotificationPreferences)
+		u.PUT("/me/notification-preferences", h.UpdateNotificationPreferences)
+		u.GET("/me/notification-deliveries", h.FetchMyDeliveries)
 	}
 
 	p := api.Group("/providers")
@@ -43,6 +63,10 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 		p.DELETE("/:id", h.DeleteProvider)
 		p.GET("/all", h.FetchProviders)
 		p.GET("/search", h.SearchProviders)
+		p.GET("/:id/slots", h.GetProviderSlots)
+		p.GET("/:id/bookings", h.FetchProviderBookings)
+		p.GET("/:id/reviews", h.FetchProviderReviews)
+		p.GET("/:id/earnings", h.GetProviderEarnings)
 	}
 
 	s := api.Group("/services")
@@ -60,8 +84,14 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 	{
 		b.POST("", h.CreateBooking)
 		b.GET("/:id", h.GetBooking)
+		b.GET("/:id/details", h.GetBookingDetails)
+		b.GET("/:id/invoice", h.GetBookingInvoice)
+		b.POST("/:id/invoice/send", h.SendBookingInvoice)
 		b.PUT("/:id", h.UpdateBooking)
 		b.PUT("/:id/cancel", h.CancelBooking)
+		b.PUT("/:id/confirm", h.ConfirmBooking)
+		b.PUT("/:id/start", h.StartBooking)
+		b.PUT("/:id/complete", h.CompleteBooking)
 		b.GET("/all", h.FetchBookings)
 	}
 
@@ -69,7 +99,10 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 	{
 		pay.POST("", h.CreatePayment)
 		pay.GET("/:id", h.GetPayment)
+		pay.POST("/:id/refund", h.RefundPayment)
 		pay.GET("/all", h.FetchPayments)
+		pay.GET("/intents/:id", h.GetPaymentIntent)
+		pay.POST("/intents/:id/confirm", h.ConfirmPayment)
 	}
 
 	r := api.Group("/reviews")
@@ -85,6 +118,32 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 	{
 		n.POST("", h.CreateNotification)
 		n.GET("/:id", h.GetNotification)
+		n.PUT("/:id/read", h.MarkNotificationRead)
+		n.PUT("/read-all", h.MarkAllNotificationsRead)
+		n.DELETE("/:id", h.DeleteNotification)
+	}
+
+	w := api.Group("/webhooks")
+	{
+		w.POST("", h.CreateWebhook)
+		w.GET("", h.FetchMyWebhooks)
+		w.GET("/:id", h.GetWebhook)
+		w.PUT("/:id", h.UpdateWebhook)
+		w.DELETE("/:id", h.DeleteWebhook)
+		w.GET("/:id/deliveries", h.FetchWebhookDeliveries)
+		w.GET("/:id/deliveries/:delivery_id", h.GetWebhookDelivery)
+		w.POST("/:id/deliveries/:delivery_id/redeliver", h.RedeliverWebhook)
+	}
+
+	a := api.Group("/admin")
+	{
+		a.POST("/providers/import", h.ImportProviders)
+		a.GET("/providers/export", h.ExportProviders)
+		a.POST("/services/import", h.ImportServices)
+		a.GET("/services/export", h.ExportServices)
+		a.PUT("/providers/:id/commission", h.SetProviderCommission)
+		a.POST("/payouts", h.CreatePayoutBatch)
+		a.GET("/payouts/:id/export", h.ExportPayoutBatch)
 	}
 
 	return router
@@ -38,8 +38,19 @@ func CasbinEnforcer(cfg *config.Config) (*casbin.Enforcer, error) {
 
 	policies := [][]string{
 		{"admin", "/car-wash/*", "*", "allow"},
+
+		{"provider", "/car-wash/bookings/all", "GET", "deny"},
+		{"provider", "/car-wash/payments/all", "GET", "deny"},
+		{"provider", "/car-wash/reviews/all", "GET", "deny"},
+		{"provider", "/car-wash/admin/*", "*", "deny"},
+
 		{"provider", "/car-wash/*", "*", "allow"},
 
+		{"customer", "/car-wash/bookings/all", "GET", "deny"},
+		{"customer", "/car-wash/payments/all", "GET", "deny"},
+		{"customer", "/car-wash/reviews/all", "GET", "deny"},
+		{"customer", "/car-wash/admin/*", "*", "deny"},
+
 		{"customer", "/car-wash/providers", "POST", "deny"},
 		{"customer", "/car-wash/providers/*", "PUT", "deny"},
 		{"customer", "/car-wash/providers/*", "DELETE", "deny"},
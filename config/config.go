@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
@@ -24,8 +25,62 @@ type Config struct {
 	KAFKA_TOPIC_BOOKING_UPDATED      string
 	KAFKA_TOPIC_BOOKING_CANCELLED    string
 	KAFKA_TOPIC_PAYMENT_CREATED      string
+	KAFKA_TOPIC_PAYMENT_UPDATED      string
+	KAFKA_TOPIC_PAYMENT_REFUNDED     string
 	KAFKA_TOPIC_REVIEW_CREATED       string
 	KAFKA_TOPIC_NOTIFICATION_CREATED string
+	PRICING_PRICE_PER_KM             float64
+	PRICING_FREE_DISTANCE_KM         float64
+	PRICING_PEAK_HOURS               string
+	PRICING_PEAK_RATE                float64
+	PRICING_TAX_RATE                 float64
+	SCHEDULE_TIMEZONE                string
+	PAGINATION_DEFAULT_LIMIT         int32
+	PAGINATION_MAX_LIMIT             int32
+	LIST_SCAN_TTL                    time.Duration
+	LIST_SCAN_MAX_ITEMS              int
+	BATCH_MAX_REQUESTS               int
+	BATCH_MAX_CONCURRENCY            int
+	IMPORT_MAX_ROWS                  int
+	STREAM_CONSUMER_GROUP            string
+	STREAM_HEARTBEAT_INTERVAL        time.Duration
+	STREAM_BUFFER_SIZE               int
+	STREAM_HISTORY_SIZE              int
+	NOTIFIER_CONSUMER_GROUP          string
+	NOTIFICATION_TEMPLATES           string
+	NOTIFICATION_LOCALE              string
+	DELIVERY_CONSUMER_GROUP          string
+	DELIVERY_FAKE                    bool
+	SMTP_HOST                        string
+	SMTP_PORT                        int
+	SMTP_USERNAME                    string
+	SMTP_PASSWORD                    string
+	SMTP_FROM                        string
+	SMS_API_URL                      string
+	SMS_API_KEY                      string
+	SMS_SENDER                       string
+	FCM_API_URL                      string
+	FCM_SERVER_KEY                   string
+	REMINDER_CONSUMER_GROUP          string
+	REMINDER_OFFSETS                 string
+	REMINDER_POLL_INTERVAL           time.Duration
+	REMINDER_SCAN_INTERVAL           time.Duration
+	WEBHOOK_CONSUMER_GROUP           string
+	WEBHOOK_POLL_INTERVAL            time.Duration
+	WEBHOOK_TIMEOUT                  time.Duration
+	WEBHOOK_MAX_ATTEMPTS             int
+	WEBHOOK_RETRY_BACKOFF            time.Duration
+	PAYMENT_PROCESSOR                string
+	PAYMENT_CURRENCY                 string
+	STRIPE_API_URL                   string
+	STRIPE_SECRET_KEY                string
+	STRIPE_WEBHOOK_SECRET            string
+	FAKE_WEBHOOK_SECRET              string
+	PAYMENT_WEBHOOK_TOLERANCE        time.Duration
+	REFUND_POLICY                    string
+	OUTBOX_POLL_INTERVAL             time.Duration
+	LEDGER_CONSUMER_GROUP            string
+	COMMISSION_RATE                  float64
 }
 
 func Load() *Config {
@@ -55,9 +110,75 @@ func Load() *Config {
 	cfg.KAFKA_TOPIC_BOOKING_UPDATED = cast.ToString(coalesce("KAFKA_TOPIC_BOOKING_UPDATED", "car-wash.booking_updated"))
 	cfg.KAFKA_TOPIC_BOOKING_CANCELLED = cast.ToString(coalesce("KAFKA_TOPIC_BOOKING_CANCELLED", "car-wash.booking_cancelled"))
 	cfg.KAFKA_TOPIC_PAYMENT_CREATED = cast.ToString(coalesce("KAFKA_TOPIC_PAYMENT_CREATED", "car-wash.payment_created"))
+	cfg.KAFKA_TOPIC_PAYMENT_UPDATED = cast.ToString(coalesce("KAFKA_TOPIC_PAYMENT_UPDATED", "car-wash.payment_updated"))
+	cfg.KAFKA_TOPIC_PAYMENT_REFUNDED = cast.ToString(coalesce("KAFKA_TOPIC_PAYMENT_REFUNDED", "car-wash.payment_refunded"))
 	cfg.KAFKA_TOPIC_REVIEW_CREATED = cast.ToString(coalesce("KAFKA_TOPIC_REVIEW_CREATED", "car-wash.review_created"))
 	cfg.KAFKA_TOPIC_NOTIFICATION_CREATED = cast.ToString(coalesce("KAFKA_TOPIC_NOTIFICATION_CREATED", "car-wash.notification_created"))
 
+	cfg.PRICING_PRICE_PER_KM = cast.ToFloat64(coalesce("PRICING_PRICE_PER_KM", 0.5))
+	cfg.PRICING_FREE_DISTANCE_KM = cast.ToFloat64(coalesce("PRICING_FREE_DISTANCE_KM", 5))
+	cfg.PRICING_PEAK_HOURS = cast.ToString(coalesce("PRICING_PEAK_HOURS", "7-10,17-20"))
+	cfg.PRICING_PEAK_RATE = cast.ToFloat64(coalesce("PRICING_PEAK_RATE", 0.2))
+	cfg.PRICING_TAX_RATE = cast.ToFloat64(coalesce("PRICING_TAX_RATE", 0.12))
+
+	cfg.SCHEDULE_TIMEZONE = cast.ToString(coalesce("SCHEDULE_TIMEZONE", "UTC"))
+
+	cfg.PAGINATION_DEFAULT_LIMIT = cast.ToInt32(coalesce("PAGINATION_DEFAULT_LIMIT", 20))
+	cfg.PAGINATION_MAX_LIMIT = cast.ToInt32(coalesce("PAGINATION_MAX_LIMIT", 100))
+	cfg.LIST_SCAN_TTL = cast.ToDuration(coalesce("LIST_SCAN_TTL", "30s"))
+	cfg.LIST_SCAN_MAX_ITEMS = cast.ToInt(coalesce("LIST_SCAN_MAX_ITEMS", 10000))
+
+	cfg.BATCH_MAX_REQUESTS = cast.ToInt(coalesce("BATCH_MAX_REQUESTS", 500))
+	cfg.BATCH_MAX_CONCURRENCY = cast.ToInt(coalesce("BATCH_MAX_CONCURRENCY", 8))
+
+	cfg.IMPORT_MAX_ROWS = cast.ToInt(coalesce("IMPORT_MAX_ROWS", 5000))
+
+	cfg.STREAM_CONSUMER_GROUP = cast.ToString(coalesce("STREAM_CONSUMER_GROUP", "api-gateway-stream"))
+	cfg.STREAM_HEARTBEAT_INTERVAL = cast.ToDuration(coalesce("STREAM_HEARTBEAT_INTERVAL", "15s"))
+	cfg.STREAM_BUFFER_SIZE = cast.ToInt(coalesce("STREAM_BUFFER_SIZE", 64))
+	cfg.STREAM_HISTORY_SIZE = cast.ToInt(coalesce("STREAM_HISTORY_SIZE", 1000))
+
+	cfg.NOTIFIER_CONSUMER_GROUP = cast.ToString(coalesce("NOTIFIER_CONSUMER_GROUP", "api-gateway-notifier"))
+	cfg.NOTIFICATION_TEMPLATES = cast.ToString(coalesce("NOTIFICATION_TEMPLATES", ""))
+	cfg.NOTIFICATION_LOCALE = cast.ToString(coalesce("NOTIFICATION_LOCALE", "en"))
+
+	cfg.DELIVERY_CONSUMER_GROUP = cast.ToString(coalesce("DELIVERY_CONSUMER_GROUP", "api-gateway-delivery"))
+	cfg.DELIVERY_FAKE = cast.ToBool(coalesce("DELIVERY_FAKE", true))
+	cfg.SMTP_HOST = cast.ToString(coalesce("SMTP_HOST", "localhost"))
+	cfg.SMTP_PORT = cast.ToInt(coalesce("SMTP_PORT", 587))
+	cfg.SMTP_USERNAME = cast.ToString(coalesce("SMTP_USERNAME", ""))
+	cfg.SMTP_PASSWORD = cast.ToString(coalesce("SMTP_PASSWORD", ""))
+	cfg.SMTP_FROM = cast.ToString(coalesce("SMTP_FROM", "no-reply@car-wash.local"))
+	cfg.SMS_API_URL = cast.ToString(coalesce("SMS_API_URL", ""))
+	cfg.SMS_API_KEY = cast.ToString(coalesce("SMS_API_KEY", ""))
+	cfg.SMS_SENDER = cast.ToString(coalesce("SMS_SENDER", "CarWash"))
+	cfg.FCM_API_URL = cast.ToString(coalesce("FCM_API_URL", "https://fcm.googleapis.com/fcm/send"))
+	cfg.FCM_SERVER_KEY = cast.ToString(coalesce("FCM_SERVER_KEY", ""))
+
+	cfg.REMINDER_CONSUMER_GROUP = cast.ToString(coalesce("REMINDER_CONSUMER_GROUP", "api-gateway-reminders"))
+	cfg.REMINDER_OFFSETS = cast.ToString(coalesce("REMINDER_OFFSETS", "24h,1h"))
+	cfg.REMINDER_POLL_INTERVAL = cast.ToDuration(coalesce("REMINDER_POLL_INTERVAL", "1m"))
+	cfg.REMINDER_SCAN_INTERVAL = cast.ToDuration(coalesce("REMINDER_SCAN_INTERVAL", "10m"))
+
+	cfg.WEBHOOK_CONSUMER_GROUP = cast.ToString(coalesce("WEBHOOK_CONSUMER_GROUP", "api-gateway-webhooks"))
+	cfg.WEBHOOK_POLL_INTERVAL = cast.ToDuration(coalesce("WEBHOOK_POLL_INTERVAL", "5s"))
+	cfg.WEBHOOK_TIMEOUT = cast.ToDuration(coalesce("WEBHOOK_TIMEOUT", "10s"))
+	cfg.WEBHOOK_MAX_ATTEMPTS = cast.ToInt(coalesce("WEBHOOK_MAX_ATTEMPTS", 8))
+	cfg.WEBHOOK_RETRY_BACKOFF = cast.ToDuration(coalesce("WEBHOOK_RETRY_BACKOFF", "30s"))
+
+	cfg.PAYMENT_PROCESSOR = cast.ToString(coalesce("PAYMENT_PROCESSOR", ""))
+	cfg.PAYMENT_CURRENCY = cast.ToString(coalesce("PAYMENT_CURRENCY", "usd"))
+	cfg.STRIPE_API_URL = cast.ToString(coalesce("STRIPE_API_URL", "https://api.stripe.com"))
+	cfg.STRIPE_SECRET_KEY = cast.ToString(coalesce("STRIPE_SECRET_KEY", ""))
+	cfg.STRIPE_WEBHOOK_SECRET = cast.ToString(coalesce("STRIPE_WEBHOOK_SECRET", ""))
+	cfg.FAKE_WEBHOOK_SECRET = cast.ToString(coalesce("FAKE_WEBHOOK_SECRET", ""))
+	cfg.PAYMENT_WEBHOOK_TOLERANCE = cast.ToDuration(coalesce("PAYMENT_WEBHOOK_TOLERANCE", "5m"))
+	cfg.REFUND_POLICY = cast.ToString(coalesce("REFUND_POLICY", "24h:100,0s:50"))
+	cfg.OUTBOX_POLL_INTERVAL = cast.ToDuration(coalesce("OUTBOX_POLL_INTERVAL", "5s"))
+
+	cfg.LEDGER_CONSUMER_GROUP = cast.ToString(coalesce("LEDGER_CONSUMER_GROUP", "api-gateway-ledger"))
+	cfg.COMMISSION_RATE = cast.ToFloat64(coalesce("COMMISSION_RATE", 0.15))
+
 	return cfg
 }
 
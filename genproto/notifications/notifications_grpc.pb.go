@@ -24,6 +24,11 @@ const _ = grpc.SupportPackageIsVersion7
 type NotificationsClient interface {
 	CreateNotification(ctx context.Context, in *NewNotification, opts ...grpc.CallOption) (*ID, error)
 	GetNotification(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Notification, error)
+	ListNotifications(ctx context.Context, in *Filter, opts ...grpc.CallOption) (*NotificationsList, error)
+	CountUnreadNotifications(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UnreadCount, error)
+	MarkNotificationRead(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Void, error)
+	MarkAllNotificationsRead(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*Void, error)
+	DeleteNotification(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Void, error)
 }
 
 type notificationsClient struct {
@@ -52,12 +57,62 @@ func (c *notificationsClient) GetNotification(ctx context.Context, in *ID, opts
 	return out, nil
 }
 
+func (c *notificationsClient) ListNotifications(ctx context.Context, in *Filter, opts ...grpc.CallOption) (*NotificationsList, error) {
+	out := new(NotificationsList)
+	err := c.cc.Invoke(ctx, "/notifications.Notifications/ListNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationsClient) CountUnreadNotifications(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UnreadCount, error) {
+	out := new(UnreadCount)
+	err := c.cc.Invoke(ctx, "/notifications.Notifications/CountUnreadNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationsClient) MarkNotificationRead(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/notifications.Notifications/MarkNotificationRead", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationsClient) MarkAllNotificationsRead(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/notifications.Notifications/MarkAllNotificationsRead", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationsClient) DeleteNotification(ctx context.Context, in *ID, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, "/notifications.Notifications/DeleteNotification", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NotificationsServer is the server API for Notifications service.
 // All implementations must embed UnimplementedNotificationsServer
 // for forward compatibility
 type NotificationsServer interface {
 	CreateNotification(context.Context, *NewNotification) (*ID, error)
 	GetNotification(context.Context, *ID) (*Notification, error)
+	ListNotifications(context.Context, *Filter) (*NotificationsList, error)
+	CountUnreadNotifications(context.Context, *UserID) (*UnreadCount, error)
+	MarkNotificationRead(context.Context, *ID) (*Void, error)
+	MarkAllNotificationsRead(context.Context, *UserID) (*Void, error)
+	DeleteNotification(context.Context, *ID) (*Void, error)
 	mustEmbedUnimplementedNotificationsServer()
 }
 
@@ -71,6 +126,21 @@ func (UnimplementedNotificationsServer) CreateNotification(context.Context, *New
 func (UnimplementedNotificationsServer) GetNotification(context.Context, *ID) (*Notification, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNotification not implemented")
 }
+func (UnimplementedNotificationsServer) ListNotifications(context.Context, *Filter) (*NotificationsList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNotifications not implemented")
+}
+func (UnimplementedNotificationsServer) CountUnreadNotifications(context.Context, *UserID) (*UnreadCount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountUnreadNotifications not implemented")
+}
+func (UnimplementedNotificationsServer) MarkNotificationRead(context.Context, *ID) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkNotificationRead not implemented")
+}
+func (UnimplementedNotificationsServer) MarkAllNotificationsRead(context.Context, *UserID) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkAllNotificationsRead not implemented")
+}
+func (UnimplementedNotificationsServer) DeleteNotification(context.Context, *ID) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNotification not implemented")
+}
 func (UnimplementedNotificationsServer) mustEmbedUnimplementedNotificationsServer() {}
 
 // UnsafeNotificationsServer may be embedded to opt out of forward compatibility for this service.
@@ -120,6 +190,96 @@ func _Notifications_GetNotification_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Notifications_ListNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Filter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationsServer).ListNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notifications.Notifications/ListNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationsServer).ListNotifications(ctx, req.(*Filter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Notifications_CountUnreadNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationsServer).CountUnreadNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notifications.Notifications/CountUnreadNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationsServer).CountUnreadNotifications(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Notifications_MarkNotificationRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationsServer).MarkNotificationRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notifications.Notifications/MarkNotificationRead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationsServer).MarkNotificationRead(ctx, req.(*ID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Notifications_MarkAllNotificationsRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationsServer).MarkAllNotificationsRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notifications.Notifications/MarkAllNotificationsRead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationsServer).MarkAllNotificationsRead(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Notifications_DeleteNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationsServer).DeleteNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notifications.Notifications/DeleteNotification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationsServer).DeleteNotification(ctx, req.(*ID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Notifications_ServiceDesc is the grpc.ServiceDesc for Notifications service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -135,6 +295,26 @@ var Notifications_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNotification",
 			Handler:    _Notifications_GetNotification_Handler,
 		},
+		{
+			MethodName: "ListNotifications",
+			Handler:    _Notifications_ListNotifications_Handler,
+		},
+		{
+			MethodName: "CountUnreadNotifications",
+			Handler:    _Notifications_CountUnreadNotifications_Handler,
+		},
+		{
+			MethodName: "MarkNotificationRead",
+			Handler:    _Notifications_MarkNotificationRead_Handler,
+		},
+		{
+			MethodName: "MarkAllNotificationsRead",
+			Handler:    _Notifications_MarkAllNotificationsRead_Handler,
+		},
+		{
+			MethodName: "DeleteNotification",
+			Handler:    _Notifications_DeleteNotification_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "notifications.proto",
@@ -0,0 +1,46 @@
+package consumer
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type IKafkaConsumer interface {
+	Consume(ctx context.Context, handle func(topic string, msg []byte)) error
+	Close()
+}
+
+type KafkaConsumer struct {
+	reader *kafka.Reader
+}
+
+// NewKafkaConsumer reads the given topics as a member of groupID. New
+// groups start from the latest messages rather than replaying the topics.
+func NewKafkaConsumer(brokers []string, groupID string, topics []string) IKafkaConsumer {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		GroupID:     groupID,
+		GroupTopics: topics,
+		StartOffset: kafka.LastOffset,
+	})
+
+	return &KafkaConsumer{reader: r}
+}
+
+// Consume passes every message to handle until ctx is done or the reader
+// fails.
+func (k *KafkaConsumer) Consume(ctx context.Context, handle func(topic string, msg []byte)) error {
+	for {
+		m, err := k.reader.ReadMessage(ctx)
+		if err != nil {
+			return err
+		}
+
+		handle(m.Topic, m.Value)
+	}
+}
+
+func (k *KafkaConsumer) Close() {
+	k.reader.Close()
+}
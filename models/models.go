@@ -1,69 +1,382 @@
 package models
 
+import (
+	"api-gateway/genproto/bookings"
+	"api-gateway/genproto/payments"
+	"api-gateway/genproto/providers"
+	"api-gateway/genproto/reviews"
+	"api-gateway/genproto/services"
+	"api-gateway/pkg/pricing"
+	"encoding/json"
+)
+
 type UserUpdate struct {
-	Email       string `json:"email" validate:"required"`
+	Email       string `json:"email" validate:"required,email"`
 	FirstName   string `json:"first_name" validate:"required"`
 	LastName    string `json:"last_name" validate:"required"`
-	PhoneNumber string `json:"phone_number" validate:"required"`
+	PhoneNumber string `json:"phone_number" validate:"required,e164"`
 }
 
 type Location struct {
 	Address   string  `json:"address" validate:"required"`
 	City      string  `json:"city" validate:"required"`
 	Country   string  `json:"country" validate:"required"`
-	Latitude  float32 `json:"latitude" validate:"required"`
-	Longitude float32 `json:"longitude" validate:"required"`
+	Latitude  float32 `json:"latitude" validate:"latitude"`
+	Longitude float32 `json:"longitude" validate:"longitude"`
 }
 
 type ProviderCreate struct {
 	CompanyName   string   `json:"company_name" validate:"required"`
 	Description   string   `json:"description" validate:"required"`
-	Services      []string `json:"services" validate:"required"`
-	Availability  []string `json:"availability" validate:"required"`
-	AverageRating float32  `json:"average_rating" validate:"required"`
+	Services      []string `json:"services" validate:"required,dive,uuid"`
+	Availability  []string `json:"availability" validate:"required,dive,availability"`
+	AverageRating float32  `json:"average_rating" validate:"gte=0,lte=5"`
 	Location      Location `json:"location" validate:"required"`
 }
 
+type ProviderDistance struct {
+	*providers.Provider
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+}
+
+type ProviderSearchResp struct {
+	Providers []ProviderDistance `json:"providers"`
+}
+
+type ServiceCreate struct {
+	Name        string  `json:"name" validate:"required"`
+	Description string  `json:"description" validate:"required"`
+	Price       float32 `json:"price" validate:"required,gt=0"`
+	Duration    int32   `json:"duration" validate:"required,gt=0"`
+}
+
 type ServiceUpdate struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
-	Price       float32 `json:"price"`
-	Duration    int32   `json:"duration"`
+	Price       float32 `json:"price" validate:"omitempty,gt=0"`
+	Duration    int32   `json:"duration" validate:"omitempty,gt=0"`
 }
 
 type BookingCreate struct {
-	ProviderID    string   `json:"provider_id" validate:"required"`
-	ServiceID     string   `json:"service_id" validate:"required"`
-	Status        string   `json:"status" validate:"required"`
-	ScheduledTime string   `json:"scheduled_time" validate:"required"`
+	ProviderID    string   `json:"provider_id" validate:"required,uuid"`
+	ServiceID     string   `json:"service_id" validate:"required,uuid"`
+	ScheduledTime string   `json:"scheduled_time" validate:"required,rfc3339,future"`
 	Location      Location `json:"location" validate:"required"`
-	TotalPrice    float32  `json:"total_price" validate:"required"`
+	TotalPrice    float32  `json:"total_price" validate:"omitempty,gt=0"`
+}
+
+type BookingResp struct {
+	Message string        `json:"message"`
+	Price   pricing.Quote `json:"price"`
+}
+
+type Slot struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type SlotsResp struct {
+	ProviderID string `json:"provider_id"`
+	ServiceID  string `json:"service_id"`
+	Date       string `json:"date"`
+	Slots      []Slot `json:"slots"`
 }
 
 type BookingUpdate struct {
-	Status        string   `json:"status"`
-	ScheduledTime string   `json:"scheduled_time"`
-	Location      Location `json:"location"`
-	TotalPrice    float32  `json:"total_price"`
+	ScheduledTime string    `json:"scheduled_time" validate:"omitempty,rfc3339,future"`
+	Location      *Location `json:"location" validate:"omitempty"`
+}
+
+type PaymentCreate struct {
+	BookingID string `json:"booking_id" validate:"required,uuid"`
+}
+
+// PaymentConfirm carries the payment method as tokenized by the
+// processor's client library, e.g. pm_card_visa. Card details never
+// reach the gateway.
+type PaymentConfirm struct {
+	PaymentMethod string `json:"payment_method" validate:"required"`
+}
+
+// PaymentIntent is a payment of a booking being collected through the
+// processor. Its amount is the booking's price and its status is only
+// changed by the processor.
+type PaymentIntent struct {
+	ID                string  `json:"id"`
+	BookingID         string  `json:"booking_id"`
+	UserID            string  `json:"user_id"`
+	Processor         string  `json:"processor"`
+	ProcessorIntentID string  `json:"processor_intent_id"`
+	Amount            float32 `json:"amount"`
+	AmountRefunded    float32 `json:"amount_refunded"`
+	Currency          string  `json:"currency"`
+	Status            string  `json:"status"`
+	PaymentMethod     string  `json:"payment_method,omitempty"`
+	FailureMessage    string  `json:"failure_message,omitempty"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
+
+// RefundCreate asks for a refund of a payment. Without an amount,
+// everything that can still be refunded is.
+type RefundCreate struct {
+	Amount float32 `json:"amount" validate:"omitempty,gt=0"`
+	Reason string  `json:"reason" validate:"required,max=500"`
+}
+
+// Refund gives back part or all of a payment intent.
+type Refund struct {
+	ID                string  `json:"id"`
+	PaymentIntentID   string  `json:"payment_intent_id"`
+	BookingID         string  `json:"booking_id"`
+	ProcessorRefundID string  `json:"processor_refund_id,omitempty"`
+	Amount            float32 `json:"amount"`
+	Currency          string  `json:"currency"`
+	Reason            string  `json:"reason"`
+	Status            string  `json:"status"`
+	RequestedBy       string  `json:"requested_by"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
 }
 
 type ReviewCreate struct {
-	BookingID  string `json:"booking_id" validate:"required"`
-	ProviderID string `json:"provider_id" validate:"required"`
-	Rating     int32  `json:"rating" validate:"required"`
+	BookingID  string `json:"booking_id" validate:"required,uuid"`
+	ProviderID string `json:"provider_id" validate:"required,uuid"`
+	Rating     int32  `json:"rating" validate:"required,min=1,max=5"`
 	Comment    string `json:"comment" validate:"required"`
 }
 
 type ReviewUpdate struct {
-	Rating  int32  `json:"rating"`
+	Rating  int32  `json:"rating" validate:"omitempty,min=1,max=5"`
 	Comment string `json:"comment"`
 }
 
+type NotificationCreate struct {
+	UserID  string `json:"user_id" validate:"required,uuid"`
+	Title   string `json:"title" validate:"required"`
+	Message string `json:"message" validate:"required"`
+	Type    string `json:"type"`
+}
+
+// NotificationPreferences choose the channels notifications are delivered
+// through besides the inbox, by notification type. The "default" entry
+// applies to the types that are not listed.
+type NotificationPreferences struct {
+	Locale     string              `json:"locale" validate:"omitempty,min=2,max=10"`
+	Channels   map[string][]string `json:"channels" validate:"dive,keys,required,endkeys,dive,oneof=email sms push webhook"`
+	PushTokens []string            `json:"push_tokens" validate:"max=10,dive,required"`
+	WebhookURL string              `json:"webhook_url" validate:"omitempty,url,startswith=https://,publicurl"`
+	UpdatedAt  string              `json:"updated_at,omitempty"`
+}
+
+type DeliveryLog struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Type      string `json:"type"`
+	Channel   string `json:"channel"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type WebhookCreate struct {
+	URL    string   `json:"url" validate:"required,url,startswith=https://,publicurl"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=booking_created booking_updated booking_cancelled payment_created payment_updated payment_refunded review_created"`
+}
+
+type WebhookUpdate struct {
+	URL    string   `json:"url" validate:"omitempty,url,startswith=https://,publicurl"`
+	Events []string `json:"events" validate:"omitempty,min=1,dive,oneof=booking_created booking_updated booking_cancelled payment_created payment_updated payment_refunded review_created"`
+	Active *bool    `json:"active"`
+}
+
+// Webhook is a subscription to events. The secret deliveries are signed
+// with is only shown when the subscription is created.
+type Webhook struct {
+	ID        string   `json:"id"`
+	UserID    string   `json:"user_id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// WebhookDelivery is an event sent, or to be sent, to a subscription.
+// Deliveries whose attempts ran out are dead and stay so until they are
+// redelivered.
+type WebhookDelivery struct {
+	ID             string           `json:"id"`
+	WebhookID      string           `json:"webhook_id"`
+	Event          string           `json:"event"`
+	Payload        json.RawMessage  `json:"payload" swaggertype:"object"`
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	LastError      string           `json:"last_error,omitempty"`
+	NextAttemptAt  string           `json:"next_attempt_at,omitempty"`
+	RedeliveryOf   string           `json:"redelivery_of,omitempty"`
+	CreatedAt      string           `json:"created_at"`
+	DeliveredAt    string           `json:"delivered_at,omitempty"`
+	AttemptHistory []WebhookAttempt `json:"attempt_history,omitempty"`
+}
+
+type WebhookAttempt struct {
+	ResponseStatus int    `json:"response_status,omitempty"`
+	Error          string `json:"error,omitempty"`
+	DurationMs     int64  `json:"duration_ms"`
+	CreatedAt      string `json:"created_at"`
+}
+
 type ProviderUpdate struct {
-	CompanyName   string   `json:"company_name"`
-	Description   string   `json:"description"`
-	Services      []string `json:"services"`
-	Availability  []string `json:"availability"`
-	AverageRating float32  `json:"average_rating"`
-	Location      Location `json:"location"`
+	CompanyName   string    `json:"company_name"`
+	Description   string    `json:"description"`
+	Services      []string  `json:"services" validate:"omitempty,dive,uuid"`
+	Availability  []string  `json:"availability" validate:"omitempty,dive,availability"`
+	AverageRating float32   `json:"average_rating" validate:"omitempty,gte=0,lte=5"`
+	Location      *Location `json:"location" validate:"omitempty"`
+}
+
+type PageInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+type ListResp struct {
+	Data     interface{} `json:"data"`
+	PageInfo PageInfo    `json:"page_info"`
+}
+
+type NotificationListResp struct {
+	ListResp
+	UnreadCount int32 `json:"unread_count"`
+}
+
+// BookingExpanded is a booking with the related resources requested
+// through ?expand= inlined.
+type BookingExpanded struct {
+	*bookings.Booking
+	Provider *providers.Provider `json:"provider,omitempty"`
+	Service  *services.Service   `json:"service,omitempty"`
+	Payments []*payments.Payment `json:"payments,omitempty"`
+}
+
+// ReviewExpanded is a review with the related resources requested
+// through ?expand= inlined.
+type ReviewExpanded struct {
+	*reviews.Review
+	Booking  *bookings.Booking   `json:"booking,omitempty"`
+	Provider *providers.Provider `json:"provider,omitempty"`
+}
+
+// BookingDetails composes a booking with everything related to it. Parts
+// that could not be loaded are left empty and named in Unavailable.
+type BookingDetails struct {
+	Booking     *bookings.Booking   `json:"booking"`
+	Service     *services.Service   `json:"service"`
+	Provider    *providers.Provider `json:"provider"`
+	Payments    []*payments.Payment `json:"payments"`
+	Review      *reviews.Review     `json:"review"`
+	Unavailable []string            `json:"unavailable,omitempty"`
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type BatchRequest struct {
+	Requests    []BatchItem `json:"requests" validate:"required,min=1,dive"`
+	Concurrency int         `json:"concurrency" validate:"omitempty,min=1"`
+	StopOnError bool        `json:"stop_on_error"`
+}
+
+type BatchItem struct {
+	Method string          `json:"method" validate:"required,oneof=GET POST PUT DELETE"`
+	Path   string          `json:"path" validate:"required,startswith=/"`
+	Body   json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+}
+
+type BatchResult struct {
+	Status  int             `json:"status,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+	Skipped bool            `json:"skipped,omitempty"`
+}
+
+type BatchResp struct {
+	Results []BatchResult `json:"results"`
+}
+
+type ImportReport struct {
+	Total   int         `json:"total"`
+	Created int         `json:"created"`
+	Valid   int         `json:"valid"`
+	Failed  int         `json:"failed"`
+	DryRun  bool        `json:"dry_run"`
+	Rows    []ImportRow `json:"rows"`
+}
+
+type ImportRow struct {
+	Line   int               `json:"line"`
+	Status string            `json:"status"`
+	ID     string            `json:"id,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// CommissionUpdate sets the share of a provider's payments the platform
+// keeps, between 0 and 1.
+type CommissionUpdate struct {
+	Rate *float64 `json:"rate" validate:"required,gte=0,lte=1"`
+}
+
+// Commission is the rate the platform keeps of a provider's payments.
+type Commission struct {
+	ProviderID string  `json:"provider_id"`
+	Rate       float64 `json:"rate"`
+}
+
+// EarningsPeriod sums up a provider's ledger over a period. Net is what
+// the provider earned: the payments less refunds and commission.
+type EarningsPeriod struct {
+	Start      string  `json:"start"`
+	Gross      float32 `json:"gross"`
+	Refunded   float32 `json:"refunded"`
+	Commission float32 `json:"commission"`
+	Net        float32 `json:"net"`
+	PaidOut    float32 `json:"paid_out"`
+}
+
+// Earnings are a provider's earnings between From and To, by period.
+// Balance is what the provider is owed now, whatever the range.
+type Earnings struct {
+	ProviderID     string           `json:"provider_id"`
+	Currency       string           `json:"currency"`
+	CommissionRate float64          `json:"commission_rate"`
+	Period         string           `json:"period"`
+	From           string           `json:"from"`
+	To             string           `json:"to"`
+	Periods        []EarningsPeriod `json:"periods"`
+	Total          EarningsPeriod   `json:"total"`
+	Balance        float32          `json:"balance"`
+}
+
+// Payout pays a provider their balance as part of a batch.
+type Payout struct {
+	TransactionID string  `json:"transaction_id"`
+	ProviderID    string  `json:"provider_id"`
+	Amount        float32 `json:"amount"`
+	Currency      string  `json:"currency"`
+}
+
+// PayoutBatch pays every provider who is owed money at once.
+type PayoutBatch struct {
+	ID        string    `json:"id"`
+	Currency  string    `json:"currency"`
+	Total     float32   `json:"total"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt string    `json:"created_at"`
+	Payouts   []*Payout `json:"payouts"`
 }
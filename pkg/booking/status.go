@@ -0,0 +1,63 @@
+package booking
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	StatusPending    = "pending"
+	StatusConfirmed  = "confirmed"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusCancelled  = "cancelled"
+)
+
+const (
+	RoleAdmin    = "admin"
+	RoleProvider = "provider"
+	RoleCustomer = "customer"
+)
+
+var (
+	ErrInvalidTransition   = errors.New("invalid status transition")
+	ErrForbiddenTransition = errors.New("status transition not allowed for role")
+)
+
+type transition struct {
+	from, to string
+}
+
+// transitions lists every allowed step of the booking lifecycle
+// together with the roles that may perform it.
+var transitions = map[transition][]string{
+	{StatusPending, StatusConfirmed}:    {RoleAdmin, RoleProvider},
+	{StatusConfirmed, StatusInProgress}: {RoleAdmin, RoleProvider},
+	{StatusInProgress, StatusCompleted}: {RoleAdmin, RoleProvider},
+	{StatusPending, StatusCancelled}:    {RoleAdmin, RoleProvider, RoleCustomer},
+	{StatusConfirmed, StatusCancelled}:  {RoleAdmin, RoleProvider, RoleCustomer},
+}
+
+// CheckTransition reports whether a user with the given role may move
+// a booking from one status to another.
+func CheckTransition(from, to, role string) error {
+	roles, ok := transitions[transition{from, to}]
+	if !ok {
+		return errors.Wrap(ErrInvalidTransition, fmt.Sprintf("%s -> %s", from, to))
+	}
+
+	for _, r := range roles {
+		if r == role {
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrForbiddenTransition, fmt.Sprintf("%s cannot move %s -> %s", role, from, to))
+}
+
+// IsModifiable reports whether booking details such as the schedule
+// or location may still be changed in the given status.
+func IsModifiable(status string) bool {
+	return status == StatusPending || status == StatusConfirmed
+}
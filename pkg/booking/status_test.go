@@ -0,0 +1,61 @@
+package booking
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		role     string
+		want     error
+	}{
+		{"provider confirms", StatusPending, StatusConfirmed, RoleProvider, nil},
+		{"admin starts", StatusConfirmed, StatusInProgress, RoleAdmin, nil},
+		{"provider completes", StatusInProgress, StatusCompleted, RoleProvider, nil},
+		{"customer cancels pending", StatusPending, StatusCancelled, RoleCustomer, nil},
+		{"customer cancels confirmed", StatusConfirmed, StatusCancelled, RoleCustomer, nil},
+		{"customer confirms", StatusPending, StatusConfirmed, RoleCustomer, ErrForbiddenTransition},
+		{"customer completes", StatusInProgress, StatusCompleted, RoleCustomer, ErrForbiddenTransition},
+		{"unknown role", StatusPending, StatusCancelled, "guest", ErrForbiddenTransition},
+		{"cancel in progress", StatusInProgress, StatusCancelled, RoleAdmin, ErrInvalidTransition},
+		{"skip confirmation", StatusPending, StatusInProgress, RoleAdmin, ErrInvalidTransition},
+		{"reopen completed", StatusCompleted, StatusPending, RoleAdmin, ErrInvalidTransition},
+		{"same status", StatusPending, StatusPending, RoleAdmin, ErrInvalidTransition},
+		{"unknown status", "archived", StatusCancelled, RoleAdmin, ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTransition(tt.from, tt.to, tt.role)
+			if tt.want == nil && err != nil {
+				t.Fatalf("CheckTransition(%q, %q, %q) = %v, want nil", tt.from, tt.to, tt.role, err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("CheckTransition(%q, %q, %q) = %v, want %v", tt.from, tt.to, tt.role, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsModifiable(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{StatusPending, true},
+		{StatusConfirmed, true},
+		{StatusInProgress, false},
+		{StatusCompleted, false},
+		{StatusCancelled, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsModifiable(tt.status); got != tt.want {
+			t.Errorf("IsModifiable(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package delivery
+
+import (
+	"api-gateway/config"
+	"api-gateway/pkg/outbound"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Channels a notification can be delivered through, besides the in-app
+// inbox that always gets it.
+const (
+	Email   = "email"
+	SMS     = "sms"
+	Push    = "push"
+	Webhook = "webhook"
+)
+
+// ErrNoAddress is returned by a channel when the recipient has no
+// address on it, e.g. no phone number for SMS.
+var ErrNoAddress = errors.New("recipient has no address on this channel")
+
+// Recipient holds the addresses of a user on every channel.
+type Recipient struct {
+	UserID     string
+	Email      string
+	Phone      string
+	PushTokens []string
+	WebhookURL string
+}
+
+// Message is a notification as delivered.
+type Message struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// Channel delivers messages through one medium.
+type Channel interface {
+	Send(ctx context.Context, to Recipient, msg Message) error
+}
+
+// NewChannels sets up a channel per medium. With DELIVERY_FAKE set, every
+// channel is a fake that logs what it would send.
+func NewChannels(cfg *config.Config, logger *slog.Logger) map[string]Channel {
+	if cfg.DELIVERY_FAKE {
+		return map[string]Channel{
+			Email:   NewFake(Email, logger),
+			SMS:     NewFake(SMS, logger),
+			Push:    NewFake(Push, logger),
+			Webhook: NewFake(Webhook, logger),
+		}
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	return map[string]Channel{
+		Email: &SMTP{
+			Host:     cfg.SMTP_HOST,
+			Port:     cfg.SMTP_PORT,
+			Username: cfg.SMTP_USERNAME,
+			Password: cfg.SMTP_PASSWORD,
+			From:     cfg.SMTP_FROM,
+		},
+		SMS: &SMSGateway{
+			URL:    cfg.SMS_API_URL,
+			APIKey: cfg.SMS_API_KEY,
+			Sender: cfg.SMS_SENDER,
+			Client: client,
+		},
+		Push: &FCM{
+			URL:       cfg.FCM_API_URL,
+			ServerKey: cfg.FCM_SERVER_KEY,
+			Client:    client,
+		},
+		Webhook: &UserWebhook{Client: outbound.NewClient(10 * time.Second)},
+	}
+}
@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+// SMTP sends messages as plain text emails.
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTP) Send(ctx context.Context, to Recipient, msg Message) error {
+	if to.Email == "" {
+		return ErrNoAddress
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	body := strings.Join([]string{
+		"From: " + s.From,
+		"To: " + to.Email,
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Title),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		msg.Message,
+	}, "\r\n")
+
+	addr := fmt.Sprintf("%s:%d", s.Host, s.Port)
+
+	// net/smtp takes no context, so the send runs until the server
+	// answers or drops the connection.
+	return smtp.SendMail(addr, auth, s.From, []string{to.Email}, []byte(body))
+}
@@ -0,0 +1,52 @@
+package delivery
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Fake stands in for a channel in local setups and tests. It logs and
+// keeps what it is given instead of sending it.
+type Fake struct {
+	Name string
+	// Err, when set, is returned by every send, to exercise failures.
+	Err error
+
+	logger *slog.Logger
+	mu     sync.Mutex
+	sent   []Sent
+}
+
+// Sent is a message given to a fake channel.
+type Sent struct {
+	To  Recipient
+	Msg Message
+}
+
+func NewFake(name string, logger *slog.Logger) *Fake {
+	return &Fake{Name: name, logger: logger}
+}
+
+func (f *Fake) Send(ctx context.Context, to Recipient, msg Message) error {
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.mu.Lock()
+	f.sent = append(f.sent, Sent{To: to, Msg: msg})
+	f.mu.Unlock()
+
+	if f.logger != nil {
+		f.logger.Info("fake "+f.Name+" delivery", "user_id", to.UserID, "type", msg.Type, "title", msg.Title)
+	}
+	return nil
+}
+
+// Sent returns the messages given to the channel so far.
+func (f *Fake) Sent() []Sent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Sent(nil), f.sent...)
+}
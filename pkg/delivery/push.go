@@ -0,0 +1,34 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+)
+
+// FCM sends messages as push notifications through Firebase Cloud
+// Messaging, which also forwards them to APNs for iOS devices.
+type FCM struct {
+	URL       string
+	ServerKey string
+	Client    *http.Client
+}
+
+func (f *FCM) Send(ctx context.Context, to Recipient, msg Message) error {
+	if len(to.PushTokens) == 0 {
+		return ErrNoAddress
+	}
+
+	body := map[string]any{
+		"registration_ids": to.PushTokens,
+		"notification": map[string]string{
+			"title": msg.Title,
+			"body":  msg.Message,
+		},
+		"data": map[string]string{
+			"id":   msg.ID,
+			"type": msg.Type,
+		},
+	}
+
+	return postJSON(ctx, f.Client, f.URL, body, map[string]string{"Authorization": "key=" + f.ServerKey})
+}
@@ -0,0 +1,30 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+)
+
+// SMSGateway sends messages as text messages through an HTTP SMS
+// gateway that takes a JSON body with the sender, the phone number and
+// the text, authorised by a bearer API key.
+type SMSGateway struct {
+	URL    string
+	APIKey string
+	Sender string
+	Client *http.Client
+}
+
+func (s *SMSGateway) Send(ctx context.Context, to Recipient, msg Message) error {
+	if to.Phone == "" {
+		return ErrNoAddress
+	}
+
+	body := map[string]string{
+		"from": s.Sender,
+		"to":   to.Phone,
+		"text": msg.Title + ": " + msg.Message,
+	}
+
+	return postJSON(ctx, s.Client, s.URL, body, map[string]string{"Authorization": "Bearer " + s.APIKey})
+}
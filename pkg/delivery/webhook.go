@@ -0,0 +1,56 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// UserWebhook posts messages as JSON to the webhook URL the user set. Its
+// client should only reach public addresses, see outbound.NewClient.
+type UserWebhook struct {
+	Client *http.Client
+}
+
+func (w *UserWebhook) Send(ctx context.Context, to Recipient, msg Message) error {
+	if to.WebhookURL == "" {
+		return ErrNoAddress
+	}
+
+	return postJSON(ctx, w.Client, to.WebhookURL, msg, nil)
+}
+
+// postJSON posts body as JSON and fails unless the response is a 2xx.
+func postJSON(ctx context.Context, client *http.Client, url string, body any, headers map[string]string) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, "error serializing message")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "invalid request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The body is left unread: it is whatever the receiver chose to
+	// answer and has no place in the delivery log.
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package invoice
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+)
+
+//go:embed invoice.html
+var htmlSource string
+
+var htmlTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"percent": percent,
+	"upper":   strings.ToUpper,
+}).Parse(htmlSource))
+
+// WriteHTML renders the invoice as a standalone HTML page.
+func (inv *Invoice) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, inv)
+}
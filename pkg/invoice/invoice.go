@@ -0,0 +1,92 @@
+package invoice
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats an invoice can be rendered in.
+const (
+	FormatJSON = "json"
+	FormatHTML = "html"
+	FormatPDF  = "pdf"
+)
+
+// Party is the seller or the buyer on an invoice.
+type Party struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+}
+
+// Line is a charge before taxes.
+type Line struct {
+	Description string  `json:"description"`
+	Amount      float32 `json:"amount"`
+}
+
+// TaxLine is a tax charged on the subtotal. Rate is a fraction, e.g. 0.12.
+type TaxLine struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float32 `json:"amount"`
+}
+
+// Payment is a payment made towards the invoice.
+type Payment struct {
+	TransactionID string  `json:"transaction_id"`
+	Method        string  `json:"method"`
+	Status        string  `json:"status"`
+	Amount        float32 `json:"amount"`
+	Date          string  `json:"date"`
+}
+
+// Invoice is the receipt of a completed booking. Its number is sequential
+// per provider and does not change once issued.
+type Invoice struct {
+	Number         string    `json:"number"`
+	IssuedAt       time.Time `json:"issued_at"`
+	BookingID      string    `json:"booking_id"`
+	Service        string    `json:"service"`
+	ScheduledTime  string    `json:"scheduled_time"`
+	Location       string    `json:"location"`
+	Currency       string    `json:"currency"`
+	Seller         Party     `json:"seller"`
+	Customer       Party     `json:"customer"`
+	Lines          []Line    `json:"lines"`
+	Subtotal       float32   `json:"subtotal"`
+	Taxes          []TaxLine `json:"taxes"`
+	Total          float32   `json:"total"`
+	Payments       []Payment `json:"payments"`
+	AmountPaid     float32   `json:"amount_paid"`
+	AmountRefunded float32   `json:"amount_refunded"`
+	AmountDue      float32   `json:"amount_due"`
+}
+
+// Number formats the sequence number of a provider's invoice, prefixed
+// with the start of the provider ID so that numbers of different
+// providers do not collide, e.g. 3F2A91C0-000042.
+func Number(providerID string, seq int64) string {
+	prefix := strings.ToUpper(strings.ReplaceAll(providerID, "-", ""))
+	if len(prefix) > 8 {
+		prefix = prefix[:8]
+	}
+	return fmt.Sprintf("%s-%06d", prefix, seq)
+}
+
+// Filename is the name an invoice is downloaded under.
+func (inv *Invoice) Filename(format string) string {
+	return "invoice-" + inv.Number + "." + format
+}
+
+// money formats an amount with the currency, e.g. 12.50 USD.
+func (inv *Invoice) money(amount float32) string {
+	return fmt.Sprintf("%.2f %s", amount, strings.ToUpper(inv.Currency))
+}
+
+// percent formats a tax rate, e.g. 12%.
+func percent(rate float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", rate*100), "0"), ".") + "%"
+}
@@ -0,0 +1,185 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The PDF is laid out as monospaced text on A4 pages, which needs no font
+// metrics: Courier glyphs are 0.6 em wide.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 50
+	pdfFontSize   = 10
+	pdfLeading    = 14
+	pdfColumns    = 80
+)
+
+type pdfLine struct {
+	text string
+	bold bool
+}
+
+// WritePDF renders the invoice as a PDF document. The standard PDF fonts
+// only cover Latin-1, so other characters print as "?".
+func (inv *Invoice) WritePDF(w io.Writer) error {
+	var lines []pdfLine
+	add := func(bold bool, format string, args ...any) {
+		lines = append(lines, pdfLine{text: fmt.Sprintf(format, args...), bold: bold})
+	}
+	row := func(bold bool, label, amount string) {
+		add(bold, "%-*s %*s", pdfColumns-21, clip(label, pdfColumns-21), 20, amount)
+	}
+	rule := func() { add(false, "%s", strings.Repeat("-", pdfColumns)) }
+
+	add(true, "INVOICE %s", inv.Number)
+	add(false, "Issued %s", inv.IssuedAt.Format("2006-01-02"))
+	add(false, "")
+	add(true, "%s", inv.Seller.Name)
+	if inv.Seller.Address != "" {
+		add(false, "%s", clip(inv.Seller.Address, pdfColumns))
+	}
+	add(false, "")
+	add(true, "Billed to")
+	add(false, "%s", clip(inv.Customer.Name, pdfColumns))
+	for _, s := range []string{inv.Customer.Email, inv.Customer.Phone} {
+		if s != "" {
+			add(false, "%s", clip(s, pdfColumns))
+		}
+	}
+	add(false, "")
+	add(false, "Booking %s", inv.BookingID)
+	add(false, "%s", clip(inv.Service+" on "+inv.ScheduledTime, pdfColumns))
+	if inv.Location != "" {
+		add(false, "%s", clip(inv.Location, pdfColumns))
+	}
+	add(false, "")
+
+	row(true, "Description", "Amount")
+	rule()
+	for _, l := range inv.Lines {
+		row(false, l.Description, inv.money(l.Amount))
+	}
+	rule()
+	row(false, "Subtotal", inv.money(inv.Subtotal))
+	for _, t := range inv.Taxes {
+		row(false, t.Name+" ("+percent(t.Rate)+")", inv.money(t.Amount))
+	}
+	row(true, "Total", inv.money(inv.Total))
+	add(false, "")
+
+	if len(inv.Payments) > 0 {
+		row(true, "Payments", "")
+		for _, p := range inv.Payments {
+			row(false, strings.TrimSpace(p.Date+" "+p.Method+" "+p.Status+" "+p.TransactionID), inv.money(p.Amount))
+		}
+		add(false, "")
+	}
+
+	row(false, "Paid", inv.money(inv.AmountPaid))
+	if inv.AmountRefunded != 0 {
+		row(false, "Refunded", inv.money(inv.AmountRefunded))
+	}
+	row(true, "Amount due", inv.money(inv.AmountDue))
+
+	_, err := w.Write(renderPDF(lines))
+	return err
+}
+
+// renderPDF lays the lines out on as many pages as they need and writes
+// the document.
+func renderPDF(lines []pdfLine) []byte {
+	perPage := (pdfPageHeight - 2*pdfMargin) / pdfLeading
+
+	var pages [][]pdfLine
+	for len(lines) > perPage {
+		pages = append(pages, lines[:perPage])
+		lines = lines[perPage:]
+	}
+	pages = append(pages, lines)
+
+	// Objects 1 and 2 are the catalog and the page tree, 3 and 4 the
+	// fonts, then each page is followed by its content.
+	var objects []string
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // the page tree, once the pages are numbered
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	)
+
+	var kids []string
+	for _, page := range pages {
+		pageID := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+
+		var content bytes.Buffer
+		y := pdfPageHeight - pdfMargin
+		for _, l := range page {
+			font := "F1"
+			if l.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, pdfFontSize, pdfMargin, y, pdfString(l.text))
+			y -= pdfLeading
+		}
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfString escapes text for a PDF string in WinAnsiEncoding, which
+// matches Latin-1 for the printable characters.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// clip shortens s to n characters.
+func clip(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
@@ -0,0 +1,138 @@
+package ledger
+
+import (
+	"fmt"
+	"math"
+)
+
+// Accounts of the ledger. The provider payable account is kept per
+// provider; the others belong to the platform.
+const (
+	// AccountCash is the money the platform holds, with the processor or
+	// in the bank.
+	AccountCash = "cash"
+	// AccountCommission is the platform's revenue from commissions.
+	AccountCommission = "commission"
+	// AccountProviderPayable is what the platform owes a provider.
+	AccountProviderPayable = "provider_payable"
+)
+
+// Kinds of transactions.
+const (
+	KindPayment = "payment"
+	KindRefund  = "refund"
+	KindPayout  = "payout"
+)
+
+// Entry debits an account when its amount is positive and credits it
+// when negative. Amounts are in the minor unit of the currency.
+type Entry struct {
+	Account    string
+	ProviderID string
+	Amount     int64
+}
+
+// Transaction is a set of entries that balance. Source identifies what it
+// records, e.g. payment:<transaction ID>, so that an event is never
+// posted twice. Transactions are never changed once posted; mistakes are
+// corrected by posting another one.
+type Transaction struct {
+	Source         string
+	Kind           string
+	ProviderID     string
+	BookingID      string
+	PayoutBatchID  string
+	CommissionRate float64
+	Currency       string
+	Entries        []Entry
+}
+
+// Validate checks that the transaction has entries and that they balance.
+func (t *Transaction) Validate() error {
+	if len(t.Entries) == 0 {
+		return fmt.Errorf("transaction %s has no entries", t.Source)
+	}
+
+	var sum int64
+	for _, e := range t.Entries {
+		sum += e.Amount
+	}
+	if sum != 0 {
+		return fmt.Errorf("transaction %s is off balance by %d", t.Source, sum)
+	}
+	return nil
+}
+
+// Split divides an amount into the platform's commission at rate and the
+// provider's share of the rest.
+func Split(amount int64, rate float64) (commission, share int64) {
+	commission = int64(math.Round(float64(amount) * rate))
+	return commission, amount - commission
+}
+
+// Payment records a payment for a booking: the platform receives the
+// amount, owes the provider their share and keeps the commission.
+func Payment(source, providerID, bookingID string, amount int64, rate float64, currency string) Transaction {
+	commission, share := Split(amount, rate)
+
+	return Transaction{
+		Source:         source,
+		Kind:           KindPayment,
+		ProviderID:     providerID,
+		BookingID:      bookingID,
+		CommissionRate: rate,
+		Currency:       currency,
+		Entries: nonZero(
+			Entry{Account: AccountCash, Amount: amount},
+			Entry{Account: AccountProviderPayable, ProviderID: providerID, Amount: -share},
+			Entry{Account: AccountCommission, Amount: -commission},
+		),
+	}
+}
+
+// Refund records a refund of a booking's payment, taking it back from the
+// provider's share and the commission in the proportion of the payment's
+// rate. A provider who was already paid out goes negative, and the
+// difference is held back from their next payout.
+func Refund(source, providerID, bookingID string, amount int64, rate float64, currency string) Transaction {
+	commission, share := Split(amount, rate)
+
+	return Transaction{
+		Source:         source,
+		Kind:           KindRefund,
+		ProviderID:     providerID,
+		BookingID:      bookingID,
+		CommissionRate: rate,
+		Currency:       currency,
+		Entries: nonZero(
+			Entry{Account: AccountProviderPayable, ProviderID: providerID, Amount: share},
+			Entry{Account: AccountCommission, Amount: commission},
+			Entry{Account: AccountCash, Amount: -amount},
+		),
+	}
+}
+
+// Payout records paying a provider what they are owed.
+func Payout(source, providerID, batchID string, amount int64, currency string) Transaction {
+	return Transaction{
+		Source:        source,
+		Kind:          KindPayout,
+		ProviderID:    providerID,
+		PayoutBatchID: batchID,
+		Currency:      currency,
+		Entries: nonZero(
+			Entry{Account: AccountProviderPayable, ProviderID: providerID, Amount: amount},
+			Entry{Account: AccountCash, Amount: -amount},
+		),
+	}
+}
+
+func nonZero(entries ...Entry) []Entry {
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Amount != 0 {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
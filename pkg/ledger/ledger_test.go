@@ -0,0 +1,98 @@
+package ledger
+
+import "testing"
+
+func balance(t Transaction, account string) int64 {
+	var sum int64
+	for _, e := range t.Entries {
+		if e.Account == account {
+			sum += e.Amount
+		}
+	}
+	return sum
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		amount         int64
+		rate           float64
+		wantCommission int64
+		wantShare      int64
+	}{
+		{10000, 0.15, 1500, 8500},
+		{999, 0.1, 100, 899},
+		{10000, 0, 0, 10000},
+		{10000, 1, 10000, 0},
+		{1, 0.5, 1, 0},
+	}
+
+	for _, tt := range tests {
+		commission, share := Split(tt.amount, tt.rate)
+		if commission != tt.wantCommission || share != tt.wantShare {
+			t.Errorf("Split(%d, %v) = %d, %d, want %d, %d", tt.amount, tt.rate, commission, share, tt.wantCommission, tt.wantShare)
+		}
+	}
+}
+
+func TestTransactionsBalance(t *testing.T) {
+	tests := []struct {
+		name           string
+		tx             Transaction
+		wantCash       int64
+		wantPayable    int64
+		wantCommission int64
+	}{
+		{"payment", Payment("payment:1", "p1", "b1", 10000, 0.15, "usd"), 10000, -8500, -1500},
+		{"payment without commission", Payment("payment:2", "p1", "b1", 10000, 0, "usd"), 10000, -10000, 0},
+		{"refund", Refund("refund:1", "p1", "b1", 4000, 0.15, "usd"), -4000, 3400, 600},
+		{"payout", Payout("payout:1", "p1", "batch1", 8500, "usd"), -8500, 8500, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tx.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			for _, e := range tt.tx.Entries {
+				if e.Amount == 0 {
+					t.Errorf("entry %+v is zero", e)
+				}
+				if e.Account == AccountProviderPayable && e.ProviderID != "p1" {
+					t.Errorf("payable entry %+v is not the provider's", e)
+				}
+			}
+
+			if got := balance(tt.tx, AccountCash); got != tt.wantCash {
+				t.Errorf("cash = %d, want %d", got, tt.wantCash)
+			}
+			if got := balance(tt.tx, AccountProviderPayable); got != tt.wantPayable {
+				t.Errorf("provider payable = %d, want %d", got, tt.wantPayable)
+			}
+			if got := balance(tt.tx, AccountCommission); got != tt.wantCommission {
+				t.Errorf("commission = %d, want %d", got, tt.wantCommission)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+		wantErr bool
+	}{
+		{"balanced", []Entry{{Account: AccountCash, Amount: 100}, {Account: AccountCommission, Amount: -100}}, false},
+		{"no entries", nil, true},
+		{"off balance", []Entry{{Account: AccountCash, Amount: 100}, {Account: AccountCommission, Amount: -99}}, true},
+		{"one sided", []Entry{{Account: AccountCash, Amount: 100}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Transaction{Source: "test", Entries: tt.entries}).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
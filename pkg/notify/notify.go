@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// Receivers of the notifications of an event.
+const (
+	Customer = "customer"
+	Provider = "provider"
+)
+
+// Events that have templates by default.
+const (
+	EventBookingCreated   = "booking_created"
+	EventBookingUpdated   = "booking_updated"
+	EventBookingCancelled = "booking_cancelled"
+	EventPaymentCreated   = "payment_created"
+	EventPaymentFailed    = "payment_failed"
+	EventPaymentRefunded  = "payment_refunded"
+	EventReviewCreated    = "review_created"
+	EventBookingReminder  = "booking_reminder"
+	EventBookingInvoice   = "booking_invoice"
+)
+
+//go:embed templates.json
+var defaultTemplates []byte
+
+// Data is what the templates can refer to. Fields that do not apply to an
+// event are left empty.
+type Data struct {
+	BookingID     string
+	Service       string
+	Provider      string
+	ScheduledTime string
+	Address       string
+	Status        string
+	TotalPrice    float32
+	Amount        float32
+	Rating        int32
+	Comment       string
+	// StartsIn is how long until the booking, e.g. 1h30m.
+	StartsIn      string
+	InvoiceNumber string
+}
+
+// Message is a rendered notification.
+type Message struct {
+	Title   string
+	Message string
+}
+
+type source struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+type compiled struct {
+	title, message *template.Template
+}
+
+// Templates holds the notification templates by locale, event and
+// receiver. An event without a template for a receiver sends that
+// receiver nothing.
+type Templates struct {
+	locale string
+	set    map[string]map[string]map[string]compiled
+}
+
+// Load reads the built-in templates and, if path is set, the templates
+// in that file on top of them, in the same layout: locale, event,
+// receiver, then title and message. Templates missing in a locale fall
+// back to defaultLocale.
+func Load(path, defaultLocale string) (*Templates, error) {
+	t := &Templates{locale: defaultLocale, set: make(map[string]map[string]map[string]compiled)}
+
+	if err := t.add(defaultTemplates); err != nil {
+		return nil, errors.Wrap(err, "invalid built-in templates")
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return t, errors.Wrap(err, "error reading templates")
+		}
+		if err := t.add(data); err != nil {
+			return t, errors.Wrapf(err, "invalid templates in %s", path)
+		}
+	}
+
+	return t, nil
+}
+
+func (t *Templates) add(data []byte) error {
+	var sources map[string]map[string]map[string]source
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return err
+	}
+
+	// Compile everything before adding anything, so that a bad file
+	// leaves the templates as they were.
+	type entry struct {
+		locale, event, receiver string
+		tmpl                    compiled
+	}
+	var entries []entry
+
+	for locale, events := range sources {
+		for event, receivers := range events {
+			for receiver, src := range receivers {
+				name := strings.Join([]string{locale, event, receiver}, ".")
+
+				title, err := template.New(name + ".title").Parse(src.Title)
+				if err != nil {
+					return err
+				}
+				message, err := template.New(name + ".message").Parse(src.Message)
+				if err != nil {
+					return err
+				}
+
+				entries = append(entries, entry{locale, event, receiver, compiled{title, message}})
+			}
+		}
+	}
+
+	for _, e := range entries {
+		if t.set[e.locale] == nil {
+			t.set[e.locale] = make(map[string]map[string]compiled)
+		}
+		if t.set[e.locale][e.event] == nil {
+			t.set[e.locale][e.event] = make(map[string]compiled)
+		}
+		t.set[e.locale][e.event][e.receiver] = e.tmpl
+	}
+
+	return nil
+}
+
+// Has reports whether event has a template for any receiver.
+func (t *Templates) Has(event string) bool {
+	return len(t.set[t.locale][event]) > 0
+}
+
+// Render renders the notification of event for receiver in locale, or in
+// the default locale when locale has no such template. It reports false
+// when the receiver gets no notification for the event.
+func (t *Templates) Render(locale, event, receiver string, data Data) (Message, bool, error) {
+	tmpl, ok := t.set[locale][event][receiver]
+	if !ok {
+		tmpl, ok = t.set[t.locale][event][receiver]
+	}
+	if !ok {
+		return Message{}, false, nil
+	}
+
+	var title, message bytes.Buffer
+	if err := tmpl.title.Execute(&title, data); err != nil {
+		return Message{}, false, err
+	}
+	if err := tmpl.message.Execute(&message, data); err != nil {
+		return Message{}, false, err
+	}
+
+	return Message{Title: title.String(), Message: message.String()}, true, nil
+}
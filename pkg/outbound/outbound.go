@@ -0,0 +1,83 @@
+package outbound
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrForbiddenAddress is returned for a connection to an address that is
+// not on the public internet.
+var ErrForbiddenAddress = errors.New("address is not publicly routable")
+
+// Ranges that are not publicly routable but not caught by the checks of
+// netip.Addr either.
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// NewClient returns a client for URLs that users choose, such as webhook
+// endpoints. It only connects to public addresses, checked after DNS
+// resolution so that a name cannot point it back into the network, and
+// it does not follow redirects or use a proxy.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// control rejects a connection to an address that is not public, right
+// before it is made.
+func control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return errors.Wrap(err, "invalid address")
+	}
+
+	if !IsPublic(addrPort.Addr()) {
+		return errors.Wrapf(ErrForbiddenAddress, "%s", addrPort.Addr())
+	}
+
+	return nil
+}
+
+// IsPublic reports whether addr is a publicly routable unicast address.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+		return false
+	}
+
+	for _, p := range forbiddenPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
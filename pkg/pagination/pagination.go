@@ -0,0 +1,93 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Params selects a single page of a backend list. With After set, the
+// page starts right after that item, wherever the items added or removed
+// since have moved it, and Page is the page it was on.
+type Params struct {
+	Page  int32 `json:"p"`
+	Limit int32 `json:"l"`
+	After *Key  `json:"a,omitempty"`
+}
+
+// Key identifies an item of a list by its creation time and ID.
+type Key struct {
+	CreatedAt string `json:"t,omitempty"`
+	ID        string `json:"id"`
+}
+
+// Offset is the index of the first item of the page.
+func (p Params) Offset() int32 {
+	return (p.Page - 1) * p.Limit
+}
+
+func (p Params) Next() Params {
+	return Params{Page: p.Page + 1, Limit: p.Limit}
+}
+
+func (p Params) Prev() Params {
+	return Params{Page: p.Page - 1, Limit: p.Limit}
+}
+
+func (p Params) First() Params {
+	return Params{Page: 1, Limit: p.Limit}
+}
+
+// EncodeCursor turns page parameters into an opaque cursor string.
+func EncodeCursor(p Params) string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor. Numbers that do
+// not fit in an int32 are rejected.
+func DecodeCursor(cursor string) (Params, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Params{}, errors.Wrap(err, "malformed cursor")
+	}
+
+	var p Params
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Params{}, errors.Wrap(err, "malformed cursor")
+	}
+
+	if p.Page < 1 || p.Limit < 1 || (p.After != nil && p.After.ID == "") {
+		return Params{}, errors.New("malformed cursor")
+	}
+
+	return p, nil
+}
+
+// Links builds an RFC 8288 Link header value pointing at the given
+// cursors. Page selection parameters of u are replaced by the cursor
+// while every other query parameter is kept.
+func Links(u *url.URL, rels map[string]Params) string {
+	links := make([]string, 0, len(rels))
+
+	for _, rel := range []string{"first", "prev", "next"} {
+		p, ok := rels[rel]
+		if !ok {
+			continue
+		}
+
+		q := u.Query()
+		q.Del("page")
+		q.Del("limit")
+		q.Set("cursor", EncodeCursor(p))
+
+		link := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=\"%s\"", link.String(), rel))
+	}
+
+	return strings.Join(links, ", ")
+}
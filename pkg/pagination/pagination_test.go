@@ -0,0 +1,123 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []Params{
+		{Page: 1, Limit: 20},
+		{Page: 7, Limit: 100},
+		{Page: 3, Limit: 10, After: &Key{CreatedAt: "2026-10-19T09:00:00Z", ID: "b7a1"}},
+		{Page: 2, Limit: 5, After: &Key{ID: "no-time"}},
+	}
+
+	for _, p := range tests {
+		cursor := EncodeCursor(p)
+		if strings.ContainsAny(cursor, "+/=") {
+			t.Errorf("EncodeCursor(%+v) = %q, want URL safe", p, cursor)
+		}
+
+		got, err := DecodeCursor(cursor)
+		if err != nil {
+			t.Fatalf("DecodeCursor(EncodeCursor(%+v)) failed: %v", p, err)
+		}
+		if !reflect.DeepEqual(got, p) {
+			t.Errorf("DecodeCursor(EncodeCursor(%+v)) = %+v", p, got)
+		}
+	}
+}
+
+func TestDecodeCursorTampered(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"p":1,"l":20}`))},
+		{"not JSON", encode("page=1")},
+		{"wrong types", encode(`{"p":"1","l":20}`)},
+		{"zero page", encode(`{"p":0,"l":20}`)},
+		{"negative page", encode(`{"p":-1,"l":20}`)},
+		{"missing limit", encode(`{"p":1}`)},
+		{"zero limit", encode(`{"p":1,"l":0}`)},
+		{"page overflows int32", encode(`{"p":2147483648,"l":20}`)},
+		{"limit overflows int32", encode(`{"p":1,"l":99999999999}`)},
+		{"after without ID", encode(`{"p":1,"l":20,"a":{"t":"2026-10-19T09:00:00Z"}}`)},
+		{"truncated", EncodeCursor(Params{Page: 1, Limit: 20})[:10]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := DecodeCursor(tt.cursor); err == nil {
+				t.Fatalf("DecodeCursor(%q) = %+v, want an error", tt.cursor, p)
+			}
+		})
+	}
+}
+
+func TestParams(t *testing.T) {
+	p := Params{Page: 3, Limit: 20}
+
+	if got := p.Offset(); got != 40 {
+		t.Errorf("Offset() = %d, want 40", got)
+	}
+	if got := p.Next(); got != (Params{Page: 4, Limit: 20}) {
+		t.Errorf("Next() = %+v", got)
+	}
+	if got := p.Prev(); got != (Params{Page: 2, Limit: 20}) {
+		t.Errorf("Prev() = %+v", got)
+	}
+	if got := p.First(); got != (Params{Page: 1, Limit: 20}) {
+		t.Errorf("First() = %+v", got)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	u, err := url.Parse("/v1/bookings/all?page=2&limit=20&status=pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := Links(u, map[string]Params{
+		"next":  {Page: 3, Limit: 20},
+		"first": {Page: 1, Limit: 20},
+	})
+
+	links := strings.Split(header, ", ")
+	if len(links) != 2 {
+		t.Fatalf("Links = %q, want a first and a next link", header)
+	}
+
+	for i, rel := range []string{"first", "next"} {
+		target, attr, ok := strings.Cut(links[i], "; ")
+		if !ok || attr != `rel="`+rel+`"` {
+			t.Fatalf("link %d = %q, want rel %q", i, links[i], rel)
+		}
+
+		link, err := url.Parse(strings.Trim(target, "<>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := link.Query()
+		if link.Path != u.Path || q.Get("status") != "pending" || q.Has("page") || q.Has("limit") {
+			t.Errorf("%s link = %q, want the same path and filters with only a cursor", rel, link)
+		}
+
+		p, err := DecodeCursor(q.Get("cursor"))
+		if err != nil {
+			t.Fatalf("%s link cursor: %v", rel, err)
+		}
+		if want := map[string]int32{"first": 1, "next": 3}[rel]; p.Page != want {
+			t.Errorf("%s link is to page %d, want %d", rel, p.Page, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+)
+
+// Disabled is the processor of a gateway that has none configured. Every
+// call fails with ErrNotConfigured, so no payment is taken by accident.
+type Disabled struct{}
+
+func (Disabled) Name() string { return "" }
+
+func (Disabled) CreateIntent(ctx context.Context, p IntentParams) (*Intent, error) {
+	return nil, ErrNotConfigured
+}
+
+func (Disabled) ConfirmIntent(ctx context.Context, intentID string, m Method) (*Intent, error) {
+	return nil, ErrNotConfigured
+}
+
+func (Disabled) GetIntent(ctx context.Context, intentID string) (*Intent, error) {
+	return nil, ErrNotConfigured
+}
+
+func (Disabled) Refund(ctx context.Context, p RefundParams) (*Refund, error) {
+	return nil, ErrNotConfigured
+}
+
+func (Disabled) ParseEvent(body []byte, header http.Header) (*Event, error) {
+	return nil, ErrNotConfigured
+}
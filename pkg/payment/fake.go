@@ -0,0 +1,165 @@
+package payment
+
+import (
+	"api-gateway/pkg/webhook"
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment method tokens the fake treats specially. Any other token is
+// charged successfully.
+const (
+	FakeDeclined   = "pm_card_declined"
+	FakeProcessing = "pm_card_processing"
+)
+
+// Fake stands in for a processor in local setups and tests. It keeps its
+// intents in memory and settles them at once, by the token used.
+//
+// Its webhook events are in the Stripe format and are signed like the
+// gateway's own webhooks, with the X-Webhook-Timestamp and
+// X-Webhook-Signature headers, so that processing intents can be settled
+// by hand.
+type Fake struct {
+	WebhookSecret string
+	Tolerance     time.Duration
+
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	intents  map[string]*Intent
+	keys     map[string]string
+	refunded map[string]int64
+	refunds  map[string]*Refund
+}
+
+func NewFake(logger *slog.Logger) *Fake {
+	return &Fake{
+		logger:   logger,
+		intents:  make(map[string]*Intent),
+		keys:     make(map[string]string),
+		refunded: make(map[string]int64),
+		refunds:  make(map[string]*Refund),
+	}
+}
+
+func (f *Fake) Name() string { return ProcessorFake }
+
+func (f *Fake) CreateIntent(ctx context.Context, p IntentParams) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id, ok := f.keys[p.IdempotencyKey]; ok && p.IdempotencyKey != "" {
+		intent := *f.intents[id]
+		return &intent, nil
+	}
+
+	intent := &Intent{
+		ID:       "pi_fake_" + uuid.NewString(),
+		Amount:   p.Amount,
+		Currency: p.Currency,
+		Status:   StatusPending,
+	}
+	f.intents[intent.ID] = intent
+	f.keys[p.IdempotencyKey] = intent.ID
+
+	if f.logger != nil {
+		f.logger.Info("fake payment intent created", "intent_id", intent.ID, "amount", p.Amount, "reference", p.Reference)
+	}
+
+	created := *intent
+	return &created, nil
+}
+
+func (f *Fake) ConfirmIntent(ctx context.Context, intentID string, m Method) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if intent.Status == StatusPending {
+		intent.MethodType = "card"
+		intent.FailureMessage = ""
+
+		switch m.Token {
+		case FakeDeclined:
+			intent.FailureMessage = "Your card was declined."
+		case FakeProcessing:
+			intent.Status = StatusProcessing
+		default:
+			intent.Status = StatusSucceeded
+		}
+	}
+
+	if f.logger != nil {
+		f.logger.Info("fake payment intent confirmed", "intent_id", intent.ID, "status", intent.Status)
+	}
+
+	confirmed := *intent
+	return &confirmed, nil
+}
+
+func (f *Fake) GetIntent(ctx context.Context, intentID string) (*Intent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	intent, ok := f.intents[intentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	found := *intent
+	return &found, nil
+}
+
+func (f *Fake) Refund(ctx context.Context, p RefundParams) (*Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r, ok := f.refunds[p.IdempotencyKey]; ok && p.IdempotencyKey != "" {
+		refund := *r
+		return &refund, nil
+	}
+
+	intent, ok := f.intents[p.IntentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if intent.Status != StatusSucceeded || p.Amount <= 0 || f.refunded[intent.ID]+p.Amount > intent.Amount {
+		return nil, ErrRefundRejected
+	}
+
+	r := &Refund{
+		ID:     "re_fake_" + uuid.NewString(),
+		Amount: p.Amount,
+		Status: StatusSucceeded,
+	}
+	f.refunded[intent.ID] += p.Amount
+	f.refunds[p.IdempotencyKey] = r
+
+	if f.logger != nil {
+		f.logger.Info("fake payment intent refunded", "intent_id", intent.ID, "amount", p.Amount, "reason", p.Reason)
+	}
+
+	refund := *r
+	return &refund, nil
+}
+
+// ParseEvent rejects every event while the fake has no webhook secret.
+func (f *Fake) ParseEvent(body []byte, header http.Header) (*Event, error) {
+	err := webhook.Verify(f.WebhookSecret, header.Get(webhook.HeaderTimestamp), header.Get(webhook.HeaderSignature),
+		body, f.Tolerance, time.Now())
+	if f.WebhookSecret == "" || err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	return parseStripeEvent(body)
+}
@@ -0,0 +1,185 @@
+package payment
+
+import (
+	"api-gateway/config"
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Statuses of a payment intent. The processors' own statuses are mapped
+// to these.
+const (
+	// StatusPending waits for the customer to confirm it with a payment
+	// method, including again after a declined one.
+	StatusPending = "pending"
+	// StatusProcessing was confirmed and waits for the processor to
+	// report the outcome.
+	StatusProcessing = "processing"
+	StatusSucceeded  = "succeeded"
+	StatusFailed     = "failed"
+	StatusCanceled   = "canceled"
+)
+
+// transitions lists the statuses an intent can move to from each status.
+// Succeeded, failed and canceled intents are settled.
+var transitions = map[string][]string{
+	StatusPending:    {StatusProcessing, StatusSucceeded, StatusFailed, StatusCanceled},
+	StatusProcessing: {StatusSucceeded, StatusFailed, StatusCanceled},
+}
+
+// CanTransition reports whether an intent can move from one status to
+// another. Events that arrive out of order would otherwise move a settled
+// intent back.
+func CanTransition(from, to string) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Names of the processors.
+const (
+	ProcessorStripe = "stripe"
+	ProcessorFake   = "fake"
+)
+
+// ErrNotFound is returned for an intent the processor does not know.
+var ErrNotFound = errors.New("payment intent not found")
+
+// ErrRefundRejected is returned for a refund the processor will not make,
+// e.g. of more than is left of the intent.
+var ErrRefundRejected = errors.New("refund rejected")
+
+// ErrNotConfigured is returned when no processor is configured.
+var ErrNotConfigured = errors.New("payments are not configured")
+
+// ErrInvalidSignature is returned for a webhook event that was not
+// signed by the processor, or was signed too long ago.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Intent is a payment as the processor tracks it. Amounts are in the
+// minor unit of the currency, e.g. cents.
+type Intent struct {
+	ID       string
+	Amount   int64
+	Currency string
+	Status   string
+	// MethodType is the kind of payment method used, e.g. card, once the
+	// intent was confirmed.
+	MethodType string
+	// FailureMessage tells why the last confirmation was declined.
+	FailureMessage string
+}
+
+// Refund gives back part or all of a succeeded intent. Its status is
+// pending while the processor sends the money back, then succeeded or
+// failed.
+type Refund struct {
+	ID     string
+	Amount int64
+	Status string
+}
+
+// RefundParams describes a refund of an intent. The processor does not
+// refund twice for the same idempotency key.
+type RefundParams struct {
+	IntentID       string
+	Amount         int64
+	Reason         string
+	IdempotencyKey string
+}
+
+// Event is a notice the processor sent to the webhook. Intent is set for
+// the events about an intent and has the status the event reports; other
+// events are of no interest.
+type Event struct {
+	ID     string
+	Type   string
+	Intent *Intent
+}
+
+// IntentParams describes the payment to collect. The processor does not
+// create a second intent for the same idempotency key.
+type IntentParams struct {
+	Amount         int64
+	Currency       string
+	Reference      string
+	IdempotencyKey string
+}
+
+// Method is a payment method as tokenized by the processor's client
+// library. The token stands for the customer's card or account and is
+// never logged: Method prints and logs as redacted.
+type Method struct {
+	Token string
+}
+
+func (m Method) String() string { return "[redacted]" }
+
+func (m Method) GoString() string { return "payment.Method{[redacted]}" }
+
+func (m Method) LogValue() slog.Value { return slog.StringValue("[redacted]") }
+
+// PaymentProcessor collects payments through a card processor.
+type PaymentProcessor interface {
+	Name() string
+	CreateIntent(ctx context.Context, p IntentParams) (*Intent, error)
+	// ConfirmIntent charges the intent with the payment method. A
+	// declined payment is not an error: the intent comes back pending
+	// with a FailureMessage.
+	ConfirmIntent(ctx context.Context, intentID string, m Method) (*Intent, error)
+	GetIntent(ctx context.Context, intentID string) (*Intent, error)
+	// Refund gives back an amount of a succeeded intent.
+	Refund(ctx context.Context, p RefundParams) (*Refund, error)
+	// ParseEvent checks the signature of a webhook request and reads the
+	// event in its body.
+	ParseEvent(body []byte, header http.Header) (*Event, error)
+}
+
+// NewProcessor sets up the processor PAYMENT_PROCESSOR names. Without
+// one, or with an unknown one, payments are disabled: the fake has to be
+// chosen explicitly, as it takes any card and keeps its intents in the
+// memory of one instance.
+func NewProcessor(cfg *config.Config, logger *slog.Logger) PaymentProcessor {
+	switch cfg.PAYMENT_PROCESSOR {
+	case ProcessorStripe:
+		return &StripeProcessor{
+			URL:           cfg.STRIPE_API_URL,
+			SecretKey:     cfg.STRIPE_SECRET_KEY,
+			WebhookSecret: cfg.STRIPE_WEBHOOK_SECRET,
+			Tolerance:     cfg.PAYMENT_WEBHOOK_TOLERANCE,
+			Client:        &http.Client{Timeout: 30 * time.Second},
+		}
+	case ProcessorFake:
+		logger.Warn("using the fake payment processor, payments are not real")
+
+		f := NewFake(logger)
+		f.WebhookSecret = cfg.FAKE_WEBHOOK_SECRET
+		f.Tolerance = cfg.PAYMENT_WEBHOOK_TOLERANCE
+		return f
+	}
+
+	if cfg.PAYMENT_PROCESSOR != "" {
+		logger.Error("unknown payment processor, payments are disabled", "processor", cfg.PAYMENT_PROCESSOR)
+	} else {
+		logger.Warn("no payment processor configured, payments are disabled")
+	}
+	return Disabled{}
+}
+
+// ToMinor converts an amount to the minor unit of the currency.
+func ToMinor(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+// FromMinor converts an amount in the minor unit of the currency back.
+func FromMinor(amount int64) float32 {
+	return float32(amount) / 100
+}
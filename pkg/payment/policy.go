@@ -0,0 +1,75 @@
+package payment
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RefundTier refunds Percent of a payment when a booking is cancelled at
+// least Notice before it starts.
+type RefundTier struct {
+	Notice  time.Duration
+	Percent float64
+}
+
+// RefundPolicy is the share of a payment given back when the customer
+// cancels a booking, by how much notice they gave. Cancelling with less
+// notice than any tier refunds nothing.
+type RefundPolicy struct {
+	Tiers []RefundTier
+}
+
+// ParseRefundPolicy reads a policy such as "24h:100,0s:50": a full refund
+// at least 24 hours ahead, and half of it up to the start.
+func ParseRefundPolicy(s string) (RefundPolicy, error) {
+	var p RefundPolicy
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		notice, percent, ok := strings.Cut(part, ":")
+		if !ok {
+			return RefundPolicy{}, fmt.Errorf("invalid refund tier %q", part)
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(notice))
+		if err != nil {
+			return RefundPolicy{}, errors.Wrapf(err, "invalid notice in refund tier %q", part)
+		}
+
+		pct, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil || math.IsNaN(pct) || pct < 0 || pct > 100 {
+			return RefundPolicy{}, fmt.Errorf("invalid percent in refund tier %q", part)
+		}
+
+		p.Tiers = append(p.Tiers, RefundTier{Notice: d, Percent: pct})
+	}
+
+	sort.Slice(p.Tiers, func(i, j int) bool { return p.Tiers[i].Notice > p.Tiers[j].Notice })
+	return p, nil
+}
+
+// Percent returns the share of the payment refunded with the given
+// notice, which is negative after the booking started.
+func (p RefundPolicy) Percent(notice time.Duration) float64 {
+	for _, t := range p.Tiers {
+		if notice >= t.Notice {
+			return t.Percent
+		}
+	}
+	return 0
+}
+
+// Refundable returns how much of amount, in minor units, is refunded with
+// the given notice.
+func (p RefundPolicy) Refundable(amount int64, notice time.Duration) int64 {
+	return int64(math.Floor(float64(amount) * p.Percent(notice) / 100))
+}
@@ -0,0 +1,96 @@
+package payment
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRefundPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		want    []RefundTier
+		wantErr bool
+	}{
+		{policy: "", want: nil},
+		{policy: "24h:100", want: []RefundTier{{Notice: 24 * time.Hour, Percent: 100}}},
+		{
+			policy: " 0s:50 , 24h:100,2h:75.5 ",
+			want: []RefundTier{
+				{Notice: 24 * time.Hour, Percent: 100},
+				{Notice: 2 * time.Hour, Percent: 75.5},
+				{Notice: 0, Percent: 50},
+			},
+		},
+		{policy: "-1h:10", want: []RefundTier{{Notice: -time.Hour, Percent: 10}}},
+		{policy: "24h", wantErr: true},
+		{policy: "a day:100", wantErr: true},
+		{policy: "24:100", wantErr: true},
+		{policy: "24h:all", wantErr: true},
+		{policy: "24h:-1", wantErr: true},
+		{policy: "24h:101", wantErr: true},
+		{policy: "24h:NaN", wantErr: true},
+		{policy: "24h:100,2h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			p, err := ParseRefundPolicy(tt.policy)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRefundPolicy(%q) = %+v, want an error", tt.policy, p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRefundPolicy(%q) failed: %v", tt.policy, err)
+			}
+
+			if len(p.Tiers) != len(tt.want) {
+				t.Fatalf("ParseRefundPolicy(%q) = %+v, want %+v", tt.policy, p.Tiers, tt.want)
+			}
+			for i := range p.Tiers {
+				if p.Tiers[i] != tt.want[i] {
+					t.Errorf("ParseRefundPolicy(%q) tier %d = %+v, want %+v", tt.policy, i, p.Tiers[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRefundable(t *testing.T) {
+	p, err := ParseRefundPolicy("24h:100,2h:50,0s:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		amount int64
+		notice time.Duration
+		want   int64
+	}{
+		{"days ahead", 10000, 72 * time.Hour, 10000},
+		{"exactly a day ahead", 10000, 24 * time.Hour, 10000},
+		{"just under a day", 10000, 24*time.Hour - time.Second, 5000},
+		{"two hours ahead", 10000, 2 * time.Hour, 5000},
+		{"at the start", 10000, 0, 1000},
+		{"after the start", 10000, -time.Minute, 0},
+		{"rounded down", 999, time.Hour, 99},
+		{"nothing paid", 0, 72 * time.Hour, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Refundable(tt.amount, tt.notice); got != tt.want {
+				t.Fatalf("Refundable(%d, %v) = %d, want %d", tt.amount, tt.notice, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefundableEmptyPolicy(t *testing.T) {
+	var p RefundPolicy
+	if got := p.Refundable(10000, 72*time.Hour); got != 0 {
+		t.Fatalf("Refundable with no tiers = %d, want 0", got)
+	}
+}
@@ -0,0 +1,306 @@
+package payment
+
+import (
+	"api-gateway/pkg/webhook"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// StripeProcessor collects payments through the Stripe API, or any API
+// compatible with its payment intents.
+type StripeProcessor struct {
+	URL           string
+	SecretKey     string
+	WebhookSecret string
+	// Tolerance is how old a webhook event may be before it is taken for
+	// a replay.
+	Tolerance time.Duration
+	Client    *http.Client
+}
+
+type stripeIntent struct {
+	ID               string          `json:"id"`
+	Amount           int64           `json:"amount"`
+	Currency         string          `json:"currency"`
+	Status           string          `json:"status"`
+	PaymentMethod    json.RawMessage `json:"payment_method"`
+	LastPaymentError *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"`
+}
+
+type stripeError struct {
+	Error struct {
+		Type          string        `json:"type"`
+		Code          string        `json:"code"`
+		DeclineCode   string        `json:"decline_code"`
+		Message       string        `json:"message"`
+		PaymentIntent *stripeIntent `json:"payment_intent"`
+	} `json:"error"`
+}
+
+func (s *StripeProcessor) Name() string { return ProcessorStripe }
+
+func (s *StripeProcessor) CreateIntent(ctx context.Context, p IntentParams) (*Intent, error) {
+	form := url.Values{
+		"amount":                 {strconv.FormatInt(p.Amount, 10)},
+		"currency":               {p.Currency},
+		"metadata[reference]":    {p.Reference},
+		"payment_method_types[]": {"card"},
+	}
+
+	return s.call(ctx, "/v1/payment_intents", form, p.IdempotencyKey)
+}
+
+func (s *StripeProcessor) ConfirmIntent(ctx context.Context, intentID string, m Method) (*Intent, error) {
+	form := url.Values{
+		"payment_method": {m.Token},
+		"expand[]":       {"payment_method"},
+	}
+
+	return s.call(ctx, "/v1/payment_intents/"+url.PathEscape(intentID)+"/confirm", form, "")
+}
+
+func (s *StripeProcessor) GetIntent(ctx context.Context, intentID string) (*Intent, error) {
+	return s.call(ctx, "/v1/payment_intents/"+url.PathEscape(intentID)+"?expand[]=payment_method", nil, "")
+}
+
+func (s *StripeProcessor) Refund(ctx context.Context, p RefundParams) (*Refund, error) {
+	form := url.Values{
+		"payment_intent":   {p.IntentID},
+		"amount":           {strconv.FormatInt(p.Amount, 10)},
+		"metadata[reason]": {p.Reason},
+	}
+
+	data, err := s.do(ctx, "/v1/refunds", form, p.IdempotencyKey)
+
+	var e *stripeFailure
+	if errors.As(err, &e) && e.Status == http.StatusBadRequest {
+		switch e.Body.Error.Code {
+		case "amount_too_large", "charge_already_refunded", "charge_disputed":
+			return nil, ErrRefundRejected
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		ID     string `json:"id"`
+		Amount int64  `json:"amount"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, errors.Wrap(err, "invalid stripe response")
+	}
+
+	refund := &Refund{ID: r.ID, Amount: r.Amount, Status: StatusPending}
+	switch r.Status {
+	case "succeeded":
+		refund.Status = StatusSucceeded
+	case "failed", "canceled":
+		refund.Status = StatusFailed
+	}
+
+	return refund, nil
+}
+
+// call posts the form to path, or gets path without one, and reads the
+// payment intent in the response.
+func (s *StripeProcessor) call(ctx context.Context, path string, form url.Values, idempotencyKey string) (*Intent, error) {
+	data, err := s.do(ctx, path, form, idempotencyKey)
+
+	// A declined card comes back as an error carrying the intent.
+	var e *stripeFailure
+	if errors.As(err, &e) && e.Body.Error.Type == "card_error" && e.Body.Error.PaymentIntent != nil {
+		intent := e.Body.Error.PaymentIntent.toIntent()
+		if intent.FailureMessage == "" {
+			intent.FailureMessage = e.Body.Error.Message
+		}
+		return intent, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var intent stripeIntent
+	if err := json.Unmarshal(data, &intent); err != nil {
+		return nil, errors.Wrap(err, "invalid stripe response")
+	}
+
+	return intent.toIntent(), nil
+}
+
+// do posts the form to path, or gets path without one, and returns the
+// body of a successful response.
+func (s *StripeProcessor) do(ctx context.Context, path string, form url.Values, idempotencyKey string) ([]byte, error) {
+	method, body := http.MethodGet, io.Reader(nil)
+	if form != nil {
+		method, body = http.MethodPost, strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(s.URL, "/")+path, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid request")
+	}
+	req.Header.Set("Authorization", "Bearer "+s.SecretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error calling stripe")
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading stripe response")
+	}
+
+	if resp.StatusCode/100 != 2 {
+		e := &stripeFailure{Status: resp.StatusCode}
+		_ = json.Unmarshal(data, &e.Body)
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, e
+	}
+
+	return data, nil
+}
+
+// stripeFailure is an error response of the API.
+type stripeFailure struct {
+	Status int
+	Body   stripeError
+}
+
+// Error reports only the codes, as Stripe messages may quote the payment
+// method.
+func (e *stripeFailure) Error() string {
+	return fmt.Sprintf("stripe error %d: %s %s", e.Status, e.Body.Error.Type, e.Body.Error.Code)
+}
+
+// ParseEvent checks the Stripe-Signature header, "t=<timestamp>,v1=<signature>",
+// where the signature is the hex HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with the webhook secret. Several v1 signatures are sent while the
+// secret is being rolled.
+func (s *StripeProcessor) ParseEvent(body []byte, header http.Header) (*Event, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header.Get("Stripe-Signature"), ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			signatures = append(signatures, "v1="+v)
+		}
+	}
+
+	valid := false
+	for _, sig := range signatures {
+		if webhook.Verify(s.WebhookSecret, timestamp, sig, body, s.Tolerance, time.Now()) == nil {
+			valid = true
+			break
+		}
+	}
+	if s.WebhookSecret == "" || !valid {
+		return nil, ErrInvalidSignature
+	}
+
+	return parseStripeEvent(body)
+}
+
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// parseStripeEvent reads an event in the Stripe format. The events of
+// payment intents carry the intent, with the status the event stands
+// for.
+func parseStripeEvent(body []byte) (*Event, error) {
+	var e stripeEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, errors.Wrap(err, "invalid event")
+	}
+	if e.ID == "" {
+		return nil, errors.New("event has no ID")
+	}
+
+	event := &Event{ID: e.ID, Type: e.Type}
+
+	status, ok := map[string]string{
+		"payment_intent.processing":     StatusProcessing,
+		"payment_intent.succeeded":      StatusSucceeded,
+		"payment_intent.payment_failed": StatusFailed,
+		"payment_intent.canceled":       StatusCanceled,
+	}[e.Type]
+	if !ok {
+		return event, nil
+	}
+
+	var si stripeIntent
+	if err := json.Unmarshal(e.Data.Object, &si); err != nil || si.ID == "" {
+		return nil, errors.New("event has no payment intent")
+	}
+
+	event.Intent = si.toIntent()
+	event.Intent.Status = status
+	return event, nil
+}
+
+func (si *stripeIntent) toIntent() *Intent {
+	intent := &Intent{
+		ID:       si.ID,
+		Amount:   si.Amount,
+		Currency: si.Currency,
+	}
+
+	switch si.Status {
+	case "processing", "requires_capture":
+		intent.Status = StatusProcessing
+	case "succeeded":
+		intent.Status = StatusSucceeded
+	case "canceled":
+		intent.Status = StatusCanceled
+	case "requires_action":
+		intent.Status = StatusPending
+		intent.FailureMessage = "the payment needs authentication"
+	default:
+		intent.Status = StatusPending
+	}
+
+	if si.LastPaymentError != nil {
+		intent.FailureMessage = si.LastPaymentError.Message
+	}
+
+	// The payment method is an ID unless it was expanded.
+	var pm struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(si.PaymentMethod, &pm) == nil {
+		intent.MethodType = pm.Type
+	}
+
+	return intent
+}
@@ -0,0 +1,148 @@
+package pricing
+
+import (
+	"api-gateway/config"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Quote is the server side price breakdown of a booking.
+type Quote struct {
+	BasePrice         float32 `json:"base_price"`
+	DistanceKm        float32 `json:"distance_km"`
+	DistanceSurcharge float32 `json:"distance_surcharge"`
+	PeakSurcharge     float32 `json:"peak_surcharge"`
+	Tax               float32 `json:"tax"`
+	TotalPrice        float32 `json:"total_price"`
+}
+
+type hourRange struct {
+	from, to int
+}
+
+type Calculator struct {
+	PricePerKm     float64
+	FreeDistanceKm float64
+	PeakRate       float64
+	TaxRate        float64
+	// TimeZone is the zone peak hours are in.
+	TimeZone  *time.Location
+	peakHours []hourRange
+}
+
+func NewCalculator(cfg *config.Config) *Calculator {
+	peakHours, err := parseHourRanges(cfg.PRICING_PEAK_HOURS)
+	if err != nil {
+		log.Println(errors.Wrap(err, "failed to parse peak hours, peak pricing is disabled"))
+	}
+
+	loc, err := time.LoadLocation(cfg.SCHEDULE_TIMEZONE)
+	if err != nil {
+		log.Println(errors.Wrap(err, "failed to load time zone, peak hours are in UTC"))
+		loc = time.UTC
+	}
+
+	return &Calculator{
+		PricePerKm:     cfg.PRICING_PRICE_PER_KM,
+		FreeDistanceKm: cfg.PRICING_FREE_DISTANCE_KM,
+		PeakRate:       cfg.PRICING_PEAK_RATE,
+		TaxRate:        cfg.PRICING_TAX_RATE,
+		TimeZone:       loc,
+		peakHours:      peakHours,
+	}
+}
+
+// Quote prices a service booked distanceKm away from the provider at
+// the given time. Surcharges are applied to the base price and taxes
+// to the subtotal.
+func (c *Calculator) Quote(basePrice float32, distanceKm float64, at time.Time) Quote {
+	base := float64(basePrice)
+
+	var distanceSurcharge float64
+	if distanceKm > c.FreeDistanceKm {
+		distanceSurcharge = (distanceKm - c.FreeDistanceKm) * c.PricePerKm
+	}
+
+	var peakSurcharge float64
+	if c.isPeak(at) {
+		peakSurcharge = base * c.PeakRate
+	}
+
+	subtotal := round(base) + round(distanceSurcharge) + round(peakSurcharge)
+	tax := round(subtotal * c.TaxRate)
+
+	return Quote{
+		BasePrice:         float32(round(base)),
+		DistanceKm:        float32(round(distanceKm)),
+		DistanceSurcharge: float32(round(distanceSurcharge)),
+		PeakSurcharge:     float32(round(peakSurcharge)),
+		Tax:               float32(tax),
+		TotalPrice:        float32(round(subtotal + tax)),
+	}
+}
+
+// Matches reports whether a client supplied total agrees with the quote
+// to the cent.
+func (q Quote) Matches(total float32) bool {
+	return math.Abs(float64(total-q.TotalPrice)) < 0.01
+}
+
+// isPeak reports whether at falls in peak hours. The hour is read in the
+// calculator's zone, whatever offset the client sent the time with.
+func (c *Calculator) isPeak(at time.Time) bool {
+	if c.TimeZone != nil {
+		at = at.In(c.TimeZone)
+	}
+	hour := at.Hour()
+	for _, r := range c.peakHours {
+		if hour >= r.from && hour < r.to {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHourRanges parses ranges such as "7-10,17-20" where the end hour
+// is exclusive.
+func parseHourRanges(s string) ([]hourRange, error) {
+	var ranges []hourRange
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, errors.Errorf("invalid hour range %q", part)
+		}
+
+		f, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hour range %q", part)
+		}
+
+		t, err := strconv.Atoi(strings.TrimSpace(to))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hour range %q", part)
+		}
+
+		if f < 0 || t > 24 || f >= t {
+			return nil, errors.Errorf("invalid hour range %q", part)
+		}
+
+		ranges = append(ranges, hourRange{from: f, to: t})
+	}
+
+	return ranges, nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
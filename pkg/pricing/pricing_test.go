@@ -0,0 +1,124 @@
+package pricing
+
+import (
+	"api-gateway/config"
+	"testing"
+	"time"
+)
+
+func newTestCalculator(t *testing.T, zone string) *Calculator {
+	t.Helper()
+
+	return NewCalculator(&config.Config{
+		PRICING_PRICE_PER_KM:     0.5,
+		PRICING_FREE_DISTANCE_KM: 5,
+		PRICING_PEAK_HOURS:       "7-10,17-20",
+		PRICING_PEAK_RATE:        0.2,
+		PRICING_TAX_RATE:         0.12,
+		SCHEDULE_TIMEZONE:        zone,
+	})
+}
+
+func TestQuote(t *testing.T) {
+	c := newTestCalculator(t, "UTC")
+	offPeak := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	peak := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		base       float32
+		distanceKm float64
+		at         time.Time
+		want       Quote
+	}{
+		{
+			name: "within free distance",
+			base: 100, distanceKm: 3, at: offPeak,
+			want: Quote{BasePrice: 100, DistanceKm: 3, Tax: 12, TotalPrice: 112},
+		},
+		{
+			name: "distance surcharge",
+			base: 100, distanceKm: 15, at: offPeak,
+			want: Quote{BasePrice: 100, DistanceKm: 15, DistanceSurcharge: 5, Tax: 12.6, TotalPrice: 117.6},
+		},
+		{
+			name: "peak surcharge",
+			base: 100, distanceKm: 0, at: peak,
+			want: Quote{BasePrice: 100, PeakSurcharge: 20, Tax: 14.4, TotalPrice: 134.4},
+		},
+		{
+			name: "both surcharges",
+			base: 50, distanceKm: 7.5, at: peak,
+			want: Quote{BasePrice: 50, DistanceKm: 7.5, DistanceSurcharge: 1.25, PeakSurcharge: 10, Tax: 7.35, TotalPrice: 68.6},
+		},
+		{
+			name: "peak end is exclusive",
+			base: 100, distanceKm: 0, at: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC),
+			want: Quote{BasePrice: 100, Tax: 12, TotalPrice: 112},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Quote(tt.base, tt.distanceKm, tt.at); got != tt.want {
+				t.Fatalf("Quote(%v, %v, %v) = %+v, want %+v", tt.base, tt.distanceKm, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotePeakInTimeZone(t *testing.T) {
+	c := newTestCalculator(t, "Asia/Tashkent")
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		wantPeak bool
+	}{
+		{"08:00 in Tashkent sent as UTC", time.Date(2026, 10, 19, 3, 0, 0, 0, time.UTC), true},
+		{"08:00 UTC is 13:00 in Tashkent", time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), false},
+		{"18:00 in Tashkent sent with its offset", time.Date(2026, 10, 19, 18, 0, 0, 0, time.FixedZone("UZT", 5*3600)), true},
+		{"18:00 in Tashkent sent from New York", time.Date(2026, 10, 19, 9, 0, 0, 0, time.FixedZone("EDT", -4*3600)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := c.Quote(100, 0, tt.at)
+			if got := q.PeakSurcharge > 0; got != tt.wantPeak {
+				t.Fatalf("peak at %v = %v, want %v", tt.at, got, tt.wantPeak)
+			}
+		})
+	}
+}
+
+func TestQuoteInvalidPeakHours(t *testing.T) {
+	for _, hours := range []string{"7-", "10-7", "7-25", "a-b"} {
+		c := NewCalculator(&config.Config{PRICING_PEAK_HOURS: hours, PRICING_PEAK_RATE: 0.2, SCHEDULE_TIMEZONE: "UTC"})
+
+		q := c.Quote(100, 0, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC))
+		if q.PeakSurcharge != 0 {
+			t.Errorf("peak hours %q: peak surcharge = %v, want peak pricing disabled", hours, q.PeakSurcharge)
+		}
+	}
+}
+
+func TestQuoteMatches(t *testing.T) {
+	q := Quote{TotalPrice: 117.6}
+
+	tests := []struct {
+		total float32
+		want  bool
+	}{
+		{117.6, true},
+		{117.605, true},
+		{117.59, false},
+		{117.62, false},
+		{0, false},
+	}
+
+	for _, tt := range tests {
+		if got := q.Matches(tt.total); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Kind tells how the values of a field are parsed and compared.
+type Kind int
+
+const (
+	String Kind = iota
+	Number
+	Time
+)
+
+// Schema whitelists the fields of a resource that can be filtered and
+// sorted on. Nested fields use dotted paths such as "location.city".
+type Schema map[string]Kind
+
+const (
+	OpEq   = "eq"
+	OpNe   = "ne"
+	OpGt   = "gt"
+	OpGte  = "gte"
+	OpLt   = "lt"
+	OpLte  = "lte"
+	OpLike = "like"
+	OpIn   = "in"
+)
+
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", time.DateOnly}
+
+type Condition struct {
+	Field  string
+	Op     string
+	Values []string
+	kind   Kind
+}
+
+type SortKey struct {
+	Field string
+	Desc  bool
+	kind  Kind
+}
+
+// Query is a parsed filter and sort expression.
+type Query struct {
+	Conditions []Condition
+	Sort       []SortKey
+}
+
+// Parse parses expressions such as
+// "status:eq:pending,scheduled_time:gte:2026-10-01" and "-created_at,total_price",
+// rejecting fields that are not in the schema. Multiple values of the
+// "in" operator are separated by "|".
+func Parse(filter, sortBy string, schema Schema) (*Query, error) {
+	q := &Query{}
+
+	for _, expr := range split(filter) {
+		parts := strings.SplitN(expr, ":", 3)
+		if len(parts) != 3 {
+			return nil, errors.Errorf("invalid filter %q: expected field:op:value", expr)
+		}
+
+		cond := Condition{Field: parts[0], Op: parts[1], Values: []string{parts[2]}}
+
+		kind, ok := schema[cond.Field]
+		if !ok {
+			return nil, errors.Errorf("filtering on %q is not supported", cond.Field)
+		}
+		cond.kind = kind
+
+		if err := checkOp(cond.Op, kind); err != nil {
+			return nil, errors.Wrapf(err, "invalid filter %q", expr)
+		}
+
+		if cond.Op == OpIn {
+			cond.Values = strings.Split(parts[2], "|")
+		}
+
+		for _, v := range cond.Values {
+			if err := checkValue(v, kind); err != nil {
+				return nil, errors.Wrapf(err, "invalid filter %q", expr)
+			}
+		}
+
+		q.Conditions = append(q.Conditions, cond)
+	}
+
+	for _, field := range split(sortBy) {
+		key := SortKey{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+
+		kind, ok := schema[key.Field]
+		if !ok {
+			return nil, errors.Errorf("sorting on %q is not supported", key.Field)
+		}
+		key.kind = kind
+
+		q.Sort = append(q.Sort, key)
+	}
+
+	return q, nil
+}
+
+func (q *Query) IsEmpty() bool {
+	return len(q.Conditions) == 0 && len(q.Sort) == 0
+}
+
+// Lookup returns the first value the query compares the field with
+// using op, for pushing simple conditions down to a backend filter.
+func (q *Query) Lookup(field, op string) (string, bool) {
+	for _, c := range q.Conditions {
+		if c.Field == field && c.Op == op {
+			return c.Values[0], true
+		}
+	}
+	return "", false
+}
+
+// Match reports whether the message satisfies every condition.
+func (q *Query) Match(m proto.Message) bool {
+	for _, c := range q.Conditions {
+		v, ok := lookup(m.ProtoReflect(), c.Field)
+		if !ok || !c.match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// SortSlice orders messages by the sort keys, keeping the original
+// order of equal items.
+func SortSlice[T proto.Message](q *Query, items []T) {
+	if len(q.Sort) == 0 {
+		return
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, key := range q.Sort {
+			a, _ := lookup(items[i].ProtoReflect(), key.Field)
+			b, _ := lookup(items[j].ProtoReflect(), key.Field)
+
+			cmp := compare(a, b, key.kind)
+			if cmp == 0 {
+				continue
+			}
+			if key.Desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+func (c Condition) match(v string) bool {
+	switch c.Op {
+	case OpIn:
+		for _, want := range c.Values {
+			if compare(v, want, c.kind) == 0 {
+				return true
+			}
+		}
+		return false
+	case OpLike:
+		return strings.Contains(strings.ToLower(v), strings.ToLower(c.Values[0]))
+	}
+
+	cmp := compare(v, c.Values[0], c.kind)
+	switch c.Op {
+	case OpEq:
+		return cmp == 0
+	case OpNe:
+		return cmp != 0
+	case OpGt:
+		return cmp > 0
+	case OpGte:
+		return cmp >= 0
+	case OpLt:
+		return cmp < 0
+	case OpLte:
+		return cmp <= 0
+	}
+	return false
+}
+
+func checkOp(op string, kind Kind) error {
+	switch op {
+	case OpEq, OpNe, OpIn:
+		return nil
+	case OpGt, OpGte, OpLt, OpLte:
+		if kind == String {
+			return errors.Errorf("operator %q needs a numeric or time field", op)
+		}
+		return nil
+	case OpLike:
+		if kind != String {
+			return errors.Errorf("operator %q needs a text field", op)
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown operator %q", op)
+	}
+}
+
+func checkValue(v string, kind Kind) error {
+	switch kind {
+	case Number:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return errors.Errorf("%q is not a number", v)
+		}
+	case Time:
+		if _, ok := parseTime(v); !ok {
+			return errors.Errorf("%q is not a date or RFC3339 timestamp", v)
+		}
+	}
+	return nil
+}
+
+// compare orders two raw field values according to their kind. Values
+// that cannot be parsed sort before valid ones.
+func compare(a, b string, kind Kind) int {
+	switch kind {
+	case Number:
+		x, errA := strconv.ParseFloat(a, 64)
+		y, errB := strconv.ParseFloat(b, 64)
+		if errA != nil || errB != nil {
+			return boolCmp(errA == nil, errB == nil)
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case Time:
+		x, okA := parseTime(a)
+		y, okB := parseTime(b)
+		if !okA || !okB {
+			return boolCmp(okA, okB)
+		}
+		return x.Compare(y)
+	default:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+}
+
+func boolCmp(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+func parseTime(v string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// lookup reads a possibly nested scalar field by its proto name and
+// renders it as a string.
+func lookup(m protoreflect.Message, path string) (string, bool) {
+	parts := strings.Split(path, ".")
+
+	for i, part := range parts {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(part))
+		if fd == nil || fd.IsList() || fd.IsMap() {
+			return "", false
+		}
+
+		if i < len(parts)-1 {
+			if fd.Kind() != protoreflect.MessageKind {
+				return "", false
+			}
+			m = m.Get(fd).Message()
+			continue
+		}
+
+		return fmt.Sprint(m.Get(fd).Interface()), true
+	}
+
+	return "", false
+}
+
+func split(s string) []string {
+	var parts []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
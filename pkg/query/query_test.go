@@ -0,0 +1,157 @@
+package query
+
+import (
+	pb "api-gateway/genproto/bookings"
+	"testing"
+)
+
+var testSchema = Schema{
+	"status":         String,
+	"total_price":    Number,
+	"scheduled_time": Time,
+	"location.city":  String,
+}
+
+var testBookings = []*pb.Booking{
+	{Id: "1", Status: "pending", TotalPrice: 120, ScheduledTime: "2026-10-20T09:00:00Z", Location: &pb.Location{City: "Tashkent"}},
+	{Id: "2", Status: "confirmed", TotalPrice: 80.5, ScheduledTime: "2026-10-19T15:00:00+05:00", Location: &pb.Location{City: "Samarkand"}},
+	{Id: "3", Status: "cancelled", TotalPrice: 200, ScheduledTime: "2026-10-01T12:00:00Z", Location: &pb.Location{City: "tashkent"}},
+	{Id: "4", Status: "pending", TotalPrice: 80.5, ScheduledTime: "2026-10-22T08:00:00Z"},
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		sort   string
+	}{
+		{"missing value", "status:eq", ""},
+		{"unknown field", "user_id:eq:1", ""},
+		{"unknown operator", "status:has:pending", ""},
+		{"ordering a text field", "status:gt:pending", ""},
+		{"like on a number", "total_price:like:1", ""},
+		{"not a number", "total_price:gte:cheap", ""},
+		{"not a time", "scheduled_time:gte:tomorrow", ""},
+		{"one bad value of in", "total_price:in:10|ten", ""},
+		{"unknown sort field", "", "-user_id"},
+		{"nested field not in schema", "location.country:eq:UZ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if q, err := Parse(tt.filter, tt.sort, testSchema); err == nil {
+				t.Fatalf("Parse(%q, %q) = %+v, want an error", tt.filter, tt.sort, q)
+			}
+		})
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	q, err := Parse(" , ", "", testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.IsEmpty() {
+		t.Fatalf("Parse of blank expressions = %+v, want an empty query", q)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"status:eq:pending", []string{"1", "4"}},
+		{"status:eq:PENDING", []string{"1", "4"}},
+		{"status:ne:pending", []string{"2", "3"}},
+		{"status:in:confirmed|cancelled", []string{"2", "3"}},
+		{"total_price:gt:80.5", []string{"1", "3"}},
+		{"total_price:lte:80.5", []string{"2", "4"}},
+		{"total_price:eq:80.50", []string{"2", "4"}},
+		{"scheduled_time:gte:2026-10-20", []string{"1", "4"}},
+		{"scheduled_time:lt:2026-10-19T12:00:00Z", []string{"2", "3"}},
+		{"location.city:like:TASH", []string{"1", "3"}},
+		{"location.city:eq:", []string{"4"}},
+		{"status:eq:pending,total_price:gt:100", []string{"1"}},
+		{"status:eq:completed", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			q, err := Parse(tt.filter, "", testSchema)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.filter, err)
+			}
+
+			var got []string
+			for _, b := range testBookings {
+				if q.Match(b) {
+					got = append(got, b.Id)
+				}
+			}
+			if !equal(got, tt.want) {
+				t.Fatalf("%q matches %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortSlice(t *testing.T) {
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"", []string{"1", "2", "3", "4"}},
+		{"total_price", []string{"2", "4", "1", "3"}},
+		{"-total_price", []string{"3", "1", "2", "4"}},
+		{"scheduled_time", []string{"3", "2", "1", "4"}},
+		{"-status,total_price", []string{"4", "1", "2", "3"}},
+		{"location.city,-total_price", []string{"4", "2", "3", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			q, err := Parse("", tt.sort, testSchema)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.sort, err)
+			}
+
+			items := append([]*pb.Booking(nil), testBookings...)
+			SortSlice(q, items)
+
+			got := make([]string, len(items))
+			for i, b := range items {
+				got[i] = b.Id
+			}
+			if !equal(got, tt.want) {
+				t.Fatalf("sorted by %q = %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	q, err := Parse("status:eq:pending,total_price:gte:10", "", testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := q.Lookup("status", OpEq); !ok || v != "pending" {
+		t.Errorf("Lookup(status, eq) = %q, %v", v, ok)
+	}
+	if _, ok := q.Lookup("status", OpNe); ok {
+		t.Error("Lookup(status, ne) found a condition")
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,272 @@
+package schedule
+
+import (
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrOutsideHours = errors.New("outside of provider's working hours")
+	ErrSlotTaken    = errors.New("time slot is already booked")
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is a daily opening interval of a provider, expressed in
+// minutes since local midnight of its time zone.
+type Window struct {
+	Day      time.Weekday
+	Start    int
+	End      int
+	Location *time.Location
+}
+
+// Schedule is the structured weekly schedule parsed from a provider's
+// availability entries. A schedule without entries is open around the
+// clock.
+type Schedule struct {
+	windows []Window
+	loc     *time.Location
+}
+
+// Interval is a half-open time range [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Overlaps(o Interval) bool {
+	return i.Start.Before(o.End) && o.Start.Before(i.End)
+}
+
+// Parse builds a weekly schedule from availability entries such as
+// "mon-fri 09:00-18:00 Asia/Tashkent" or "sat,sun 10:00-16:00". Entries
+// without a time zone use def.
+func Parse(availability []string, def *time.Location) (*Schedule, error) {
+	s := &Schedule{loc: def}
+
+	for _, entry := range availability {
+		windows, err := ParseEntry(entry, def)
+		if err != nil {
+			return nil, err
+		}
+		s.windows = append(s.windows, windows...)
+	}
+
+	return s, nil
+}
+
+// ParseEntry parses a single availability entry into one window per day.
+func ParseEntry(entry string, def *time.Location) ([]Window, error) {
+	fields := strings.Fields(entry)
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, errors.Errorf("invalid availability %q: expected \"<days> <HH:MM>-<HH:MM> [time zone]\"", entry)
+	}
+
+	days, err := parseDays(fields[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid availability %q", entry)
+	}
+
+	start, end, err := parseHours(fields[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid availability %q", entry)
+	}
+
+	loc := def
+	if len(fields) == 3 {
+		loc, err = time.LoadLocation(fields[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid availability %q", entry)
+		}
+	}
+
+	windows := make([]Window, 0, len(days))
+	for _, d := range days {
+		windows = append(windows, Window{Day: d, Start: start, End: end, Location: loc})
+	}
+
+	return windows, nil
+}
+
+func (s *Schedule) IsEmpty() bool {
+	return len(s.windows) == 0
+}
+
+// openWindows returns the opening windows, or the whole of every day in
+// the default time zone when the schedule is empty.
+func (s *Schedule) openWindows() []Window {
+	if !s.IsEmpty() {
+		return s.windows
+	}
+
+	loc := s.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	windows := make([]Window, 0, 7)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		windows = append(windows, Window{Day: d, Start: 0, End: 24 * 60, Location: loc})
+	}
+	return windows
+}
+
+// Contains reports whether the interval fits entirely inside one of the
+// opening windows.
+func (s *Schedule) Contains(i Interval) bool {
+	if s.IsEmpty() {
+		return true
+	}
+
+	for _, w := range s.windows {
+		start := i.Start.In(w.Location)
+		if start.Weekday() != w.Day {
+			continue
+		}
+
+		open, closing := w.on(start)
+		if !i.Start.Before(open) && !i.End.After(closing) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Slots splits the opening windows of the given calendar date into
+// consecutive slots of length d. The date is interpreted in the time
+// zone of each window.
+func (s *Schedule) Slots(date string, d time.Duration) ([]Interval, error) {
+	if d <= 0 {
+		return nil, errors.New("slot length must be positive")
+	}
+
+	var slots []Interval
+	for _, w := range s.openWindows() {
+		day, err := time.ParseInLocation(time.DateOnly, date, w.Location)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid date")
+		}
+		if day.Weekday() != w.Day {
+			continue
+		}
+
+		open, closing := w.on(day)
+		for start := open; !start.Add(d).After(closing); start = start.Add(d) {
+			slots = append(slots, Interval{Start: start, End: start.Add(d)})
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].Start.Before(slots[j].Start)
+	})
+
+	return slots, nil
+}
+
+// Free returns the slots that do not overlap any of the busy intervals.
+func Free(slots, busy []Interval) []Interval {
+	free := make([]Interval, 0, len(slots))
+
+	for _, slot := range slots {
+		taken := false
+		for _, b := range busy {
+			if slot.Overlaps(b) {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			free = append(free, slot)
+		}
+	}
+
+	return free
+}
+
+func parseDays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+
+		first, ok := weekdays[from]
+		if !ok {
+			return nil, errors.Errorf("unknown day %q", from)
+		}
+		if !isRange {
+			days = append(days, first)
+			continue
+		}
+
+		last, ok := weekdays[to]
+		if !ok {
+			return nil, errors.Errorf("unknown day %q", to)
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == last {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+func parseHours(s string) (int, int, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, errors.Errorf("invalid hours %q", s)
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := parseClock(to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end == 0 {
+		end = 24 * 60
+	}
+	if start >= end {
+		return 0, 0, errors.Errorf("opening time %s is not before closing time %s", from, to)
+	}
+
+	return start, end, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errors.Errorf("invalid time of day %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// on returns the opening and closing time of the window on the date of
+// day, read on the wall clock of its time zone so that days on which
+// the clocks change keep their hours.
+func (w Window) on(day time.Time) (time.Time, time.Time) {
+	y, m, d := day.In(w.Location).Date()
+	open := time.Date(y, m, d, w.Start/60, w.Start%60, 0, 0, w.Location)
+	closing := time.Date(y, m, d, w.End/60, w.End%60, 0, 0, w.Location)
+	return open, closing
+}
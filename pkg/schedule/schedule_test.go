@@ -0,0 +1,278 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestParseEntry(t *testing.T) {
+	tashkent := mustLoad(t, "Asia/Tashkent")
+
+	tests := []struct {
+		entry   string
+		want    []Window
+		wantErr bool
+	}{
+		{
+			entry: "mon 09:00-18:00",
+			want:  []Window{{Day: time.Monday, Start: 9 * 60, End: 18 * 60, Location: time.UTC}},
+		},
+		{
+			entry: "fri-mon 10:30-16:45 Asia/Tashkent",
+			want: []Window{
+				{Day: time.Friday, Start: 630, End: 1005, Location: tashkent},
+				{Day: time.Saturday, Start: 630, End: 1005, Location: tashkent},
+				{Day: time.Sunday, Start: 630, End: 1005, Location: tashkent},
+				{Day: time.Monday, Start: 630, End: 1005, Location: tashkent},
+			},
+		},
+		{
+			entry: "SAT,sun 20:00-00:00",
+			want: []Window{
+				{Day: time.Saturday, Start: 20 * 60, End: 24 * 60, Location: time.UTC},
+				{Day: time.Sunday, Start: 20 * 60, End: 24 * 60, Location: time.UTC},
+			},
+		},
+		{entry: "mon", wantErr: true},
+		{entry: "mon 09:00-18:00 Asia/Tashkent extra", wantErr: true},
+		{entry: "funday 09:00-18:00", wantErr: true},
+		{entry: "mon-funday 09:00-18:00", wantErr: true},
+		{entry: "mon 09:00", wantErr: true},
+		{entry: "mon 09:00xyz-18:00", wantErr: true},
+		{entry: "mon 09:00-18:00xyz", wantErr: true},
+		{entry: "mon 9-18", wantErr: true},
+		{entry: "mon 24:00-25:00", wantErr: true},
+		{entry: "mon 09:60-18:00", wantErr: true},
+		{entry: "mon 18:00-09:00", wantErr: true},
+		{entry: "mon 09:00-09:00", wantErr: true},
+		{entry: "mon 09:00-18:00 Mars/Olympus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, err := ParseEntry(tt.entry, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseEntry(%q) = %v, want an error", tt.entry, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEntry(%q) failed: %v", tt.entry, err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseEntry(%q) = %v, want %v", tt.entry, got, tt.want)
+			}
+			for i := range got {
+				if !sameWindow(got[i], tt.want[i]) {
+					t.Errorf("ParseEntry(%q)[%d] = %v, want %v", tt.entry, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// sameWindow compares windows by the name of their time zone, as every
+// load of a zone is a location of its own.
+func sameWindow(a, b Window) bool {
+	return a.Day == b.Day && a.Start == b.Start && a.End == b.End && a.Location.String() == b.Location.String()
+}
+
+func TestContains(t *testing.T) {
+	tashkent := mustLoad(t, "Asia/Tashkent")
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	weekdays, err := Parse([]string{"mon-fri 09:00-18:00 Asia/Tashkent"}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlinDays, err := Parse([]string{"sun 09:00-18:00"}, berlin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, err := Parse(nil, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(loc *time.Location, day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		start    time.Time
+		d        time.Duration
+		want     bool
+	}{
+		{"inside", weekdays, at(tashkent, 19, 10, 0), time.Hour, true},
+		{"at opening", weekdays, at(tashkent, 19, 9, 0), time.Hour, true},
+		{"up to closing", weekdays, at(tashkent, 19, 17, 0), time.Hour, true},
+		{"before opening", weekdays, at(tashkent, 19, 8, 30), time.Hour, false},
+		{"past closing", weekdays, at(tashkent, 19, 17, 30), time.Hour, false},
+		{"weekend", weekdays, at(tashkent, 18, 10, 0), time.Hour, false},
+		{"given in another zone", weekdays, at(time.UTC, 19, 5, 0), time.Hour, true},
+		{"late in another zone", weekdays, at(time.UTC, 19, 13, 30), time.Hour, false},
+		// Clocks go back on 25 October 2026 in Berlin, so 09:00 is an
+		// hour later in UTC than the day before.
+		{"opening on DST day", berlinDays, at(berlin, 25, 9, 0), time.Hour, true},
+		{"before opening on DST day", berlinDays, at(time.UTC, 25, 7, 30), time.Hour, false},
+		{"closing on DST day", berlinDays, at(berlin, 25, 17, 0), time.Hour, true},
+		{"past closing on DST day", berlinDays, at(berlin, 25, 17, 30), time.Hour, false},
+		{"empty schedule", empty, at(time.UTC, 18, 3, 0), 8 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := Interval{Start: tt.start, End: tt.start.Add(tt.d)}
+			if got := tt.schedule.Contains(i); got != tt.want {
+				t.Fatalf("Contains(%v - %v) = %v, want %v", i.Start, i.End, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlots(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	tests := []struct {
+		name         string
+		availability []string
+		date         string
+		d            time.Duration
+		wantCount    int
+		wantFirst    time.Time
+		wantLast     time.Time
+	}{
+		{
+			name:         "whole slots only",
+			availability: []string{"mon 09:00-12:30"},
+			date:         "2026-10-19",
+			d:            time.Hour,
+			wantCount:    3,
+			wantFirst:    time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+			wantLast:     time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "closed day",
+			availability: []string{"mon 09:00-12:00"},
+			date:         "2026-10-20",
+			d:            time.Hour,
+		},
+		{
+			name:         "two windows in order",
+			availability: []string{"mon 14:00-16:00", "mon 09:00-11:00"},
+			date:         "2026-10-19",
+			d:            time.Hour,
+			wantCount:    4,
+			wantFirst:    time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+			wantLast:     time.Date(2026, 10, 19, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "DST day keeps wall clock hours",
+			availability: []string{"sun 09:00-18:00 Europe/Berlin"},
+			date:         "2026-10-25",
+			d:            time.Hour,
+			wantCount:    9,
+			wantFirst:    time.Date(2026, 10, 25, 9, 0, 0, 0, berlin),
+			wantLast:     time.Date(2026, 10, 25, 17, 0, 0, 0, berlin),
+		},
+		{
+			name:      "empty schedule is open all day",
+			date:      "2026-10-19",
+			d:         2 * time.Hour,
+			wantCount: 12,
+			wantFirst: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			wantLast:  time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.availability, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			slots, err := s.Slots(tt.date, tt.d)
+			if err != nil {
+				t.Fatalf("Slots(%q, %v) failed: %v", tt.date, tt.d, err)
+			}
+			if len(slots) != tt.wantCount {
+				t.Fatalf("Slots(%q, %v) = %d slots, want %d", tt.date, tt.d, len(slots), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+
+			if first := slots[0].Start; !first.Equal(tt.wantFirst) {
+				t.Errorf("first slot starts at %v, want %v", first, tt.wantFirst)
+			}
+			if last := slots[len(slots)-1].Start; !last.Equal(tt.wantLast) {
+				t.Errorf("last slot starts at %v, want %v", last, tt.wantLast)
+			}
+			for _, slot := range slots {
+				if slot.End.Sub(slot.Start) != tt.d {
+					t.Errorf("slot %v - %v is not %v long", slot.Start, slot.End, tt.d)
+				}
+			}
+		})
+	}
+}
+
+func TestSlotsInvalid(t *testing.T) {
+	s, err := Parse([]string{"mon 09:00-18:00"}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Slots("2026-10-19", 0); err == nil {
+		t.Error("Slots with a zero length succeeded")
+	}
+	if _, err := s.Slots("19.10.2026", time.Hour); err == nil {
+		t.Error("Slots with an invalid date succeeded")
+	}
+}
+
+func TestFree(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2026, 10, 19, hour, 0, 0, 0, time.UTC)
+	}
+	slots := []Interval{
+		{Start: at(9), End: at(10)},
+		{Start: at(10), End: at(11)},
+		{Start: at(11), End: at(12)},
+	}
+
+	tests := []struct {
+		name string
+		busy []Interval
+		want int
+	}{
+		{"nothing busy", nil, 3},
+		{"touching ends do not overlap", []Interval{{Start: at(8), End: at(9)}, {Start: at(12), End: at(13)}}, 3},
+		{"one slot taken", []Interval{{Start: at(10), End: at(11)}}, 2},
+		{"across two slots", []Interval{{Start: at(9).Add(30 * time.Minute), End: at(10).Add(30 * time.Minute)}}, 1},
+		{"all taken", []Interval{{Start: at(8), End: at(13)}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Free(slots, tt.busy); len(got) != tt.want {
+				t.Fatalf("Free = %d slots, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package stream
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a message pushed to the users it concerns.
+type Event struct {
+	ID    string
+	Type  string
+	Data  json.RawMessage
+	seq   uint64
+	users []string
+}
+
+func (e Event) isFor(userID string) bool {
+	for _, u := range e.users {
+		if u == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Hub fans events out to the subscriptions of the users they concern and
+// keeps the latest of them, so that a client that reconnects can resume
+// where it stopped.
+//
+// Event IDs are made of the start time of the hub and a sequence number.
+// An ID of another hub, e.g. from before a restart, cannot be resumed
+// from, and the subscription reports the events as missed.
+type Hub struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	history []Event
+	next    int
+	subs    map[*Subscription]bool
+}
+
+func NewHub(historySize int) *Hub {
+	return &Hub{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		history: make([]Event, 0, max(historySize, 1)),
+		subs:    make(map[*Subscription]bool),
+	}
+}
+
+// Subscription receives the events of one user. When the client falls
+// behind and its buffer fills up, the hub stops sending to it and closes
+// Events rather than holding up the other subscriptions.
+type Subscription struct {
+	UserID string
+	// Missed is set when the events after the resumed ID are no longer
+	// all kept, so the client has to refetch its state.
+	Missed bool
+
+	events  chan Event
+	dropped bool
+}
+
+// Events is closed when the subscription is dropped for falling behind.
+func (s *Subscription) Events() <-chan Event { return s.events }
+
+// Publish assigns the event an ID, keeps it and sends it to the current
+// subscriptions of users.
+func (h *Hub) Publish(typ string, data json.RawMessage, users []string) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	e := Event{
+		ID:    h.epoch + "-" + strconv.FormatUint(h.seq, 10),
+		Type:  typ,
+		Data:  data,
+		seq:   h.seq,
+		users: users,
+	}
+
+	if len(h.history) < cap(h.history) {
+		h.history = append(h.history, e)
+	} else {
+		h.history[h.next] = e
+		h.next = (h.next + 1) % len(h.history)
+	}
+
+	for s := range h.subs {
+		if !e.isFor(s.UserID) {
+			continue
+		}
+
+		select {
+		case s.events <- e:
+		default:
+			h.drop(s)
+		}
+	}
+
+	return e
+}
+
+// Subscribe registers a subscription for userID with room for buffer
+// pending events. With a lastEventID, the kept events of the user that
+// followed it are queued first.
+func (h *Hub) Subscribe(userID, lastEventID string, buffer int) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var replay []Event
+	missed := false
+
+	if lastEventID != "" {
+		replay, missed = h.since(userID, lastEventID)
+	}
+
+	s := &Subscription{
+		UserID: userID,
+		Missed: missed,
+		events: make(chan Event, max(buffer, 1)+len(replay)),
+	}
+	for _, e := range replay {
+		s.events <- e
+	}
+
+	h.subs[s] = true
+	return s
+}
+
+// Unsubscribe stops sending to s. It is safe to call on a dropped
+// subscription.
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.drop(s)
+}
+
+func (h *Hub) drop(s *Subscription) {
+	if s.dropped {
+		return
+	}
+
+	s.dropped = true
+	delete(h.subs, s)
+	close(s.events)
+}
+
+// since returns the kept events of userID that followed the event with
+// the given ID, and whether some of them may have been dropped already.
+func (h *Hub) since(userID, id string) ([]Event, bool) {
+	seq, ok := h.parseID(id)
+	if !ok || seq > h.seq {
+		return nil, true
+	}
+
+	ordered := append(append([]Event{}, h.history[h.next:]...), h.history[:h.next]...)
+
+	missed := len(ordered) > 0 && ordered[0].seq > seq+1
+
+	var events []Event
+	for _, e := range ordered {
+		if e.seq > seq && e.isFor(userID) {
+			events = append(events, e)
+		}
+	}
+
+	return events, missed
+}
+
+func (h *Hub) parseID(id string) (uint64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != h.epoch {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
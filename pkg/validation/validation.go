@@ -0,0 +1,188 @@
+package validation
+
+import (
+	"api-gateway/pkg/outbound"
+	"api-gateway/pkg/schedule"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+type structValidator struct {
+	once     sync.Once
+	validate *validator.Validate
+}
+
+// New returns a gin struct validator that reads the `validate` tags
+// of request models instead of gin's default `binding` tags.
+func New() binding.StructValidator {
+	return &structValidator{}
+}
+
+func (v *structValidator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.Elem().Kind() != reflect.Struct {
+			return v.ValidateStruct(value.Elem().Interface())
+		}
+		return v.validateStruct(obj)
+	case reflect.Struct:
+		return v.validateStruct(obj)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := v.ValidateStruct(value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (v *structValidator) Engine() any {
+	v.lazyinit()
+	return v.validate
+}
+
+func (v *structValidator) validateStruct(obj any) error {
+	v.lazyinit()
+	return v.validate.Struct(obj)
+}
+
+func (v *structValidator) lazyinit() {
+	v.once.Do(func() {
+		v.validate = validator.New(validator.WithRequiredStructEnabled())
+		v.validate.SetTagName("validate")
+		v.validate.RegisterTagNameFunc(jsonName)
+
+		v.validate.RegisterValidation("rfc3339", isRFC3339)
+		v.validate.RegisterValidation("future", isFuture)
+		v.validate.RegisterValidation("availability", isAvailability)
+		v.validate.RegisterValidation("publicurl", isPublicURL)
+	})
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func isRFC3339(fl validator.FieldLevel) bool {
+	_, err := time.Parse(time.RFC3339, fl.Field().String())
+	return err == nil
+}
+
+func isFuture(fl validator.FieldLevel) bool {
+	t, err := time.Parse(time.RFC3339, fl.Field().String())
+	if err != nil {
+		return false
+	}
+	return t.After(time.Now())
+}
+
+func isAvailability(fl validator.FieldLevel) bool {
+	_, err := schedule.ParseEntry(fl.Field().String(), time.UTC)
+	return err == nil
+}
+
+// isPublicURL rejects URLs whose host is an address or name that is
+// not on the public internet. Names are checked again when connecting,
+// after they are resolved.
+func isPublicURL(fl validator.FieldLevel) bool {
+	u, err := url.Parse(fl.Field().String())
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return outbound.IsPublic(addr)
+	}
+
+	return true
+}
+
+// Fields converts validation errors into a map of json field paths
+// to human readable messages. It returns nil for any other error.
+func Fields(err error) map[string]string {
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil
+	}
+
+	fields := make(map[string]string, len(errs))
+	for _, e := range errs {
+		fields[fieldPath(e.Namespace())] = message(e)
+	}
+
+	return fields
+}
+
+func fieldPath(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
+	}
+	return path
+}
+
+func message(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "e164":
+		return "must be a phone number in E.164 format, e.g. +998901234567"
+	case "latitude":
+		return "must be a latitude between -90 and 90"
+	case "longitude":
+		return "must be a longitude between -180 and 180"
+	case "rfc3339":
+		return "must be a timestamp in RFC3339 format"
+	case "future":
+		return "must be in the future"
+	case "availability":
+		return "must look like \"mon-fri 09:00-18:00 [time zone]\""
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", e.Param())
+	case "gte", "min":
+		return fmt.Sprintf("must be at least %s", e.Param())
+	case "lte", "max":
+		return fmt.Sprintf("must be at most %s", e.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", e.Param())
+	case "publicurl":
+		return "must point to a public host"
+	case "startswith":
+		return fmt.Sprintf("must start with %q", e.Param())
+	default:
+		return fmt.Sprintf("failed on the '%s' rule", e.Tag())
+	}
+}
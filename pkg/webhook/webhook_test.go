@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+
+	// The HMAC-SHA256 of `1760864400.{"id":"1"}` keyed with whsec_test.
+	want := "v1=65333b42ad2189dd5c050b04a453f4549cd45c0bb9fb48b27005dc99de970dcf"
+	if got := Sign("whsec_test", 1760864400, body); got != want {
+		t.Fatalf("Sign = %q, want %q", got, want)
+	}
+
+	if Sign("whsec_other", 1760864400, body) == want {
+		t.Error("Sign ignores the secret")
+	}
+	if Sign("whsec_test", 1760864401, body) == want {
+		t.Error("Sign ignores the timestamp")
+	}
+	if Sign("whsec_test", 1760864400, []byte(`{"id":"2"}`)) == want {
+		t.Error("Sign ignores the body")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"id":"1"}`)
+	now := time.Unix(1760864400, 0)
+	signature := Sign(secret, now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		wantErr   bool
+	}{
+		{"valid", secret, "1760864400", signature, body, false},
+		{"within tolerance", secret, "1760864100", Sign(secret, 1760864100, body), body, false},
+		{"signed in the near future", secret, "1760864700", Sign(secret, 1760864700, body), body, false},
+		{"replayed later", secret, "1760863800", Sign(secret, 1760863800, body), body, true},
+		{"too far ahead", secret, "1760865000", Sign(secret, 1760865000, body), body, true},
+		{"invalid timestamp", secret, "yesterday", signature, body, true},
+		{"timestamp changed", secret, "1760864401", signature, body, true},
+		{"body changed", secret, "1760864400", signature, []byte(`{"id":"2"}`), true},
+		{"wrong secret", "whsec_other", "1760864400", signature, body, true},
+		{"missing version", secret, "1760864400", strings.TrimPrefix(signature, "v1="), body, true},
+		{"other version", secret, "1760864400", "v2=" + strings.TrimPrefix(signature, "v1="), body, true},
+		{"empty signature", secret, "1760864400", "", body, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, 5*time.Minute, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	const secret = "whsec_test"
+	d := Delivery{ID: "d1", Event: "booking.created", CreatedAt: "2026-10-19T09:00:00Z", Data: json.RawMessage(`{"id":"b1"}`)}
+
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+		wantErr    bool
+	}{
+		{"accepted", http.StatusOK, http.StatusOK, false},
+		{"no content", http.StatusNoContent, http.StatusNoContent, false},
+		{"rejected", http.StatusBadRequest, http.StatusBadRequest, true},
+		{"redirected", http.StatusFound, http.StatusFound, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verifyErr error
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				verifyErr = Verify(secret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute, time.Now())
+				if r.Header.Get(HeaderID) != d.ID || r.Header.Get(HeaderEvent) != d.Event {
+					t.Errorf("delivery headers = %v", r.Header)
+				}
+
+				w.Header().Set("Location", "/elsewhere")
+				w.WriteHeader(tt.status)
+				io.WriteString(w, "internal details of the receiver")
+			}))
+			defer srv.Close()
+
+			client := srv.Client()
+			client.CheckRedirect = func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+
+			status, err := (&Sender{Client: client}).Send(context.Background(), srv.URL, secret, d)
+			if verifyErr != nil {
+				t.Fatalf("receiver could not verify the delivery: %v", verifyErr)
+			}
+			if status != tt.wantStatus || (err != nil) != tt.wantErr {
+				t.Fatalf("Send = %d, %v, want %d and error %v", status, err, tt.wantStatus, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "internal details") {
+				t.Errorf("Send error %q carries the response body", err)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{6, 30 * time.Minute},
+		{100, 30 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts, time.Minute, 30*time.Minute); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// InvoiceNumber is the number a booking's invoice was issued with.
+type InvoiceNumber struct {
+	Number   int64
+	IssuedAt time.Time
+}
+
+// IssueInvoice returns the number of the booking's invoice, issuing the
+// provider's next one the first time. Numbers of a provider have no gaps:
+// the sequence row is locked until the invoice is stored.
+func (s *Storage) IssueInvoice(ctx context.Context, bookingID, providerID string) (*InvoiceNumber, error) {
+	n, err := s.getInvoice(ctx, bookingID)
+	if err != sql.ErrNoRows {
+		return n, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var number int64
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO invoice_sequences (provider_id, last_number) VALUES ($1, 1)
+		ON CONFLICT (provider_id) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number`,
+		providerID,
+	).Scan(&number)
+	if err != nil {
+		return nil, err
+	}
+
+	n = &InvoiceNumber{Number: number}
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO invoices (booking_id, provider_id, number) VALUES ($1, $2, $3)
+		ON CONFLICT (booking_id) DO NOTHING
+		RETURNING issued_at`,
+		bookingID, providerID, number,
+	).Scan(&n.IssuedAt)
+	if err == sql.ErrNoRows {
+		// Another request issued it first; the number is given back.
+		tx.Rollback()
+		return s.getInvoice(ctx, bookingID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return n, tx.Commit()
+}
+
+func (s *Storage) getInvoice(ctx context.Context, bookingID string) (*InvoiceNumber, error) {
+	var n InvoiceNumber
+	err := s.db.QueryRowContext(ctx,
+		`SELECT number, issued_at FROM invoices WHERE booking_id = $1`,
+		bookingID,
+	).Scan(&n.Number, &n.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
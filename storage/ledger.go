@@ -0,0 +1,334 @@
+package storage
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/ledger"
+	"api-gateway/pkg/payment"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetCommissionRate returns the commission rate set for a provider, or
+// ErrNotFound when the default applies.
+func (s *Storage) GetCommissionRate(ctx context.Context, providerID string) (float64, error) {
+	var rate float64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT rate FROM provider_commissions WHERE provider_id = $1`,
+		providerID,
+	).Scan(&rate)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	return rate, err
+}
+
+// SetCommissionRate sets the commission rate of a provider. Payments
+// posted before keep the rate they were posted with.
+func (s *Storage) SetCommissionRate(ctx context.Context, providerID string, rate float64, updatedBy string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO provider_commissions (provider_id, rate, updated_by) VALUES ($1, $2, $3)
+		ON CONFLICT (provider_id) DO UPDATE SET rate = $2, updated_by = $3, updated_at = NOW()`,
+		providerID, rate, updatedBy,
+	)
+	return err
+}
+
+// PostLedgerTransaction posts a transaction to the ledger unless one with
+// the same source was posted before, and reports whether it was posted.
+func (s *Storage) PostLedgerTransaction(ctx context.Context, t *ledger.Transaction) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	id, err := postTransaction(ctx, tx, t)
+	if err != nil || id == "" {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// postTransaction inserts a transaction and its entries, returning its
+// ID, or an empty ID when its source was posted before.
+func postTransaction(ctx context.Context, tx *sql.Tx, t *ledger.Transaction) (string, error) {
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+
+	id := uuid.NewString()
+	err := tx.QueryRowContext(ctx,
+		`INSERT INTO ledger_transactions
+			(id, source, kind, provider_id, booking_id, payout_batch_id, commission_rate, currency)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, NULLIF($6, '')::uuid, $7, $8)
+		ON CONFLICT (source) DO NOTHING
+		RETURNING id`,
+		id, t.Source, t.Kind, t.ProviderID, t.BookingID, t.PayoutBatchID, t.CommissionRate, t.Currency,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, e := range t.Entries {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO ledger_entries (transaction_id, account, provider_id, amount)
+			VALUES ($1, $2, NULLIF($3, '')::uuid, $4)`,
+			id, e.Account, e.ProviderID, e.Amount,
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+// GetPaymentCommissionRate returns the commission rate the payment of a
+// booking was posted with, or ErrNotFound if it was not posted.
+func (s *Storage) GetPaymentCommissionRate(ctx context.Context, bookingID string) (float64, error) {
+	var rate float64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT commission_rate FROM ledger_transactions
+		WHERE booking_id = $1 AND kind = $2
+		ORDER BY created_at DESC LIMIT 1`,
+		bookingID, ledger.KindPayment,
+	).Scan(&rate)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	return rate, err
+}
+
+// ListBookingRefunds returns the refunds of a booking's payments that the
+// processor accepted and that did not fail.
+func (s *Storage) ListBookingRefunds(ctx context.Context, bookingID string) ([]*models.Refund, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT r.id, r.payment_intent_id, pi.booking_id, r.processor_refund_id, r.amount, pi.currency,
+			r.reason, r.status, r.requested_by, r.created_at, r.updated_at
+		FROM refunds r JOIN payment_intents pi ON pi.id = r.payment_intent_id
+		WHERE pi.booking_id = $1 AND r.processor_refund_id <> '' AND r.status <> $2
+		ORDER BY r.created_at`,
+		bookingID, payment.StatusFailed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []*models.Refund
+	for rows.Next() {
+		var (
+			r      models.Refund
+			amount int64
+		)
+		err := rows.Scan(&r.ID, &r.PaymentIntentID, &r.BookingID, &r.ProcessorRefundID, &amount, &r.Currency,
+			&r.Reason, &r.Status, &r.RequestedBy, &r.CreatedAt, &r.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		r.Amount = payment.FromMinor(amount)
+		refunds = append(refunds, &r)
+	}
+	return refunds, rows.Err()
+}
+
+// ProviderEarnings sums up the ledger of a provider from from to to, by
+// day, week or month in loc. Periods without transactions are left out.
+func (s *Storage) ProviderEarnings(ctx context.Context, providerID, period string, from, to time.Time,
+	loc *time.Location) ([]models.EarningsPeriod, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT date_trunc($2, t.created_at AT TIME ZONE $5) AS start,
+			COALESCE(SUM(e.amount) FILTER (WHERE e.account = $6 AND t.kind = $9), 0),
+			COALESCE(-SUM(e.amount) FILTER (WHERE e.account = $6 AND t.kind = $10), 0),
+			COALESCE(-SUM(e.amount) FILTER (WHERE e.account = $7), 0),
+			COALESCE(-SUM(e.amount) FILTER (WHERE e.account = $8 AND t.kind <> $11), 0),
+			COALESCE(SUM(e.amount) FILTER (WHERE e.account = $8 AND t.kind = $11), 0)
+		FROM ledger_transactions t JOIN ledger_entries e ON e.transaction_id = t.id
+		WHERE t.provider_id = $1 AND t.created_at >= $3 AND t.created_at < $4
+		GROUP BY start
+		ORDER BY start`,
+		providerID, period, from, to, loc.String(),
+		ledger.AccountCash, ledger.AccountCommission, ledger.AccountProviderPayable,
+		ledger.KindPayment, ledger.KindRefund, ledger.KindPayout,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periods := []models.EarningsPeriod{}
+	for rows.Next() {
+		var (
+			start                                     time.Time
+			gross, refunded, commission, net, paidOut int64
+		)
+		if err := rows.Scan(&start, &gross, &refunded, &commission, &net, &paidOut); err != nil {
+			return nil, err
+		}
+
+		periods = append(periods, models.EarningsPeriod{
+			Start:      start.Format(time.DateOnly),
+			Gross:      payment.FromMinor(gross),
+			Refunded:   payment.FromMinor(refunded),
+			Commission: payment.FromMinor(commission),
+			Net:        payment.FromMinor(net),
+			PaidOut:    payment.FromMinor(paidOut),
+		})
+	}
+	return periods, rows.Err()
+}
+
+// ProviderBalance returns what the platform owes a provider. It is
+// negative when refunds came in after the provider was paid out.
+func (s *Storage) ProviderBalance(ctx context.Context, providerID string) (float32, error) {
+	var balance int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(-SUM(amount), 0) FROM ledger_entries WHERE account = $1 AND provider_id = $2`,
+		ledger.AccountProviderPayable, providerID,
+	).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+	return payment.FromMinor(balance), nil
+}
+
+// CreatePayoutBatch pays every provider with a positive balance in the
+// batch's currency, filling in the batch. Batches are created one at a
+// time, so a balance is never paid twice. It returns ErrConflict when
+// nobody is owed anything.
+func (s *Storage) CreatePayoutBatch(ctx context.Context, b *models.PayoutBatch) error {
+	b.ID = uuid.NewString()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('payout_batches'))`); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT e.provider_id, -SUM(e.amount)
+		FROM ledger_entries e JOIN ledger_transactions t ON t.id = e.transaction_id
+		WHERE e.account = $1 AND t.currency = $2
+		GROUP BY e.provider_id
+		HAVING SUM(e.amount) < 0
+		ORDER BY e.provider_id`,
+		ledger.AccountProviderPayable, b.Currency,
+	)
+	if err != nil {
+		return err
+	}
+
+	balances := map[string]int64{}
+	var providerIDs []string
+	for rows.Next() {
+		var (
+			providerID string
+			balance    int64
+		)
+		if err := rows.Scan(&providerID, &balance); err != nil {
+			rows.Close()
+			return err
+		}
+		balances[providerID] = balance
+		providerIDs = append(providerIDs, providerID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(providerIDs) == 0 {
+		return ErrConflict
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO payout_batches (id, currency, created_by) VALUES ($1, $2, $3)
+		RETURNING created_at`,
+		b.ID, b.Currency, b.CreatedBy,
+	).Scan(&b.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	b.Payouts = make([]*models.Payout, 0, len(providerIDs))
+	for _, providerID := range providerIDs {
+		amount := balances[providerID]
+		t := ledger.Payout("payout:"+b.ID+":"+providerID, providerID, b.ID, amount, b.Currency)
+
+		id, err := postTransaction(ctx, tx, &t)
+		if err != nil {
+			return err
+		}
+
+		total += amount
+		b.Payouts = append(b.Payouts, &models.Payout{
+			TransactionID: id,
+			ProviderID:    providerID,
+			Amount:        payment.FromMinor(amount),
+			Currency:      b.Currency,
+		})
+	}
+	b.Total = payment.FromMinor(total)
+
+	return tx.Commit()
+}
+
+// GetPayoutBatch returns a batch with its payouts, or ErrNotFound.
+func (s *Storage) GetPayoutBatch(ctx context.Context, id string) (*models.PayoutBatch, error) {
+	if uuid.Validate(id) != nil {
+		return nil, ErrNotFound
+	}
+
+	b := models.PayoutBatch{ID: id, Payouts: []*models.Payout{}}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT currency, created_by, created_at FROM payout_batches WHERE id = $1`,
+		id,
+	).Scan(&b.Currency, &b.CreatedBy, &b.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.id, t.provider_id, e.amount, t.currency
+		FROM ledger_transactions t JOIN ledger_entries e ON e.transaction_id = t.id
+		WHERE t.payout_batch_id = $1 AND e.account = $2
+		ORDER BY t.provider_id`,
+		id, ledger.AccountProviderPayable,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var total int64
+	for rows.Next() {
+		var (
+			p      models.Payout
+			amount int64
+		)
+		if err := rows.Scan(&p.TransactionID, &p.ProviderID, &amount, &p.Currency); err != nil {
+			return nil, err
+		}
+		p.Amount = payment.FromMinor(amount)
+		total += amount
+		b.Payouts = append(b.Payouts, &p)
+	}
+	b.Total = payment.FromMinor(total)
+
+	return &b, rows.Err()
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"api-gateway/pkg/ledger"
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testStorage connects to the database in TEST_DATABASE_URL and creates
+// the schema in it. The tests write to it, so it should be a database
+// of its own.
+func testStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("error creating tables: %v", err)
+	}
+
+	return &Storage{db: db}
+}
+
+func TestLedgerBalance(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+	providerID := uuid.NewString()
+
+	payment := ledger.Payment("payment:"+uuid.NewString(), providerID, uuid.NewString(), 10000, 0.15, "usd")
+	posted, err := s.PostLedgerTransaction(ctx, &payment)
+	if err != nil || !posted {
+		t.Fatalf("PostLedgerTransaction = %v, %v, want it posted", posted, err)
+	}
+
+	posted, err = s.PostLedgerTransaction(ctx, &payment)
+	if err != nil || posted {
+		t.Fatalf("posting the same source again = %v, %v, want it skipped", posted, err)
+	}
+
+	balance, err := s.ProviderBalance(ctx, providerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 85 {
+		t.Fatalf("ProviderBalance = %v, want 85", balance)
+	}
+
+	// The balance trigger catches what gets past Transaction.Validate.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	id := uuid.NewString()
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO ledger_transactions (id, source, kind, provider_id, currency)
+		VALUES ($1, $2, 'payment', $3, 'usd')`,
+		id, "test:"+id, providerID,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, amount := range []int64{10000, -9999} {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO ledger_entries (transaction_id, account, amount) VALUES ($1, 'cash', $2)`,
+			id, amount,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tx.Commit(); err == nil {
+		t.Fatal("committed a transaction that does not balance")
+	}
+}
+
+func TestLedgerImmutable(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	payment := ledger.Payment("payment:"+uuid.NewString(), uuid.NewString(), uuid.NewString(), 10000, 0.15, "usd")
+	if _, err := s.PostLedgerTransaction(ctx, &payment); err != nil {
+		t.Fatal(err)
+	}
+
+	statements := []string{
+		`UPDATE ledger_transactions SET currency = 'eur' WHERE source = $1`,
+		`DELETE FROM ledger_transactions WHERE source = $1`,
+		`UPDATE ledger_entries SET amount = amount * 2
+		WHERE transaction_id = (SELECT id FROM ledger_transactions WHERE source = $1)`,
+		`DELETE FROM ledger_entries
+		WHERE transaction_id = (SELECT id FROM ledger_transactions WHERE source = $1)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := s.db.ExecContext(ctx, stmt, payment.Source); err == nil {
+			t.Errorf("%s succeeded, want the ledger to refuse it", stmt)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"api-gateway/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// GetPreferences returns the delivery preferences of the user, or
+// ErrNotFound if they never set any.
+func (s *Storage) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	var (
+		data      []byte
+		updatedAt string
+	)
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT preferences, updated_at FROM notification_preferences WHERE user_id = $1`,
+		userID,
+	).Scan(&data, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var prefs models.NotificationPreferences
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, errors.Wrap(err, "invalid stored preferences")
+	}
+	prefs.UpdatedAt = updatedAt
+
+	return &prefs, nil
+}
+
+func (s *Storage) SetPreferences(ctx context.Context, userID string, prefs *models.NotificationPreferences) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO notification_preferences (user_id, preferences, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET preferences = EXCLUDED.preferences, updated_at = NOW()`,
+		userID, data,
+	)
+	return err
+}
+
+// LogDelivery records a delivery attempt, filling in its ID and time.
+func (s *Storage) LogDelivery(ctx context.Context, log *models.DeliveryLog) error {
+	log.ID = uuid.NewString()
+
+	return s.db.QueryRowContext(ctx,
+		`INSERT INTO notification_deliveries (id, user_id, type, channel, status, error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at`,
+		log.ID, log.UserID, log.Type, log.Channel, log.Status, log.Error,
+	).Scan(&log.CreatedAt)
+}
+
+// ListDeliveries returns the delivery attempts of the user, newest first.
+func (s *Storage) ListDeliveries(ctx context.Context, userID string, page, limit int32) ([]*models.DeliveryLog, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, type, channel, status, error, created_at
+		FROM notification_deliveries
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset(page, limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*models.DeliveryLog
+	for rows.Next() {
+		var l models.DeliveryLog
+		if err := rows.Scan(&l.ID, &l.UserID, &l.Type, &l.Channel, &l.Status, &l.Error, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs = append(logs, &l)
+	}
+
+	return logs, rows.Err()
+}
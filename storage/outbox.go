@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+)
+
+// OutboxMessage is a Kafka message saved in the same transaction as the
+// change it announces.
+type OutboxMessage struct {
+	Topic   string
+	Message []byte
+}
+
+func insertOutbox(ctx context.Context, tx *sql.Tx, out []OutboxMessage) error {
+	for _, m := range out {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO outbox (topic, message) VALUES ($1, $2)`,
+			m.Topic, m.Message,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PublishOutbox publishes up to limit unpublished messages in the order
+// they were saved, and returns how many it published. It stops at the
+// first message publish fails for, which is tried again next time. One
+// instance publishes at a time, so the order holds; the others publish
+// nothing meanwhile. Delivery is at least once: a message is published
+// again if marking it fails.
+func (s *Storage) PublishOutbox(ctx context.Context, limit int, publish func(topic string, message []byte) error) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	err = tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('outbox'))`).Scan(&locked)
+	if err != nil || !locked {
+		return 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, topic, message FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	type pending struct {
+		id int64
+		OutboxMessage
+	}
+	var messages []pending
+	for rows.Next() {
+		var m pending
+		if err := rows.Scan(&m.id, &m.Topic, &m.Message); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		messages = append(messages, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var publishErr error
+	for _, m := range messages {
+		if publishErr = publish(m.Topic, m.Message); publishErr != nil {
+			break
+		}
+
+		_, err := tx.ExecContext(ctx, `UPDATE outbox SET published_at = NOW() WHERE id = $1`, m.id)
+		if err != nil {
+			return 0, err
+		}
+		published++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
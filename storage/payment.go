@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/payment"
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Refunds that did not fail count as refunded, including pending ones.
+const intentColumns = `id, booking_id, user_id, processor, processor_intent_id, amount,
+	(SELECT COALESCE(SUM(r.amount), 0) FROM refunds r
+		WHERE r.payment_intent_id = payment_intents.id AND r.status <> 'failed'),
+	currency, status, payment_method, failure_message, created_at, updated_at`
+
+func scanIntent(row interface{ Scan(...any) error }) (*models.PaymentIntent, error) {
+	var (
+		pi               models.PaymentIntent
+		amount, refunded int64
+	)
+
+	err := row.Scan(&pi.ID, &pi.BookingID, &pi.UserID, &pi.Processor, &pi.ProcessorIntentID, &amount, &refunded,
+		&pi.Currency, &pi.Status, &pi.PaymentMethod, &pi.FailureMessage, &pi.CreatedAt, &pi.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pi.Amount = payment.FromMinor(amount)
+	pi.AmountRefunded = payment.FromMinor(refunded)
+	return &pi, nil
+}
+
+// CreatePaymentIntent stores an intent, filling in its times. A booking
+// has one intent that is not failed or canceled at most; another one is
+// an ErrConflict.
+func (s *Storage) CreatePaymentIntent(ctx context.Context, pi *models.PaymentIntent) error {
+	if pi.ID == "" {
+		pi.ID = uuid.NewString()
+	}
+
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO payment_intents (id, booking_id, user_id, processor, processor_intent_id, amount, currency, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at, updated_at`,
+		pi.ID, pi.BookingID, pi.UserID, pi.Processor, pi.ProcessorIntentID,
+		payment.ToMinor(pi.Amount), pi.Currency, pi.Status,
+	).Scan(&pi.CreatedAt, &pi.UpdatedAt)
+
+	if e, ok := err.(*pq.Error); ok && e.Code.Name() == "unique_violation" {
+		return ErrConflict
+	}
+	return err
+}
+
+// GetPaymentIntent returns an intent, or ErrNotFound.
+func (s *Storage) GetPaymentIntent(ctx context.Context, id string) (*models.PaymentIntent, error) {
+	if uuid.Validate(id) != nil {
+		return nil, ErrNotFound
+	}
+
+	return scanIntent(s.db.QueryRowContext(ctx,
+		`SELECT `+intentColumns+` FROM payment_intents WHERE id = $1`,
+		id,
+	))
+}
+
+// GetActivePaymentIntent returns the intent of a booking that is not
+// failed or canceled, or ErrNotFound.
+func (s *Storage) GetActivePaymentIntent(ctx context.Context, bookingID string) (*models.PaymentIntent, error) {
+	return scanIntent(s.db.QueryRowContext(ctx,
+		`SELECT `+intentColumns+` FROM payment_intents
+		WHERE booking_id = $1 AND status IN ($2, $3, $4)`,
+		bookingID, payment.StatusPending, payment.StatusProcessing, payment.StatusSucceeded,
+	))
+}
+
+// GetPaymentIntentByProcessorID returns the intent the processor knows
+// by the given ID, or ErrNotFound.
+func (s *Storage) GetPaymentIntentByProcessorID(ctx context.Context, processor, processorIntentID string) (*models.PaymentIntent, error) {
+	return scanIntent(s.db.QueryRowContext(ctx,
+		`SELECT `+intentColumns+` FROM payment_intents WHERE processor = $1 AND processor_intent_id = $2`,
+		processor, processorIntentID,
+	))
+}
+
+// UpdatePaymentIntent saves the status, payment method and failure of an
+// intent whose status was from, with the outbox messages that announce
+// it. If the status changed in the meantime, nothing is saved and
+// ErrConflict is returned.
+func (s *Storage) UpdatePaymentIntent(ctx context.Context, pi *models.PaymentIntent, from string, out ...OutboxMessage) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`UPDATE payment_intents SET status = $2, payment_method = $3, failure_message = $4, updated_at = NOW()
+		WHERE id = $1 AND status = $5
+		RETURNING updated_at`,
+		pi.ID, pi.Status, pi.PaymentMethod, pi.FailureMessage, from,
+	).Scan(&pi.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertOutbox(ctx, tx, out); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RecordPaymentEvent notes that a webhook event of the processor was
+// received, and reports false if it was already.
+func (s *Storage) RecordPaymentEvent(ctx context.Context, processor, eventID, eventType string) (bool, error) {
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO payment_events (processor, event_id, type) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`,
+		processor, eventID, eventType,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ForgetPaymentEvent drops a received event that could not be handled,
+// so that it is handled when the processor sends it again.
+func (s *Storage) ForgetPaymentEvent(ctx context.Context, processor, eventID string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM payment_events WHERE processor = $1 AND event_id = $2`,
+		processor, eventID,
+	)
+	return err
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"api-gateway/models"
+	"api-gateway/pkg/payment"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateRefund stores a pending refund, filling in its ID and times. The
+// refunds of the intent that did not fail may add up to limit at most;
+// past it nothing is stored and ErrConflict is returned. The intent is
+// locked meanwhile, so concurrent refunds cannot overrun the limit.
+func (s *Storage) CreateRefund(ctx context.Context, r *models.Refund, limit float32) error {
+	if r.ID == "" {
+		r.ID = uuid.NewString()
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var refunded int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT (SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE payment_intent_id = $1 AND status <> $2)
+		FROM payment_intents WHERE id = $1
+		FOR UPDATE`,
+		r.PaymentIntentID, payment.StatusFailed,
+	).Scan(&refunded)
+	if err != nil {
+		return err
+	}
+
+	amount := payment.ToMinor(r.Amount)
+	if amount <= 0 || refunded+amount > payment.ToMinor(limit) {
+		return ErrConflict
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO refunds (id, payment_intent_id, amount, reason, status, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at`,
+		r.ID, r.PaymentIntentID, amount, r.Reason, r.Status, r.RequestedBy,
+	).Scan(&r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateRefund saves the status of a refund and the ID the processor
+// gave it.
+func (s *Storage) UpdateRefund(ctx context.Context, r *models.Refund) error {
+	return s.db.QueryRowContext(ctx,
+		`UPDATE refunds SET status = $2, processor_refund_id = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		r.ID, r.Status, r.ProcessorRefundID,
+	).Scan(&r.UpdatedAt)
+}
+
+// Cancellation is who cancelled a booking and when.
+type Cancellation struct {
+	UserID      string
+	Role        string
+	CancelledAt time.Time
+}
+
+// RecordCancellation notes who cancelled a booking, now. The refunds of
+// the booking are worked out from it later.
+func (s *Storage) RecordCancellation(ctx context.Context, bookingID, userID, role string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO booking_cancellations (booking_id, cancelled_by, role) VALUES ($1, $2, $3)
+		ON CONFLICT (booking_id) DO UPDATE SET cancelled_by = $2, role = $3, cancelled_at = NOW()`,
+		bookingID, userID, role,
+	)
+	return err
+}
+
+// GetCancellation returns the cancellation of a booking, or ErrNotFound.
+func (s *Storage) GetCancellation(ctx context.Context, bookingID string) (*Cancellation, error) {
+	var c Cancellation
+	err := s.db.QueryRowContext(ctx,
+		`SELECT cancelled_by, role, cancelled_at FROM booking_cancellations WHERE booking_id = $1`,
+		bookingID,
+	).Scan(&c.UserID, &c.Role, &c.CancelledAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// Reminder statuses.
+const (
+	ReminderPending   = "pending"
+	ReminderSent      = "sent"
+	ReminderCancelled = "cancelled"
+)
+
+// Reminder is a notification due some time before a booking.
+type Reminder struct {
+	BookingID     string
+	Offset        time.Duration
+	ScheduledTime time.Time
+	SendAt        time.Time
+}
+
+// ScheduleReminders sets the reminders of a booking for its scheduled
+// time. Reminders for another time are dropped, while those already set
+// for this time are kept as they are, sent or cancelled, so scheduling
+// the same booking again sends nothing twice. Offsets whose time has
+// passed are skipped.
+func (s *Storage) ScheduleReminders(ctx context.Context, bookingID string, scheduledTime time.Time, offsets []time.Duration) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM booking_reminders WHERE booking_id = $1 AND scheduled_time <> $2`,
+		bookingID, scheduledTime,
+	)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, offset := range offsets {
+		sendAt := scheduledTime.Add(-offset)
+		if !sendAt.After(now) {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO booking_reminders (booking_id, offset_minutes, scheduled_time, send_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (booking_id, offset_minutes) DO NOTHING`,
+			bookingID, int(offset.Minutes()), scheduledTime, sendAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CancelReminders cancels the pending reminders of a booking.
+func (s *Storage) CancelReminders(ctx context.Context, bookingID string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE booking_reminders SET status = $2 WHERE booking_id = $1 AND status = $3`,
+		bookingID, ReminderCancelled, ReminderPending,
+	)
+	return err
+}
+
+// ClaimDueReminders takes up to limit pending reminders that are due for
+// a booking still ahead. A claimed reminder is not handed out again until
+// the lease runs out, so instances polling together never get the same
+// one, and one whose instance died before finishing it is retried.
+func (s *Storage) ClaimDueReminders(ctx context.Context, limit int, lease time.Duration) ([]*Reminder, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`UPDATE booking_reminders r SET claimed_until = NOW() + make_interval(secs => $3)
+		FROM (
+			SELECT booking_id, offset_minutes FROM booking_reminders
+			WHERE status = $1 AND send_at <= NOW() AND scheduled_time > NOW()
+				AND (claimed_until IS NULL OR claimed_until < NOW())
+			ORDER BY send_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE r.booking_id = due.booking_id AND r.offset_minutes = due.offset_minutes
+		RETURNING r.booking_id, r.offset_minutes, r.scheduled_time, r.send_at`,
+		ReminderPending, limit, lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*Reminder
+	for rows.Next() {
+		var (
+			r       Reminder
+			minutes int
+		)
+		if err := rows.Scan(&r.BookingID, &minutes, &r.ScheduledTime, &r.SendAt); err != nil {
+			return nil, err
+		}
+		r.Offset = time.Duration(minutes) * time.Minute
+		reminders = append(reminders, &r)
+	}
+
+	return reminders, rows.Err()
+}
+
+// FinishReminder marks a claimed reminder as sent or cancelled.
+func (s *Storage) FinishReminder(ctx context.Context, r *Reminder, status string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE booking_reminders SET status = $3, claimed_until = NULL,
+			sent_at = CASE WHEN $3 = $4 THEN NOW() ELSE sent_at END
+		WHERE booking_id = $1 AND offset_minutes = $2 AND status = $5`,
+		r.BookingID, int(r.Offset.Minutes()), status, ReminderSent, ReminderPending,
+	)
+	return err
+}
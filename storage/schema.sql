@@ -0,0 +1,241 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id     UUID PRIMARY KEY,
+    preferences JSONB NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id         UUID PRIMARY KEY,
+    user_id    UUID NOT NULL,
+    type       TEXT NOT NULL,
+    channel    TEXT NOT NULL,
+    status     TEXT NOT NULL,
+    error      TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS notification_deliveries_user_id_idx
+    ON notification_deliveries (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS booking_reminders (
+    booking_id     UUID NOT NULL,
+    offset_minutes INT NOT NULL,
+    scheduled_time TIMESTAMPTZ NOT NULL,
+    send_at        TIMESTAMPTZ NOT NULL,
+    status         TEXT NOT NULL DEFAULT 'pending',
+    claimed_until  TIMESTAMPTZ,
+    sent_at        TIMESTAMPTZ,
+    PRIMARY KEY (booking_id, offset_minutes)
+);
+
+CREATE INDEX IF NOT EXISTS booking_reminders_due_idx
+    ON booking_reminders (send_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id         UUID PRIMARY KEY,
+    user_id    UUID NOT NULL,
+    user_role  TEXT NOT NULL,
+    url        TEXT NOT NULL,
+    events     TEXT[] NOT NULL,
+    secret     TEXT NOT NULL,
+    active     BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_user_id_idx
+    ON webhooks (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              UUID PRIMARY KEY,
+    webhook_id      UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event           TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    claimed_until   TIMESTAMPTZ,
+    redelivery_of   UUID,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx
+    ON webhook_deliveries (webhook_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx
+    ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+    id              BIGSERIAL PRIMARY KEY,
+    delivery_id     UUID NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+    response_status INT NOT NULL DEFAULT 0,
+    error           TEXT NOT NULL DEFAULT '',
+    duration_ms     BIGINT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_attempts_delivery_id_idx
+    ON webhook_attempts (delivery_id, created_at);
+
+CREATE TABLE IF NOT EXISTS payment_intents (
+    id                  UUID PRIMARY KEY,
+    booking_id          UUID NOT NULL,
+    user_id             UUID NOT NULL,
+    processor           TEXT NOT NULL,
+    processor_intent_id TEXT NOT NULL,
+    amount              BIGINT NOT NULL,
+    currency            TEXT NOT NULL,
+    status              TEXT NOT NULL,
+    payment_method      TEXT NOT NULL DEFAULT '',
+    failure_message     TEXT NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (processor, processor_intent_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payment_intents_active_booking_idx
+    ON payment_intents (booking_id) WHERE status IN ('pending', 'processing', 'succeeded');
+
+CREATE TABLE IF NOT EXISTS payment_events (
+    processor   TEXT NOT NULL,
+    event_id    TEXT NOT NULL,
+    type        TEXT NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (processor, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id                  UUID PRIMARY KEY,
+    payment_intent_id   UUID NOT NULL REFERENCES payment_intents (id),
+    processor_refund_id TEXT NOT NULL DEFAULT '',
+    amount              BIGINT NOT NULL,
+    reason              TEXT NOT NULL,
+    status              TEXT NOT NULL,
+    requested_by        UUID NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS refunds_payment_intent_idx ON refunds (payment_intent_id);
+
+CREATE TABLE IF NOT EXISTS booking_cancellations (
+    booking_id   UUID PRIMARY KEY,
+    cancelled_by UUID NOT NULL,
+    role         TEXT NOT NULL,
+    cancelled_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    provider_id UUID PRIMARY KEY,
+    last_number BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    booking_id  UUID PRIMARY KEY,
+    provider_id UUID NOT NULL,
+    number      BIGINT NOT NULL,
+    issued_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider_id, number)
+);
+
+CREATE TABLE IF NOT EXISTS provider_commissions (
+    provider_id UUID PRIMARY KEY,
+    rate        NUMERIC(5, 4) NOT NULL CHECK (rate >= 0 AND rate <= 1),
+    updated_by  UUID NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id         UUID PRIMARY KEY,
+    currency   TEXT NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The ledger is double-entry: the entries of a transaction add up to
+-- zero, debits positive and credits negative, in minor units. Posted
+-- transactions are never changed; corrections are posted as new ones.
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id              UUID PRIMARY KEY,
+    source          TEXT NOT NULL UNIQUE,
+    kind            TEXT NOT NULL,
+    provider_id     UUID NOT NULL,
+    booking_id      UUID,
+    payout_batch_id UUID REFERENCES payout_batches (id),
+    commission_rate NUMERIC(5, 4) NOT NULL DEFAULT 0,
+    currency        TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS ledger_transactions_provider_idx ON ledger_transactions (provider_id, created_at);
+CREATE INDEX IF NOT EXISTS ledger_transactions_booking_idx ON ledger_transactions (booking_id);
+CREATE INDEX IF NOT EXISTS ledger_transactions_payout_batch_idx ON ledger_transactions (payout_batch_id);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id             BIGSERIAL PRIMARY KEY,
+    transaction_id UUID NOT NULL REFERENCES ledger_transactions (id),
+    account        TEXT NOT NULL,
+    provider_id    UUID,
+    amount         BIGINT NOT NULL CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS ledger_entries_transaction_idx ON ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS ledger_entries_account_idx ON ledger_entries (account, provider_id);
+
+CREATE OR REPLACE FUNCTION ledger_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger rows cannot be changed or deleted';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ledger_transactions_immutable ON ledger_transactions;
+CREATE TRIGGER ledger_transactions_immutable BEFORE UPDATE OR DELETE ON ledger_transactions
+    FOR EACH ROW EXECUTE FUNCTION ledger_immutable();
+
+DROP TRIGGER IF EXISTS ledger_entries_immutable ON ledger_entries;
+CREATE TRIGGER ledger_entries_immutable BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_immutable();
+
+-- Checked at commit, once every entry of the transaction is in.
+CREATE OR REPLACE FUNCTION ledger_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % does not balance', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ledger_entries_balanced ON ledger_entries;
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_balanced();
+
+-- Messages saved with the change they announce and published after
+-- commit, so that neither happens without the other.
+CREATE TABLE IF NOT EXISTS outbox (
+    id           BIGSERIAL PRIMARY KEY,
+    topic        TEXT NOT NULL,
+    message      BYTEA NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
+
+-- The time taken by the active bookings of each provider, since the
+-- bookings service cannot list them by provider. No two slots of a
+-- provider overlap, which keeps a time from being booked twice. Held
+-- slots belong to bookings published before they had an ID.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS booking_slots (
+    booking_id  UUID PRIMARY KEY,
+    provider_id UUID NOT NULL,
+    starts_at   TIMESTAMPTZ NOT NULL,
+    ends_at     TIMESTAMPTZ NOT NULL,
+    held        BOOLEAN NOT NULL DEFAULT FALSE,
+    EXCLUDE USING gist (provider_id WITH =, tstzrange(starts_at, ends_at) WITH &&)
+);
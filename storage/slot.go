@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// BookingSlot is the time a booking takes up with its provider. Held
+// slots belong to bookings that were published before the bookings
+// service gave them an ID, and are known by provider and start instead.
+type BookingSlot struct {
+	BookingID  string
+	ProviderID string
+	Start      time.Time
+	End        time.Time
+	Held       bool
+}
+
+// changeBookingSlots runs change and then publish in one transaction, so
+// the slots change exactly when the booking change is published. A slot
+// that overlaps another of its provider is an ErrConflict, and nothing is
+// published then. The error of publish is returned as it is.
+func (s *Storage) changeBookingSlots(ctx context.Context, change func(tx *sql.Tx) error, publish func() error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = change(tx)
+	if e, ok := err.(*pq.Error); ok && e.Code.Name() == "exclusion_violation" {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := publish(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertBookingSlot(ctx context.Context, tx *sql.Tx, slot *BookingSlot) error {
+	// Slots that are over make way first.
+	_, err := tx.ExecContext(ctx,
+		`DELETE FROM booking_slots WHERE provider_id = $1 AND ends_at <= NOW()`,
+		slot.ProviderID,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO booking_slots (booking_id, provider_id, starts_at, ends_at, held)
+		VALUES ($1, $2, $3, $4, $5)`,
+		slot.BookingID, slot.ProviderID, slot.Start, slot.End, slot.Held,
+	)
+	return err
+}
+
+func deleteBookingSlot(ctx context.Context, tx *sql.Tx, slot *BookingSlot) error {
+	_, err := tx.ExecContext(ctx,
+		`DELETE FROM booking_slots
+		WHERE booking_id = $1 OR (held AND provider_id = $2 AND starts_at = $3)`,
+		slot.BookingID, slot.ProviderID, slot.Start,
+	)
+	return err
+}
+
+// HoldBookingSlot takes up the time of a new booking while publish
+// publishes it.
+func (s *Storage) HoldBookingSlot(ctx context.Context, slot *BookingSlot, publish func() error) error {
+	held := *slot
+	held.Held = true
+
+	return s.changeBookingSlots(ctx, func(tx *sql.Tx) error {
+		return insertBookingSlot(ctx, tx, &held)
+	}, publish)
+}
+
+// MoveBookingSlot moves the time a booking takes up from the slot it had
+// to slot while publish publishes the reschedule. from is the slot the
+// booking had; a held one becomes the booking's own.
+func (s *Storage) MoveBookingSlot(ctx context.Context, from, slot *BookingSlot, publish func() error) error {
+	return s.changeBookingSlots(ctx, func(tx *sql.Tx) error {
+		if err := deleteBookingSlot(ctx, tx, from); err != nil {
+			return err
+		}
+		return insertBookingSlot(ctx, tx, slot)
+	}, publish)
+}
+
+// FreeBookingSlot frees the time of a booking that is no longer active
+// while publish publishes the change.
+func (s *Storage) FreeBookingSlot(ctx context.Context, slot *BookingSlot, publish func() error) error {
+	return s.changeBookingSlots(ctx, func(tx *sql.Tx) error {
+		return deleteBookingSlot(ctx, tx, slot)
+	}, publish)
+}
+
+// ListBookingSlots returns the slots of a provider that overlap the time
+// from to to.
+func (s *Storage) ListBookingSlots(ctx context.Context, providerID string, from, to time.Time) ([]*BookingSlot, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT booking_id, provider_id, starts_at, ends_at, held FROM booking_slots
+		WHERE provider_id = $1 AND tstzrange(starts_at, ends_at) && tstzrange($2, $3)
+		ORDER BY starts_at`,
+		providerID, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slots []*BookingSlot
+	for rows.Next() {
+		var slot BookingSlot
+		if err := rows.Scan(&slot.BookingID, &slot.ProviderID, &slot.Start, &slot.End, &slot.Held); err != nil {
+			return nil, err
+		}
+		slots = append(slots, &slot)
+	}
+
+	return slots, rows.Err()
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"api-gateway/config"
+	"api-gateway/models"
+	"api-gateway/pkg/ledger"
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+//go:embed schema.sql
+var schema string
+
+// ErrNotFound is returned when the requested record does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned when a record clashes with an existing one.
+var ErrConflict = errors.New("conflict")
+
+// IStorage keeps the state the gateway owns itself rather than the
+// backend services.
+type IStorage interface {
+	GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, userID string, prefs *models.NotificationPreferences) error
+	LogDelivery(ctx context.Context, log *models.DeliveryLog) error
+	ListDeliveries(ctx context.Context, userID string, page, limit int32) ([]*models.DeliveryLog, error)
+	ScheduleReminders(ctx context.Context, bookingID string, scheduledTime time.Time, offsets []time.Duration) error
+	CancelReminders(ctx context.Context, bookingID string) error
+	ClaimDueReminders(ctx context.Context, limit int, lease time.Duration) ([]*Reminder, error)
+	FinishReminder(ctx context.Context, r *Reminder, status string) error
+	CreateWebhook(ctx context.Context, w *models.Webhook, role string) error
+	GetWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, userID string, page, limit int32) ([]*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, w *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id string) error
+	EnqueueWebhookEvent(ctx context.Context, event string, payload json.RawMessage, userIDs []string, adminRole string) error
+	RedeliverWebhook(ctx context.Context, d *models.WebhookDelivery) (*models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	ListWebhookDeliveries(ctx context.Context, webhookID, status string, page, limit int32) ([]*models.WebhookDelivery, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*WebhookJob, error)
+	RecordWebhookAttempt(ctx context.Context, deliveryID string, r WebhookResult) error
+	CreatePaymentIntent(ctx context.Context, pi *models.PaymentIntent) error
+	GetPaymentIntent(ctx context.Context, id string) (*models.PaymentIntent, error)
+	GetActivePaymentIntent(ctx context.Context, bookingID string) (*models.PaymentIntent, error)
+	GetPaymentIntentByProcessorID(ctx context.Context, processor, processorIntentID string) (*models.PaymentIntent, error)
+	UpdatePaymentIntent(ctx context.Context, pi *models.PaymentIntent, from string, out ...OutboxMessage) error
+	PublishOutbox(ctx context.Context, limit int, publish func(topic string, message []byte) error) (int, error)
+	RecordPaymentEvent(ctx context.Context, processor, eventID, eventType string) (bool, error)
+	ForgetPaymentEvent(ctx context.Context, processor, eventID string) error
+	CreateRefund(ctx context.Context, r *models.Refund, limit float32) error
+	UpdateRefund(ctx context.Context, r *models.Refund) error
+	RecordCancellation(ctx context.Context, bookingID, userID, role string) error
+	GetCancellation(ctx context.Context, bookingID string) (*Cancellation, error)
+	HoldBookingSlot(ctx context.Context, slot *BookingSlot, publish func() error) error
+	MoveBookingSlot(ctx context.Context, from, slot *BookingSlot, publish func() error) error
+	FreeBookingSlot(ctx context.Context, slot *BookingSlot, publish func() error) error
+	ListBookingSlots(ctx context.Context, providerID string, from, to time.Time) ([]*BookingSlot, error)
+	IssueInvoice(ctx context.Context, bookingID, providerID string) (*InvoiceNumber, error)
+	GetCommissionRate(ctx context.Context, providerID string) (float64, error)
+	SetCommissionRate(ctx context.Context, providerID string, rate float64, updatedBy string) error
+	PostLedgerTransaction(ctx context.Context, t *ledger.Transaction) (bool, error)
+	GetPaymentCommissionRate(ctx context.Context, bookingID string) (float64, error)
+	ListBookingRefunds(ctx context.Context, bookingID string) ([]*models.Refund, error)
+	ProviderEarnings(ctx context.Context, providerID, period string, from, to time.Time, loc *time.Location) ([]models.EarningsPeriod, error)
+	ProviderBalance(ctx context.Context, providerID string) (float32, error)
+	CreatePayoutBatch(ctx context.Context, b *models.PayoutBatch) error
+	GetPayoutBatch(ctx context.Context, id string) (*models.PayoutBatch, error)
+	Close()
+}
+
+type Storage struct {
+	db *sql.DB
+}
+
+// NewStorage connects to the gateway database and creates the tables
+// that are missing.
+func NewStorage(cfg *config.Config) (IStorage, error) {
+	conn := fmt.Sprintf("host=%s port=%d user=%s dbname=%s password=%s sslmode=disable",
+		cfg.DB_HOST, cfg.DB_PORT, cfg.DB_USER, cfg.DB_NAME, cfg.DB_PASSWORD)
+
+	db, err := sql.Open("postgres", conn)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Storage{db: db}
+
+	if _, err := db.Exec(schema); err != nil {
+		return s, errors.Wrap(err, "error creating tables")
+	}
+
+	return s, nil
+}
+
+func (s *Storage) Close() {
+	s.db.Close()
+}
+
+func offset(page, limit int32) int32 {
+	return (page - 1) * limit
+}
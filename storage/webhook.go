@@ -0,0 +1,342 @@
+package storage
+
+import (
+	"api-gateway/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Webhook delivery statuses. A delivery is dead once its attempts ran
+// out; dead deliveries are the dead-letter store of the webhooks.
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookDead      = "dead"
+)
+
+// WebhookJob is a claimed delivery with the subscription it goes to.
+type WebhookJob struct {
+	Delivery models.WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+// WebhookResult is the outcome of an attempt to deliver a job.
+type WebhookResult struct {
+	ResponseStatus int
+	Error          string
+	Duration       time.Duration
+	// Status is the status the delivery moves to, and NextAttemptAt
+	// when it is tried again if it stays pending.
+	Status        string
+	NextAttemptAt time.Time
+}
+
+const webhookColumns = `id, user_id, url, events, active, created_at, updated_at`
+
+func scanWebhook(row interface{ Scan(...any) error }) (*models.Webhook, error) {
+	var w models.Webhook
+	err := row.Scan(&w.ID, &w.UserID, &w.URL, pq.Array(&w.Events), &w.Active, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// CreateWebhook stores a subscription of a user with the given role,
+// filling in its ID and times.
+func (s *Storage) CreateWebhook(ctx context.Context, w *models.Webhook, role string) error {
+	w.ID = uuid.NewString()
+	w.Active = true
+
+	return s.db.QueryRowContext(ctx,
+		`INSERT INTO webhooks (id, user_id, user_role, url, events, secret)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at`,
+		w.ID, w.UserID, role, w.URL, pq.Array(w.Events), w.Secret,
+	).Scan(&w.CreatedAt, &w.UpdatedAt)
+}
+
+// GetWebhook returns a subscription without its secret, or ErrNotFound.
+func (s *Storage) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	if uuid.Validate(id) != nil {
+		return nil, ErrNotFound
+	}
+
+	w, err := scanWebhook(s.db.QueryRowContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`,
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return w, err
+}
+
+// ListWebhooks returns the subscriptions of the user, newest first.
+func (s *Storage) ListWebhooks(ctx context.Context, userID string, page, limit int32) ([]*models.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset(page, limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (s *Storage) UpdateWebhook(ctx context.Context, w *models.Webhook) error {
+	return s.db.QueryRowContext(ctx,
+		`UPDATE webhooks SET url = $2, events = $3, active = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		w.ID, w.URL, pq.Array(w.Events), w.Active,
+	).Scan(&w.UpdatedAt)
+}
+
+// DeleteWebhook deletes a subscription along with its deliveries.
+func (s *Storage) DeleteWebhook(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	return err
+}
+
+// EnqueueWebhookEvent queues a delivery of the event to every active
+// subscription to it of the given users, and of the admins, who get the
+// events of everyone.
+func (s *Storage) EnqueueWebhookEvent(ctx context.Context, event string, payload json.RawMessage, userIDs []string, adminRole string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (id, webhook_id, event, payload)
+		SELECT gen_random_uuid(), id, $1, $2 FROM webhooks
+		WHERE active AND $1 = ANY(events) AND (user_id::text = ANY($3) OR user_role = $4)`,
+		event, []byte(payload), pq.Array(userIDs), adminRole,
+	)
+	return err
+}
+
+// RedeliverWebhook queues a copy of a delivery, which is tried from
+// scratch, and returns it. Copies point to the first delivery of the
+// event, even when a copy is redelivered.
+func (s *Storage) RedeliverWebhook(ctx context.Context, d *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	r := &models.WebhookDelivery{
+		ID:           uuid.NewString(),
+		WebhookID:    d.WebhookID,
+		Event:        d.Event,
+		Payload:      d.Payload,
+		Status:       WebhookPending,
+		RedeliveryOf: d.ID,
+	}
+	if d.RedeliveryOf != "" {
+		r.RedeliveryOf = d.RedeliveryOf
+	}
+
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO webhook_deliveries (id, webhook_id, event, payload, redelivery_of)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING next_attempt_at, created_at`,
+		r.ID, r.WebhookID, r.Event, []byte(r.Payload), r.RedeliveryOf,
+	).Scan(&r.NextAttemptAt, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+const deliveryColumns = `id, webhook_id, event, payload, status, attempts, last_error,
+	next_attempt_at, redelivery_of, created_at, delivered_at`
+
+func scanDelivery(row interface{ Scan(...any) error }) (*models.WebhookDelivery, error) {
+	var (
+		d            models.WebhookDelivery
+		payload      []byte
+		redeliveryOf sql.NullString
+		deliveredAt  sql.NullString
+	)
+
+	err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &payload, &d.Status, &d.Attempts, &d.LastError,
+		&d.NextAttemptAt, &redeliveryOf, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Payload = payload
+	d.RedeliveryOf = redeliveryOf.String
+	d.DeliveredAt = deliveredAt.String
+	if d.Status != WebhookPending {
+		d.NextAttemptAt = ""
+	}
+
+	return &d, nil
+}
+
+// GetWebhookDelivery returns a delivery with the history of its attempts,
+// or ErrNotFound.
+func (s *Storage) GetWebhookDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	if uuid.Validate(id) != nil {
+		return nil, ErrNotFound
+	}
+
+	d, err := scanDelivery(s.db.QueryRowContext(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`,
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT response_status, error, duration_ms, created_at
+		FROM webhook_attempts
+		WHERE delivery_id = $1
+		ORDER BY created_at, id`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.WebhookAttempt
+		if err := rows.Scan(&a.ResponseStatus, &a.Error, &a.DurationMs, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.AttemptHistory = append(d.AttemptHistory, a)
+	}
+
+	return d, rows.Err()
+}
+
+// ListWebhookDeliveries returns the deliveries of a subscription, newest
+// first, only those with the given status if it is set.
+func (s *Storage) ListWebhookDeliveries(ctx context.Context, webhookID, status string, page, limit int32) ([]*models.WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4`,
+		webhookID, status, limit, offset(page, limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// ClaimWebhookDeliveries takes up to limit pending deliveries that are
+// due, to active subscriptions. Like reminders, a claimed delivery is not
+// handed out again until the lease runs out.
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*WebhookJob, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`WITH due AS (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = $1 AND w.active AND d.next_attempt_at <= NOW()
+				AND (d.claimed_until IS NULL OR d.claimed_until < NOW())
+			ORDER BY d.next_attempt_at
+			LIMIT $2
+			FOR UPDATE OF d SKIP LOCKED
+		), claimed AS (
+			UPDATE webhook_deliveries d SET claimed_until = NOW() + make_interval(secs => $3)
+			FROM due WHERE d.id = due.id
+			RETURNING d.id, d.webhook_id, d.event, d.payload, d.attempts, d.redelivery_of, d.created_at
+		)
+		SELECT c.id, c.webhook_id, c.event, c.payload, c.attempts, c.redelivery_of, c.created_at, w.url, w.secret
+		FROM claimed c JOIN webhooks w ON w.id = c.webhook_id`,
+		WebhookPending, limit, lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*WebhookJob
+	for rows.Next() {
+		var (
+			j            WebhookJob
+			payload      []byte
+			redeliveryOf sql.NullString
+		)
+		err := rows.Scan(&j.Delivery.ID, &j.Delivery.WebhookID, &j.Delivery.Event, &payload,
+			&j.Delivery.Attempts, &redeliveryOf, &j.Delivery.CreatedAt, &j.URL, &j.Secret)
+		if err != nil {
+			return nil, err
+		}
+		j.Delivery.Payload = payload
+		j.Delivery.RedeliveryOf = redeliveryOf.String
+		j.Delivery.Status = WebhookPending
+		jobs = append(jobs, &j)
+	}
+
+	return jobs, rows.Err()
+}
+
+// RecordWebhookAttempt logs an attempt to deliver a claimed delivery and
+// moves the delivery on as the result says.
+func (s *Storage) RecordWebhookAttempt(ctx context.Context, deliveryID string, r WebhookResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO webhook_attempts (delivery_id, response_status, error, duration_ms)
+		VALUES ($1, $2, $3, $4)`,
+		deliveryID, r.ResponseStatus, r.Error, r.Duration.Milliseconds(),
+	)
+	if err != nil {
+		return err
+	}
+
+	nextAttemptAt := r.NextAttemptAt
+	if nextAttemptAt.IsZero() {
+		nextAttemptAt = time.Now()
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = $2, attempts = attempts + 1, last_error = $3,
+			next_attempt_at = $4, claimed_until = NULL,
+			delivered_at = CASE WHEN $2 = $5 THEN NOW() ELSE delivered_at END
+		WHERE id = $1`,
+		deliveryID, r.Status, r.Error, nextAttemptAt, WebhookDelivered,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}